@@ -0,0 +1,16 @@
+package slog
+
+// allowFields returns the fields of data on FieldAllowlist, all of them when it is empty.
+func (c Config) allowFields(data map[string]interface{}) map[string]interface{} {
+	if len(c.FieldAllowlist) == 0 {
+		return data
+	}
+
+	allowed := make(map[string]interface{}, len(c.FieldAllowlist))
+	for _, key := range c.FieldAllowlist {
+		if v, ok := data[key]; ok {
+			allowed[key] = v
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFieldAllowlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		emit      func(s *SukiLogger)
+		want      []string
+	}{
+		{
+			name:      "Application log",
+			allowlist: []string{"tracing", "shop"},
+			emit: func(s *SukiLogger) {
+				s.Info("application", Any("order_id", 1), WithTracing("trace", "span"), WithUser("user-1"), WithExperiment("checkout", "b"))
+			},
+			want: []string{"shop", "tracing"},
+		},
+		{
+			name:      "HTTP log",
+			allowlist: []string{"tracing", "http_response"},
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "127.0.0.1", nil, nil, nil, `{"card":"4242"}`), WithHTTPResponse(200, 1, ""), WithTracing("trace", "span"))
+			},
+			want: []string{"http_response", "tracing"},
+		},
+		{
+			name:      "Event log",
+			allowlist: []string{"tracing"},
+			emit: func(s *SukiLogger) {
+				s.Event("event", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+			},
+			want: []string{},
+		},
+		{
+			name: "No allowlist",
+			emit: func(s *SukiLogger) {
+				s.Info("application", Any("order_id", 1), WithTracing("trace", "span"), WithUser("user-1"))
+			},
+			want: []string{"shop", "tracing", "user"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AppName = "shop"
+			c.FieldAllowlist = tt.allowlist
+			logger, buf := newTestLogger(c)
+
+			tt.emit(logger)
+
+			line := decodeLines(t, buf)[0]
+			got := []string{}
+			for key := range line["data"].(map[string]interface{}) {
+				got = append(got, key)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data keys = %v, want %v", got, tt.want)
+			}
+			if line["log_type"] == nil || line["message"] == nil {
+				t.Errorf("envelope fields dropped: %v", line)
+			}
+		})
+	}
+}
+
+func TestFieldAllowlistCloudEvents(t *testing.T) {
+	c := NewProductionConfig()
+	c.CloudEvents.Enabled = true
+	c.FieldAllowlist = []string{"event"}
+	logger, buf := newTestLogger(c)
+
+	logger.Event("event", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"), WithTracing("trace", "span"))
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	if _, ok := data["event"]; !ok || len(data) != 1 {
+		t.Errorf("data = %v, want only event", data)
+	}
+}
@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"math"
+)
+
+// AnomalyInfo is the current value of a metric compared with its baseline.
+// Deviation is the change relative to the baseline, e.g. 0.5 for 50% above it
+// and -0.5 for 50% below it.
+type AnomalyInfo struct {
+	Metric    string  `json:"metric"`
+	Current   float64 `json:"current"`
+	Baseline  float64 `json:"baseline"`
+	Deviation float64 `json:"deviation"`
+}
+
+// WithAnomaly returns the AnomalyInfo of metric with the deviation of current from baseline.
+// A baseline of 0 gives a deviation of 1, or -1, for any other current value.
+func WithAnomaly(metric string, current float64, baseline float64) AnomalyInfo {
+	var deviation float64
+	switch {
+	case baseline != 0:
+		deviation = (current - baseline) / math.Abs(baseline)
+	case current > 0:
+		deviation = 1
+	case current < 0:
+		deviation = -1
+	}
+
+	return AnomalyInfo{
+		Metric:    metric,
+		Current:   current,
+		Baseline:  baseline,
+		Deviation: deviation,
+	}
+}
+
+// Anomaly writes an anomaly log, at warn level when the deviation exceeds
+// AnomalyDeviation either way, at info level otherwise.
+func (s SukiLogger) Anomaly(info AnomalyInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	data["anomaly"] = info
+
+	level := zapcore.InfoLevel
+	if math.Abs(info.Deviation) > s.config.AnomalyDeviation {
+		level = zapcore.WarnLevel
+	}
+
+	if ce := s.sampledLogger("anomaly", alertLevel, level, "anomaly "+info.Metric, data).Check(level, "anomaly "+info.Metric); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestWithAnomaly(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  float64
+		baseline float64
+		want     float64
+	}{
+		{name: "Above the baseline", current: 150, baseline: 100, want: 0.5},
+		{name: "Below the baseline", current: 25, baseline: 100, want: -0.75},
+		{name: "Negative baseline", current: -5, baseline: -10, want: 0.5},
+		{name: "Zero baseline", current: 3, baseline: 0, want: 1},
+		{name: "Zero baseline and current", current: 0, baseline: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithAnomaly("m", tt.current, tt.baseline).Deviation; got != tt.want {
+				t.Errorf("Deviation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnomalyLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		info      AnomalyInfo
+		wantLevel string
+	}{
+		{name: "Within the threshold", threshold: 0.5, info: WithAnomaly("orders", 140, 100), wantLevel: "info"},
+		{name: "At the threshold", threshold: 0.5, info: WithAnomaly("orders", 150, 100), wantLevel: "info"},
+		{name: "Above the threshold", threshold: 0.5, info: WithAnomaly("orders", 160, 100), wantLevel: "warn"},
+		{name: "Below the baseline", threshold: 0.5, info: WithAnomaly("orders", 40, 100), wantLevel: "warn"},
+		{name: "Zero threshold", threshold: 0, info: WithAnomaly("orders", 101, 100), wantLevel: "warn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AnomalyDeviation = tt.threshold
+			logger, buf := newTestLogger(c)
+
+			logger.Anomaly(tt.info)
+
+			line := decodeLines(t, buf)[0]
+			if line["level"] != tt.wantLevel || line["log_type"] != "anomaly" || line["message"] != "anomaly orders" {
+				t.Errorf("got %v %v %q, want %v anomaly log", line["level"], line["log_type"], line["message"], tt.wantLevel)
+			}
+			anomaly := line["data"].(map[string]interface{})["anomaly"].(map[string]interface{})
+			if anomaly["deviation"] != tt.info.Deviation {
+				t.Errorf("deviation = %v, want %v", anomaly["deviation"], tt.info.Deviation)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// AsyncConfig buffers the writes to the output so logging does not wait on it.
+// The buffer is flushed every FlushInterval and whenever a log would take it
+// above FlushBytes, whichever comes first, as well as by Sync, Rotate and Close.
+type AsyncConfig struct {
+	Enabled bool
+	// FlushInterval is the longest a log stays buffered, 30 seconds when 0.
+	FlushInterval time.Duration
+	// FlushBytes is the size of the buffer, 256 KiB when 0. A burst of logs
+	// is flushed as soon as it crosses it rather than waiting for FlushInterval.
+	FlushBytes int
+}
+
+// asyncBuffer is the Async buffer of the output, Stop also stops the ticks of its clock.
+type asyncBuffer struct {
+	*zapcore.BufferedWriteSyncer
+	forwarders *tickForwarders
+}
+
+func (a *asyncBuffer) Stop() error {
+	err := a.BufferedWriteSyncer.Stop()
+	a.forwarders.close()
+	return err
+}
+
+// buffer returns ws buffered as configured and flushed at the intervals of c,
+// or nil when Async is disabled.
+func (a AsyncConfig) buffer(ws zapcore.WriteSyncer, c Clock) *asyncBuffer {
+	if !a.Enabled {
+		return nil
+	}
+	forwarders := newTickForwarders()
+	return &asyncBuffer{
+		BufferedWriteSyncer: &zapcore.BufferedWriteSyncer{
+			WS:            ws,
+			Size:          a.FlushBytes,
+			FlushInterval: a.FlushInterval,
+			Clock:         zapClock{Clock: c, forwarders: forwarders},
+		},
+		forwarders: forwarders,
+	}
+}
@@ -0,0 +1,150 @@
+package slog
+
+import (
+	"fmt"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// chanWriter sends every write to writes.
+type chanWriter struct {
+	writes chan string
+}
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w.writes <- string(p)
+	return len(p), nil
+}
+
+func TestAsyncFlushIntervalUsesClock(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	output := chanWriter{writes: make(chan string, 1)}
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.Output = output
+	c.Async = AsyncConfig{Enabled: true, FlushInterval: time.Minute}
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	logger.Info("buffered")
+	clock.BlockUntil(1)
+	select {
+	case line := <-output.writes:
+		t.Fatalf("written before the interval: %s", line)
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	select {
+	case line := <-output.writes:
+		if !strings.Contains(line, "buffered") {
+			t.Errorf("flushed %q, want the buffered log", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("buffer not flushed once the interval of the clock elapsed")
+	}
+}
+
+func TestAsyncClockTickerStopped(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.Output = io.Discard
+	c.Async = AsyncConfig{Enabled: true, FlushInterval: time.Minute}
+	logger := &SukiLogger{}
+
+	for i := 0; i < 3; i++ {
+		if err := logger.Configure(c); err != nil {
+			t.Fatal(err)
+		}
+		logger.Info("buffered")
+		clock.BlockUntil(1)
+		if pending := clock.Pending(); len(pending) != 1 {
+			t.Fatalf("%d tickers pending after Configure %d, want 1", len(pending), i+1)
+		}
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if pending := clock.Pending(); len(pending) != 0 {
+		t.Errorf("%d tickers pending after Close, want 0", len(pending))
+	}
+}
+
+func TestAsyncFlushBytes(t *testing.T) {
+	c := NewProductionConfig()
+	c.Async = AsyncConfig{Enabled: true, FlushInterval: time.Hour, FlushBytes: 1024}
+	logger, buf := newTestLogger(c)
+	defer logger.Close()
+
+	logger.Info("first")
+	if buf.Len() != 0 {
+		t.Fatalf("written before the buffer was full: %s", buf)
+	}
+
+	written := 1
+	for buf.Len() == 0 && written < 100 {
+		logger.Info(fmt.Sprintf("burst %d", written))
+		written++
+	}
+	if buf.Len() == 0 {
+		t.Fatalf("nothing flushed after %d logs", written)
+	}
+	if buf.Len() > 1024 {
+		t.Errorf("flushed %d bytes, want at most FlushBytes", buf.Len())
+	}
+	if !strings.HasPrefix(buf.String(), `{"level":"info"`) || !strings.Contains(buf.String(), `"message":"first"`) {
+		t.Errorf("flushed = %s, want the first logs", buf)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if lines := decodeLines(t, buf); len(lines) != written {
+		t.Errorf("%d logs after Close, want %d", len(lines), written)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	tests := []struct {
+		name        string
+		async       AsyncConfig
+		wantWritten bool
+	}{
+		{
+			name:        "Disabled",
+			wantWritten: true,
+		},
+		{
+			name:        "Buffered",
+			async:       AsyncConfig{Enabled: true, FlushInterval: time.Hour},
+			wantWritten: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.Async = tt.async
+			logger, buf := newTestLogger(c)
+
+			logger.Info("buffered")
+			if written := buf.Len() > 0; written != tt.wantWritten {
+				t.Errorf("written = %v, want %v", written, tt.wantWritten)
+			}
+
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if lines := decodeLines(t, buf); len(lines) != 1 {
+				t.Errorf("%d logs after Close, want 1", len(lines))
+			}
+		})
+	}
+}
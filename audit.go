@@ -0,0 +1,149 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"sort"
+)
+
+// AuditInfo is the data.audit of an audit log, a change of a resource by an actor.
+type AuditInfo struct {
+	Actor    string      `json:"actor"`
+	Action   string      `json:"action"`
+	Resource string      `json:"resource"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+	// Changes are the changed fields set by Diff, in place of Before and After.
+	Changes []AuditChange `json:"changes,omitempty"`
+}
+
+// AuditChange is a changed field, Field is its dotted path e.g. "address.city",
+// Before is null for an added field and After for a removed one.
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func WithAudit(actor string, action string, resource string, before interface{}, after interface{}) AuditInfo {
+	return AuditInfo{
+		Actor:    actor,
+		Action:   action,
+		Resource: resource,
+		Before:   before,
+		After:    after,
+	}
+}
+
+// Diff returns a with only the fields changed between Before and After, e.g.
+// for update actions. Nested objects are compared field by field, arrays as a
+// whole. Identical states leave no changes. a is returned as is when a state
+// cannot be marshaled to JSON.
+func (a AuditInfo) Diff() AuditInfo {
+	before, err := jsonValue(a.Before)
+	if err != nil {
+		return a
+	}
+	after, err := jsonValue(a.After)
+	if err != nil {
+		return a
+	}
+
+	// A created or deleted resource has all its fields added or removed.
+	if _, ok := after.(map[string]interface{}); ok && before == nil {
+		before = map[string]interface{}{}
+	}
+	if _, ok := before.(map[string]interface{}); ok && after == nil {
+		after = map[string]interface{}{}
+	}
+
+	var changes []AuditChange
+	diffValues(&changes, "", before, after)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	a.Before, a.After, a.Changes = nil, nil, changes
+	return a
+}
+
+// jsonValue returns v as decoded from its JSON, so structs compare like maps.
+// A panicking MarshalJSON is returned as an error.
+func jsonValue(v interface{}) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, fmt.Errorf("marshaling panicked: %v", r)
+		}
+	}()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &value)
+	return value, err
+}
+
+func diffValues(changes *[]AuditChange, path string, before interface{}, after interface{}) {
+	beforeObject, beforeOk := before.(map[string]interface{})
+	afterObject, afterOk := after.(map[string]interface{})
+	if !beforeOk || !afterOk {
+		if !reflect.DeepEqual(before, after) {
+			*changes = append(*changes, AuditChange{Field: path, Before: before, After: after})
+		}
+		return
+	}
+
+	for key, value := range beforeObject {
+		diffValues(changes, joinPath(path, key), value, afterObject[key])
+	}
+	for key, value := range afterObject {
+		if _, ok := beforeObject[key]; !ok {
+			diffValues(changes, joinPath(path, key), nil, value)
+		}
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Audit writes an audit log of info, call info.Diff() to only log the changed fields.
+func (s SukiLogger) Audit(info AuditInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["audit"] = s.safeAudit(info)
+
+	if ce := s.sampledLogger("audit", alertLevel, zapcore.InfoLevel, "audit "+info.Action, data).Check(zapcore.InfoLevel, "audit "+info.Action); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// safeAudit returns info with its states guarded against a panicking MarshalJSON, see safeValue.
+func (s SukiLogger) safeAudit(info AuditInfo) AuditInfo {
+	guard := func(key string, v interface{}) interface{} {
+		if v == nil {
+			return nil
+		}
+		return safeValue{key: key, value: v, report: s.internalError}
+	}
+
+	info.Before = guard("before", info.Before)
+	info.After = guard("after", info.After)
+	if info.Changes != nil {
+		changes := make([]AuditChange, len(info.Changes))
+		for i, change := range info.Changes {
+			changes[i] = AuditChange{
+				Field:  change.Field,
+				Before: guard(change.Field+".before", change.Before),
+				After:  guard(change.Field+".after", change.After),
+			}
+		}
+		info.Changes = changes
+	}
+	return info
+}
@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+type auditAddress struct {
+	City    string `json:"city"`
+	Zipcode string `json:"zipcode"`
+}
+
+type auditCustomer struct {
+	Name    string       `json:"name"`
+	Tags    []string     `json:"tags"`
+	Address auditAddress `json:"address"`
+}
+
+func TestAuditInfoDiff(t *testing.T) {
+	customer := auditCustomer{Name: "Somchai", Tags: []string{"vip"}, Address: auditAddress{City: "Bangkok", Zipcode: "10110"}}
+	moved := customer
+	moved.Address.City = "Chiang Mai"
+	moved.Address.Zipcode = "50000"
+	moved.Tags = []string{"vip", "wholesale"}
+
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   []AuditChange
+	}{
+		{
+			name:   "Nested changes",
+			before: customer,
+			after:  moved,
+			want: []AuditChange{
+				{Field: "address.city", Before: "Bangkok", After: "Chiang Mai"},
+				{Field: "address.zipcode", Before: "10110", After: "50000"},
+				{Field: "tags", Before: []interface{}{"vip"}, After: []interface{}{"vip", "wholesale"}},
+			},
+		},
+		{
+			name:   "Identical states",
+			before: customer,
+			after:  customer,
+			want:   nil,
+		},
+		{
+			name:   "Added and removed fields",
+			before: map[string]interface{}{"status": "active", "note": "call back"},
+			after:  map[string]interface{}{"status": "active", "blocked_by": map[string]interface{}{"id": "admin-1"}},
+			want: []AuditChange{
+				{Field: "blocked_by", Before: nil, After: map[string]interface{}{"id": "admin-1"}},
+				{Field: "note", Before: "call back", After: nil},
+			},
+		},
+		{
+			name:   "Created resource",
+			before: nil,
+			after:  map[string]interface{}{"name": "Somchai"},
+			want: []AuditChange{
+				{Field: "name", Before: nil, After: "Somchai"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WithAudit("admin-1", "update", "customer/1", tt.before, tt.after).Diff()
+
+			if !reflect.DeepEqual(got.Changes, tt.want) {
+				t.Errorf("Changes = %#v, want %#v", got.Changes, tt.want)
+			}
+			if got.Before != nil || got.After != nil {
+				t.Errorf("Before, After = %v, %v, want both nil", got.Before, got.After)
+			}
+		})
+	}
+}
+
+func TestAuditInfoDiffUnmarshalable(t *testing.T) {
+	info := WithAudit("admin-1", "update", "customer/1", make(chan int), map[string]interface{}{"name": "Somchai"})
+
+	if got := info.Diff(); got.Changes != nil || got.After == nil {
+		t.Errorf("Diff() = %+v, want the full states", got)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name string
+		info AuditInfo
+		want map[string]interface{}
+	}{
+		{
+			name: "Full states",
+			info: WithAudit("admin-1", "update", "customer/1", auditCustomer{Name: "Somchai"}, auditCustomer{Name: "Somsak"}),
+			want: map[string]interface{}{
+				"actor":    "admin-1",
+				"action":   "update",
+				"resource": "customer/1",
+				"before":   map[string]interface{}{"name": "Somchai", "tags": nil, "address": map[string]interface{}{"city": "", "zipcode": ""}},
+				"after":    map[string]interface{}{"name": "Somsak", "tags": nil, "address": map[string]interface{}{"city": "", "zipcode": ""}},
+			},
+		},
+		{
+			name: "Diff",
+			info: WithAudit("admin-1", "update", "customer/1", auditCustomer{Name: "Somchai"}, auditCustomer{Name: "Somsak"}).Diff(),
+			want: map[string]interface{}{
+				"actor":    "admin-1",
+				"action":   "update",
+				"resource": "customer/1",
+				"changes": []interface{}{
+					map[string]interface{}{"field": "name", "before": "Somchai", "after": "Somsak"},
+				},
+			},
+		},
+		{
+			name: "Empty diff",
+			info: WithAudit("admin-1", "update", "customer/1", auditCustomer{Name: "Somchai"}, auditCustomer{Name: "Somchai"}).Diff(),
+			want: map[string]interface{}{
+				"actor":    "admin-1",
+				"action":   "update",
+				"resource": "customer/1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.Audit(tt.info, WithTracing("trace", "span"))
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "audit" || line["message"] != "audit update" {
+				t.Errorf("log_type = %v, message = %v", line["log_type"], line["message"])
+			}
+			if got := line["data"].(map[string]interface{})["audit"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("audit = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	AuthzAllow = "allow"
+	AuthzDeny  = "deny"
+)
+
+// AuthzInfo is the data.authz of an authz log, MissingScopes are the required
+// scopes absent from the actual ones.
+type AuthzInfo struct {
+	Decision       string   `json:"decision"`
+	Policy         string   `json:"policy"`
+	RequiredScopes []string `json:"required_scopes,omitempty"`
+	ActualScopes   []string `json:"actual_scopes,omitempty"`
+	MissingScopes  []string `json:"missing_scopes,omitempty"`
+}
+
+// AuthzScopes are the scopes required by the matched policy and those the caller actually had.
+type AuthzScopes struct {
+	Required []string
+	Actual   []string
+}
+
+// ScopeInfo is the data.authz.scopes of an HTTP log, the OAuth scopes required
+// by the route and those granted to the token. Missing are the required scopes not granted.
+type ScopeInfo struct {
+	Required []string `json:"required"`
+	Granted  []string `json:"granted"`
+	Missing  []string `json:"missing,omitempty"`
+}
+
+// httpAuthz is the data.authz of an HTTP log.
+type httpAuthz struct {
+	Scopes ScopeInfo `json:"scopes"`
+}
+
+// UserInfo is the caller on behalf of whom a request is handled.
+type UserInfo struct {
+	ID    string   `json:"id"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func WithAuthzScopes(required []string, actual []string) AuthzScopes {
+	return AuthzScopes{
+		Required: required,
+		Actual:   actual,
+	}
+}
+
+func WithScopes(required []string, granted []string) ScopeInfo {
+	return ScopeInfo{
+		Required: required,
+		Granted:  granted,
+		Missing:  missingScopes(required, granted),
+	}
+}
+
+func WithUser(id string, roles ...string) UserInfo {
+	return UserInfo{
+		ID:    id,
+		Roles: roles,
+	}
+}
+
+// Authz writes an authz log of an authorization decision, AuthzAllow or
+// AuthzDeny, taken by policy. A deny is written at warn level, alerting when
+// Config.AlertOnAuthzDeny is set.
+func (s SukiLogger) Authz(decision string, policy string, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	info := AuthzInfo{Decision: decision, Policy: policy}
+	for i := range args {
+		if scopes, ok := args[i].(AuthzScopes); ok {
+			info.RequiredScopes = scopes.Required
+			info.ActualScopes = scopes.Actual
+			info.MissingScopes = missingScopes(scopes.Required, scopes.Actual)
+		}
+	}
+	data["authz"] = info
+
+	level := zapcore.InfoLevel
+	if decision == AuthzDeny {
+		level = zapcore.WarnLevel
+		if s.config.AlertOnAuthzDeny && alertLevel < LevelAlert {
+			alertLevel = LevelAlert
+		}
+	}
+
+	if ce := s.sampledLogger("authz", alertLevel, level, "authz "+decision, data).Check(level, "authz "+decision); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+func missingScopes(required []string, actual []string) []string {
+	has := make(map[string]bool, len(actual))
+	for _, scope := range actual {
+		has[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range required {
+		if !has[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuthz(t *testing.T) {
+	tests := []struct {
+		name        string
+		alertOnDeny bool
+		decision    string
+		args        []interface{}
+		wantLevel   string
+		wantAlert   float64
+		wantAuthz   map[string]interface{}
+	}{
+		{
+			name:      "Allow",
+			decision:  AuthzAllow,
+			args:      []interface{}{WithAuthzScopes([]string{"orders:read"}, []string{"orders:read"})},
+			wantLevel: "info",
+			wantAuthz: map[string]interface{}{
+				"decision":        "allow",
+				"policy":          "orders.read",
+				"required_scopes": []interface{}{"orders:read"},
+				"actual_scopes":   []interface{}{"orders:read"},
+			},
+		},
+		{
+			name:      "Deny",
+			decision:  AuthzDeny,
+			args:      []interface{}{WithAuthzScopes([]string{"orders:read", "orders:write"}, []string{"orders:read"})},
+			wantLevel: "warn",
+			wantAuthz: map[string]interface{}{
+				"decision":        "deny",
+				"policy":          "orders.read",
+				"required_scopes": []interface{}{"orders:read", "orders:write"},
+				"actual_scopes":   []interface{}{"orders:read"},
+				"missing_scopes":  []interface{}{"orders:write"},
+			},
+		},
+		{
+			name:        "Deny alerting",
+			alertOnDeny: true,
+			decision:    AuthzDeny,
+			wantLevel:   "warn",
+			wantAlert:   1,
+			wantAuthz: map[string]interface{}{
+				"decision": "deny",
+				"policy":   "orders.read",
+			},
+		},
+		{
+			name:        "Allow never alerts",
+			alertOnDeny: true,
+			decision:    AuthzAllow,
+			wantLevel:   "info",
+			wantAuthz: map[string]interface{}{
+				"decision": "allow",
+				"policy":   "orders.read",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AlertOnAuthzDeny = tt.alertOnDeny
+			logger, buf := newTestLogger(c)
+
+			args := append(tt.args, WithTracing("trace-1", "span-1"), WithUser("user-1", "staff"))
+			logger.Authz(tt.decision, "orders.read", args...)
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "authz" || line["level"] != tt.wantLevel || line["alert"] != tt.wantAlert {
+				t.Errorf("log_type, level, alert = %v, %v, %v, want authz, %v, %v", line["log_type"], line["level"], line["alert"], tt.wantLevel, tt.wantAlert)
+			}
+
+			data := line["data"].(map[string]interface{})
+			if !reflect.DeepEqual(data["authz"], tt.wantAuthz) {
+				t.Errorf("authz = %v, want %v", data["authz"], tt.wantAuthz)
+			}
+			if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+				t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+			}
+			if want := map[string]interface{}{"id": "user-1", "roles": []interface{}{"staff"}}; !reflect.DeepEqual(data["user"], want) {
+				t.Errorf("user = %v, want %v", data["user"], want)
+			}
+		})
+	}
+}
+
+func TestHTTPScopes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes ScopeInfo
+		want   map[string]interface{}
+	}{
+		{
+			name:   "Granted",
+			scopes: WithScopes([]string{"orders:read"}, []string{"orders:read", "orders:write"}),
+			want: map[string]interface{}{
+				"required": []interface{}{"orders:read"},
+				"granted":  []interface{}{"orders:read", "orders:write"},
+			},
+		},
+		{
+			name:   "Missing scope",
+			scopes: WithScopes([]string{"orders:read", "orders:delete"}, []string{"orders:read"}),
+			want: map[string]interface{}{
+				"required": []interface{}{"orders:read", "orders:delete"},
+				"granted":  []interface{}{"orders:read"},
+				"missing":  []interface{}{"orders:delete"},
+			},
+		},
+		{
+			name:   "No scope granted",
+			scopes: WithScopes([]string{"orders:read"}, nil),
+			want: map[string]interface{}{
+				"required": []interface{}{"orders:read"},
+				"granted":  nil,
+				"missing":  []interface{}{"orders:read"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.RequestHTTP("http request", WithHTTPRequest("DELETE", "/orders/1", "", nil, nil, nil, ""), WithHTTPResponse(403, 1, ""), tt.scopes)
+			logger.ClientHTTP("http client request", WithHTTPRequest("DELETE", "/orders/1", "", nil, nil, nil, ""), WithHTTPResponse(403, 1, ""), tt.scopes)
+
+			for _, line := range decodeLines(t, buf) {
+				authz := line["data"].(map[string]interface{})["authz"].(map[string]interface{})
+				if !reflect.DeepEqual(authz["scopes"], tt.want) {
+					t.Errorf("%v data.authz.scopes = %v, want %v", line["log_type"], authz["scopes"], tt.want)
+				}
+			}
+		})
+	}
+}
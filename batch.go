@@ -0,0 +1,131 @@
+package slog
+
+import "go.uber.org/zap/zapcore"
+
+// MaxBatchErrors is the number of per-item errors kept in a BatchResult,
+// the remaining errors are only counted in MoreErrors.
+const MaxBatchErrors = 10
+
+type BatchItemError struct {
+	ID    string    `json:"id"`
+	Error ErrorInfo `json:"error"`
+}
+
+type BatchResult struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// ErrorBreakdown counts the item errors by name, including those beyond MaxBatchErrors.
+	ErrorBreakdown map[string]int `json:"error_breakdown,omitempty"`
+	// Errors are the item errors of a batch log, with the ID of their item.
+	Errors []BatchItemError `json:"errors,omitempty"`
+	// SampleErrors are the errors written in the data.batch_result of a request log,
+	// at most MaxBatchErrors of them.
+	SampleErrors []ErrorInfo `json:"sample_errors,omitempty"`
+	MoreErrors   int         `json:"more_errors,omitempty"`
+}
+
+func WithBatchItemError(id string, err ErrorInfo) BatchItemError {
+	return BatchItemError{
+		ID:    id,
+		Error: err,
+	}
+}
+
+// WithBatchResult describes a batch of total items of which succeeded were processed
+// successfully, errors beyond MaxBatchErrors are counted in MoreErrors.
+func WithBatchResult(total int, succeeded int, errors ...BatchItemError) BatchResult {
+	result := BatchResult{
+		Total:     total,
+		Succeeded: succeeded,
+		Failed:    total - succeeded,
+		Errors:    errors,
+	}
+
+	for _, e := range errors {
+		if result.ErrorBreakdown == nil {
+			result.ErrorBreakdown = make(map[string]int)
+		}
+		result.ErrorBreakdown[e.Error.Name]++
+	}
+
+	if len(errors) > MaxBatchErrors {
+		result.Errors = errors[:MaxBatchErrors]
+		result.MoreErrors = len(errors) - MaxBatchErrors
+	}
+
+	for _, e := range result.Errors {
+		result.SampleErrors = append(result.SampleErrors, e.Error)
+	}
+
+	return result
+}
+
+// Batch writes a batch log, at error level when the failure ratio reaches
+// BatchErrorRatio and at warn level for fewer failures.
+func (s SukiLogger) Batch(message string, result BatchResult, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	data["batch"] = s.formatBatchResult(result)
+	level := s.batchLevel(result)
+
+	if ce := s.sampledLogger("batch", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// formatBatchResult returns the data.batch of a batch log, its errors with the ID of their item.
+func (s SukiLogger) formatBatchResult(result BatchResult) BatchResult {
+	errors := make([]BatchItemError, len(result.Errors))
+	for i, e := range result.Errors {
+		errors[i] = BatchItemError{ID: e.ID, Error: s.formatError(e.Error)}
+	}
+	result.Errors = errors
+	result.SampleErrors = nil
+	return result
+}
+
+// formatSampleErrors returns the data.batch_result of a request log, its SampleErrors capped at MaxBatchErrors.
+func (s SukiLogger) formatSampleErrors(result BatchResult) BatchResult {
+	samples := result.SampleErrors
+	if len(samples) > MaxBatchErrors {
+		samples = samples[:MaxBatchErrors]
+	}
+	result.SampleErrors = make([]ErrorInfo, len(samples))
+	for i, e := range samples {
+		result.SampleErrors[i] = s.formatError(e)
+	}
+	result.Errors = nil
+	return result
+}
+
+// batchLevel returns the level of a batch given its failure ratio.
+func (s SukiLogger) batchLevel(result BatchResult) zapcore.Level {
+	if result.Failed <= 0 || result.Total <= 0 {
+		return zapcore.InfoLevel
+	}
+
+	if float64(result.Failed)/float64(result.Total) >= s.config.BatchErrorRatio {
+		return zapcore.ErrorLevel
+	}
+	return zapcore.WarnLevel
+}
+
+// batchArgs adds the BatchResult found in args to data as batch_result and
+// returns the level escalated by its failure ratio, a batch without failure adds nothing.
+func (s SukiLogger) batchArgs(level zapcore.Level, data map[string]interface{}, args []interface{}) zapcore.Level {
+	for i := range args {
+		if result, ok := args[i].(BatchResult); ok {
+			if result.Failed <= 0 {
+				continue
+			}
+			data["batch_result"] = s.formatSampleErrors(result)
+			if l := s.batchLevel(result); l > level {
+				level = l
+			}
+		}
+	}
+	return level
+}
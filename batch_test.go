@@ -0,0 +1,164 @@
+package slog
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWithBatchResult(t *testing.T) {
+	var errors []BatchItemError
+	for i := 0; i < MaxBatchErrors+5; i++ {
+		errors = append(errors, WithBatchItemError(fmt.Sprint(i), WithError("invalid_sku")))
+	}
+
+	result := WithBatchResult(100, 85, errors...)
+	if result.Failed != 15 || len(result.Errors) != MaxBatchErrors || len(result.SampleErrors) != MaxBatchErrors || result.MoreErrors != 5 || result.ErrorBreakdown["invalid_sku"] != 15 {
+		t.Errorf("WithBatchResult() = %+v", result)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    BatchResult
+		wantLevel string
+		wantBatch map[string]interface{}
+	}{
+		{
+			name:      "All success",
+			result:    WithBatchResult(3, 3),
+			wantLevel: "info",
+			wantBatch: map[string]interface{}{
+				"total":     float64(3),
+				"succeeded": float64(3),
+				"failed":    float64(0),
+			},
+		},
+		{
+			name:      "Mixed result",
+			result:    WithBatchResult(3, 2, WithBatchItemError("sku-2", WithError("out_of_stock"))),
+			wantLevel: "warn",
+			wantBatch: map[string]interface{}{
+				"total":     float64(3),
+				"succeeded": float64(2),
+				"failed":    float64(1),
+				"error_breakdown": map[string]interface{}{
+					"out_of_stock": float64(1),
+				},
+				"errors": []interface{}{
+					map[string]interface{}{
+						"id":    "sku-2",
+						"error": map[string]interface{}{"name": "out_of_stock", "stack_trace": ""},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			logger.Batch("import products", tt.result, WithTracing("trace", "span"))
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "batch" || line["level"] != tt.wantLevel {
+				t.Errorf("log_type = %v, level = %v", line["log_type"], line["level"])
+			}
+			if got := line["data"].(map[string]interface{})["batch"]; !reflect.DeepEqual(got, tt.wantBatch) {
+				t.Errorf("batch = %v, want %v", got, tt.wantBatch)
+			}
+		})
+	}
+}
+
+func TestRequestHTTPBatchResult(t *testing.T) {
+	var failures []BatchItemError
+	for i := 0; i < 188; i++ {
+		name := "invalid_sku"
+		if i%2 == 0 {
+			name = "out_of_stock"
+		}
+		failures = append(failures, WithBatchItemError(fmt.Sprint(i), WithError(name)))
+	}
+
+	tests := []struct {
+		name      string
+		result    BatchResult
+		wantLevel string
+	}{
+		{
+			name:      "All success adds no batch_result",
+			result:    WithBatchResult(500, 500),
+			wantLevel: "info",
+		},
+		{
+			name:      "Failure ratio below the threshold",
+			result:    WithBatchResult(500, 312, failures...),
+			wantLevel: "warn",
+		},
+		{
+			name:      "Failure ratio above the threshold",
+			result:    WithBatchResult(200, 12, failures...),
+			wantLevel: "error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			logger.RequestHTTP("import products", HTTPRequestInfo{}, WithHTTPResponse(200, 1, ""), tt.result)
+
+			line := decodeLines(t, buf)[0]
+			if line["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %v", line["level"], tt.wantLevel)
+			}
+
+			batch, ok := line["data"].(map[string]interface{})["batch_result"].(map[string]interface{})
+			if tt.result.Failed == 0 {
+				if ok {
+					t.Errorf("batch_result should be omitted, got %v", batch)
+				}
+				return
+			}
+
+			want := map[string]interface{}{"invalid_sku": float64(94), "out_of_stock": float64(94)}
+			if !reflect.DeepEqual(batch["error_breakdown"], want) {
+				t.Errorf("error_breakdown = %v, want %v", batch["error_breakdown"], want)
+			}
+			samples := batch["sample_errors"].([]interface{})
+			if len(samples) != MaxBatchErrors || batch["more_errors"] != float64(178) {
+				t.Errorf("sample_errors are not capped: %v", batch)
+			}
+			if _, ok := samples[0].(map[string]interface{})["name"]; !ok || batch["errors"] != nil {
+				t.Errorf("sample_errors = %v, errors = %v, want the ErrorInfo of the items only", samples, batch["errors"])
+			}
+		})
+	}
+}
+
+func TestRequestHTTPSampleErrorsCapped(t *testing.T) {
+	result := BatchResult{Total: 20, Failed: 20}
+	for i := 0; i < 20; i++ {
+		result.SampleErrors = append(result.SampleErrors, WithError("invalid_sku"))
+	}
+
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.RequestHTTP("import products", HTTPRequestInfo{}, WithHTTPResponse(200, 1, ""), result)
+
+	batch := decodeLines(t, buf)[0]["data"].(map[string]interface{})["batch_result"].(map[string]interface{})
+	if samples := batch["sample_errors"].([]interface{}); len(samples) != MaxBatchErrors {
+		t.Errorf("%d sample_errors, want %d", len(samples), MaxBatchErrors)
+	}
+}
+
+func TestRequestKafkaBatchResult(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.RequestKafka("consume batch", KafkaMessage{}, WithKafkaResult(1), WithBatchResult(10, 0, WithBatchItemError("1", WithError("invalid"))))
+
+	line := decodeLines(t, buf)[0]
+	if line["level"] != "error" {
+		t.Errorf("level = %v, want error", line["level"])
+	}
+	if _, ok := line["data"].(map[string]interface{})["batch_result"]; !ok {
+		t.Errorf("batch_result is missing from %v", line["data"])
+	}
+}
@@ -0,0 +1,78 @@
+package slog
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// MaxBodyCeiling is the most bytes of a body ever kept for a log, whatever the
+// body size limits of the configuration, a limit of 0 included.
+const MaxBodyCeiling = 16777216
+
+// BodySource is a body read by the logger when the log is written.
+type BodySource interface {
+	io.Reader
+}
+
+// WithBodyReader sets the request body to be read from body when the log is written,
+// up to MaxRequestBodySize bytes. The reader is consumed and never read when the log is
+// filtered out by level or sampling.
+func (r HTTPRequestInfo) WithBodyReader(body io.Reader) HTTPRequestInfo {
+	r.bodyReader = body
+	return r
+}
+
+// WithBodyReader sets the response body to be read from body when the log is written,
+// up to MaxResponseBodySize bytes. The reader is consumed and never read when the log is
+// filtered out by level or sampling.
+func (r HTTPResponseInfo) WithBodyReader(body io.Reader) HTTPResponseInfo {
+	r.bodyReader = body
+	return r
+}
+
+// TeeBody lets a request body stream reach the application while keeping a copy
+// for the log, up to the MaxRequestBodySize of the global logger. The application
+// reads passthrough, the logCopy holds what has been read so far and is meant for WithBodyReader.
+func TeeBody(r io.ReadCloser) (logCopy BodySource, passthrough io.ReadCloser) {
+	return L().TeeBody(r)
+}
+
+// TeeBody is the package TeeBody keeping up to the MaxRequestBodySize of s.
+func (s SukiLogger) TeeBody(r io.ReadCloser) (logCopy BodySource, passthrough io.ReadCloser) {
+	capture := &bodyCapture{ReadCloser: r, limit: captureLimit(s.config.maxRequestBodySize())}
+	return &capture.buf, capture
+}
+
+// bodyLimit returns the number of bytes of a body kept for a maxBodySize limit,
+// MaxBodyCeiling when it is 0 or above.
+func bodyLimit(maxBodySize int) int {
+	if maxBodySize <= 0 || maxBodySize > MaxBodyCeiling {
+		return MaxBodyCeiling
+	}
+	return maxBodySize
+}
+
+// captureLimit keeps one byte over the body size limit so RequestHTTP can still tell the body is too large.
+func captureLimit(maxBodySize int) int {
+	return bodyLimit(maxBodySize) + 1
+}
+
+// readBody reads up to maxBodySize bytes of r, reporting whether more remained.
+// A maxBodySize of 0 reads up to MaxBodyCeiling bytes.
+func readBody(r io.Reader, maxBodySize int) (string, bool) {
+	b, _ := io.ReadAll(io.LimitReader(r, int64(bodyLimit(maxBodySize))+1))
+	return truncateBody(string(b), maxBodySize)
+}
+
+// truncateBody cuts body to the bodyLimit of maxBodySize, reporting whether it
+// was cut. The cut never splits a UTF-8 encoded rune.
+func truncateBody(body string, maxBodySize int) (string, bool) {
+	limit := bodyLimit(maxBodySize)
+	if len(body) <= limit {
+		return body, false
+	}
+	for limit > 0 && !utf8.RuneStart(body[limit]) {
+		limit--
+	}
+	return body[:limit], true
+}
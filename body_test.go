@@ -0,0 +1,294 @@
+package slog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// endlessReader yields an unlimited stream of 'a' and counts the bytes read.
+type endlessReader struct {
+	read int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	r.read += len(p)
+	return len(p), nil
+}
+
+func TestBodyReaderNotConsumedWhenFiltered(t *testing.T) {
+	config := NewProductionConfig()
+	config.LogLevel = LevelWarn
+	logger, buf := newTestLogger(config)
+
+	body := &endlessReader{}
+	logger.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(body), HTTPResponseInfo{})
+
+	if body.read != 0 {
+		t.Errorf("read %d bytes from a filtered log", body.read)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("filtered log was written: %s", buf.String())
+	}
+}
+
+func TestBodyReaderBoundedConsumption(t *testing.T) {
+	config := NewProductionConfig()
+	config.MaxBodySize = 16
+	logger, buf := newTestLogger(config)
+
+	body := &endlessReader{}
+	logger.RequestHTTP(
+		"http",
+		HTTPRequestInfo{}.WithBodyReader(body),
+		HTTPResponseInfo{}.WithBodyReader(strings.NewReader("short")),
+	)
+
+	if body.read > 4096 {
+		t.Errorf("read %d bytes from a huge body", body.read)
+	}
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	request := data["http_request"].(map[string]interface{})
+	if request["body"] != strings.Repeat("a", 16) || request["body_truncated"] != true {
+		t.Errorf("request = %v", request)
+	}
+	response := data["http_response"].(map[string]interface{})
+	if response["body"] != "short" {
+		t.Errorf("response body = %v, want short", response["body"])
+	}
+	if _, ok := response["body_truncated"]; ok {
+		t.Errorf("body_truncated should be omitted, got %v", response["body_truncated"])
+	}
+}
+
+func TestTeeBody(t *testing.T) {
+	logCopy, passthrough := TeeBody(io.NopCloser(strings.NewReader(`{"id":1}`)))
+
+	app, _ := io.ReadAll(passthrough)
+	if string(app) != `{"id":1}` {
+		t.Errorf("application read %q", app)
+	}
+
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(logCopy), HTTPResponseInfo{})
+
+	request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+	if request["body"] != `{"id":1}` {
+		t.Errorf("logged body = %v", request["body"])
+	}
+}
+
+func TestTeeBodyUsesConfigLimit(t *testing.T) {
+	config := NewProductionConfig()
+	config.MaxRequestBodySize = 8
+	logger, buf := newTestLogger(config)
+
+	logCopy, passthrough := logger.TeeBody(io.NopCloser(strings.NewReader(strings.Repeat("a", 20))))
+	app, _ := io.ReadAll(passthrough)
+	if len(app) != 20 {
+		t.Errorf("application read %d bytes, want 20", len(app))
+	}
+
+	logger.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(logCopy), HTTPResponseInfo{})
+
+	request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+	if request["body"] != strings.Repeat("a", 8) || request["body_truncated"] != true {
+		t.Errorf("request = %v", request)
+	}
+}
+
+func TestBodyReaderCeiling(t *testing.T) {
+	config := NewProductionConfig()
+	config.MaxBodySize = 0
+	logger, buf := newTestLogger(config)
+
+	body := &endlessReader{}
+	logger.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(body), HTTPResponseInfo{})
+
+	if body.read > MaxBodyCeiling+65536 {
+		t.Errorf("read %d bytes from an endless body", body.read)
+	}
+	request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+	if len(request["body"].(string)) != MaxBodyCeiling || request["body_truncated"] != true {
+		t.Errorf("body of %d bytes, body_truncated = %v", len(request["body"].(string)), request["body_truncated"])
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxBodySize   int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "Within the limit", body: "short", maxBodySize: 8, want: "short"},
+		{name: "ASCII", body: "0123456789", maxBodySize: 4, want: "0123", wantTruncated: true},
+		{name: "Rune not split", body: "สวัสดี", maxBodySize: 4, want: "ส", wantTruncated: true},
+		{name: "No limit below the ceiling", body: "0123456789", maxBodySize: 0, want: "0123456789"},
+		{name: "Ceiling", body: strings.Repeat("a", MaxBodyCeiling+1), maxBodySize: 0, want: strings.Repeat("a", MaxBodyCeiling), wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncateBody(tt.body, tt.maxBodySize)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("truncateBody() = %q, %v, want %q, %v", got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestBodyLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		bodyLevel   LogLevel
+		emit        func(s *SukiLogger)
+		wantBody    string
+		wantOmitted interface{}
+	}{
+		{
+			name: "Default keeps the bodies",
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "", nil, nil, nil, "ping"), WithHTTPResponse(200, 10, "pong"))
+			},
+			wantBody:    "ping",
+			wantOmitted: nil,
+		},
+		{
+			name:      "Below the level",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "", nil, nil, nil, "ping"), WithHTTPResponse(200, 10, "pong"))
+			},
+			wantBody:    "",
+			wantOmitted: true,
+		},
+		{
+			name:      "Body reader below the level",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(strings.NewReader("ping")), HTTPResponseInfo{}.WithBodyReader(strings.NewReader("pong")))
+			},
+			wantBody:    "",
+			wantOmitted: true,
+		},
+		{
+			name:      "At the level",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "", nil, nil, nil, "ping"), WithHTTPResponse(200, 500, "pong"))
+			},
+			wantBody:    "ping",
+			wantOmitted: nil,
+		},
+		{
+			name:      "Empty bodies are not flagged",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP("http", WithHTTPRequest("GET", "/orders", "", nil, nil, nil, ""), WithHTTPResponse(200, 10, ""))
+			},
+			wantBody:    "",
+			wantOmitted: nil,
+		},
+		{
+			name:      "Client request below the level",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				s.ClientHTTP("http", WithHTTPRequest("POST", "/orders", "", nil, nil, nil, "ping"), WithHTTPResponse(200, 10, "pong"))
+			},
+			wantBody:    "",
+			wantOmitted: true,
+		},
+		{
+			name:      "Middleware below the level",
+			bodyLevel: LevelWarn,
+			emit: func(s *SukiLogger) {
+				handler := s.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					io.ReadAll(r.Body)
+					w.Write([]byte("pong"))
+				}))
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("ping")))
+			},
+			wantBody:    "",
+			wantOmitted: true,
+		},
+		{
+			name:      "Middleware debug request",
+			bodyLevel: LevelError,
+			emit: func(s *SukiLogger) {
+				handler := s.HTTPMiddleware(MiddlewareOption{DebugHeader: "X-Debug", DebugTrusted: func(*http.Request) bool { return true }})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					io.ReadAll(r.Body)
+					w.Write([]byte("pong"))
+				}))
+				r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("ping"))
+				r.Header.Set("X-Debug", "true")
+				handler.ServeHTTP(httptest.NewRecorder(), r)
+			},
+			wantBody:    "ping",
+			wantOmitted: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.BodyLevel = tt.bodyLevel
+			c.SlowThresholdMs = map[string]float64{"handler.http": 100}
+			logger, buf := newTestLogger(c)
+
+			tt.emit(logger)
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			request := data["http_request"].(map[string]interface{})
+			response := data["http_response"].(map[string]interface{})
+			if request["body"] != tt.wantBody || request["body_omitted"] != tt.wantOmitted {
+				t.Errorf("request body, body_omitted = %q, %v, want %q, %v", request["body"], request["body_omitted"], tt.wantBody, tt.wantOmitted)
+			}
+			if response["body_omitted"] != tt.wantOmitted {
+				t.Errorf("response body_omitted = %v, want %v", response["body_omitted"], tt.wantOmitted)
+			}
+		})
+	}
+}
+
+func TestRequestBodyOnError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantBody    string
+		wantOmitted interface{}
+	}{
+		{name: "Success drops the request body", status: http.StatusOK, wantBody: "", wantOmitted: true},
+		{name: "Failure keeps the request body", status: http.StatusInternalServerError, wantBody: "body is too large", wantOmitted: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.RequestBodyOnError = true
+			c.MaxBodySize = 2
+			logger, buf := newTestLogger(c)
+			handler := logger.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+				w.Write([]byte("po"))
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("ping")))
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			request := data["http_request"].(map[string]interface{})
+			response := data["http_response"].(map[string]interface{})
+			if request["body"] != tt.wantBody || request["body_omitted"] != tt.wantOmitted {
+				t.Errorf("request body, body_omitted = %q, %v, want %q, %v", request["body"], request["body_omitted"], tt.wantBody, tt.wantOmitted)
+			}
+			if response["body"] != "po" {
+				t.Errorf("response body = %v, want po", response["body"])
+			}
+		})
+	}
+}
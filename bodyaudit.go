@@ -0,0 +1,156 @@
+package slog
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBodyAuditBudget is the time a body audit may take when MiddlewareOption.BodyAuditBudget is not set.
+const DefaultBodyAuditBudget = time.Millisecond
+
+// BodyAuditSpec is the set of JSON fields expected in the request body of a route.
+// Nested fields are written as "parent.child", the fields of the objects of an
+// array included. The fields of a nested object are only checked when the spec
+// lists some of them.
+type BodyAuditSpec struct {
+	Expected []string
+	Required []string
+}
+
+// BodyAudit is the data.body_audit of a handler.http log whose request body
+// does not conform to its BodyAuditSpec.
+type BodyAudit struct {
+	UnexpectedFields []string `json:"unexpected_fields,omitempty"`
+	MissingRequired  []string `json:"missing_required,omitempty"`
+}
+
+var bodyAudits = struct {
+	sync.RWMutex
+	specs map[string]BodyAuditSpec
+}{specs: map[string]BodyAuditSpec{}}
+
+// RegisterBodyAudit registers the spec the request bodies of route are audited
+// against by HTTPMiddleware when MiddlewareOption.BodyAuditRate is set. route is
+// the ServeMux pattern of the handler, or the request path for other handlers.
+func RegisterBodyAudit(route string, spec BodyAuditSpec) {
+	bodyAudits.Lock()
+	defer bodyAudits.Unlock()
+	bodyAudits.specs[route] = spec
+}
+
+func bodyAuditSpec(route string) (BodyAuditSpec, bool) {
+	bodyAudits.RLock()
+	defer bodyAudits.RUnlock()
+	spec, ok := bodyAudits.specs[route]
+	return spec, ok
+}
+
+// auditBody compares the top-level and nested fields of body to spec, ok is
+// false when the body conforms, is not a JSON object or the budget ran out.
+func auditBody(body string, spec BodyAuditSpec, budget time.Duration, now func() time.Time) (BodyAudit, bool) {
+	deadline := now().Add(budget)
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &object); err != nil || now().After(deadline) {
+		return BodyAudit{}, false
+	}
+
+	expected := make(map[string]bool, len(spec.Expected))
+	nested := make(map[string]bool)
+	for _, field := range append(spec.Expected, spec.Required...) {
+		expected[field] = true
+		if i := strings.Index(field, "."); i >= 0 {
+			nested[field[:i]] = true
+		}
+	}
+
+	present := make(map[string]bool)
+	var audit BodyAudit
+	for key, value := range object {
+		present[key] = true
+		if !expected[key] {
+			audit.UnexpectedFields = append(audit.UnexpectedFields, key)
+		}
+		if !nested[key] {
+			continue
+		}
+
+		for _, child := range nestedKeys(value) {
+			field := key + "." + child
+			if !present[field] && !expected[field] {
+				audit.UnexpectedFields = append(audit.UnexpectedFields, field)
+			}
+			present[field] = true
+		}
+
+		if now().After(deadline) {
+			return BodyAudit{}, false
+		}
+	}
+
+	for _, field := range spec.Required {
+		if !present[field] {
+			audit.MissingRequired = append(audit.MissingRequired, field)
+		}
+	}
+
+	if len(audit.UnexpectedFields) == 0 && len(audit.MissingRequired) == 0 {
+		return BodyAudit{}, false
+	}
+	sort.Strings(audit.UnexpectedFields)
+	sort.Strings(audit.MissingRequired)
+	return audit, true
+}
+
+// nestedKeys returns the keys of an object, or of the objects of an array.
+func nestedKeys(value interface{}) []string {
+	var keys []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case []interface{}:
+		for _, item := range v {
+			if object, ok := item.(map[string]interface{}); ok {
+				for k := range object {
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// sampler returns the source of the body audit samples, seeded with SampleSeed
+// when set, safe for concurrent use.
+func (c Config) sampler() func() float64 {
+	if c.SampleSeed == 0 {
+		return rand.Float64
+	}
+
+	var mu sync.Mutex
+	source := rand.New(rand.NewSource(c.SampleSeed))
+	return func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return source.Float64()
+	}
+}
+
+// requestIDSample returns the number in [0, 1) of requestID hashed with seed,
+// the same for every call.
+func requestIDSample(seed int64, requestID string) float64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(seed))
+	h.Write(b[:])
+	h.Write([]byte(requestID))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
@@ -0,0 +1,247 @@
+package slog
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditBody(t *testing.T) {
+	spec := BodyAuditSpec{
+		Expected: []string{"name", "customer", "customer.email", "items", "items.sku", "items.qty"},
+		Required: []string{"name", "customer.email"},
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		want   BodyAudit
+		wantOK bool
+	}{
+		{
+			name:   "Conforming body",
+			body:   `{"name":"a","customer":{"email":"a@b.c"},"items":[{"sku":"1","qty":2}]}`,
+			wantOK: false,
+		},
+		{
+			name:   "Unexpected top-level field",
+			body:   `{"name":"a","customer":{"email":"a@b.c"},"coupon":"X"}`,
+			want:   BodyAudit{UnexpectedFields: []string{"coupon"}},
+			wantOK: true,
+		},
+		{
+			name: "Nested fields",
+			body: `{"customer":{"phone":"1"}}`,
+			want: BodyAudit{
+				UnexpectedFields: []string{"customer.phone"},
+				MissingRequired:  []string{"customer.email", "name"},
+			},
+			wantOK: true,
+		},
+		{
+			name:   "Fields of array items",
+			body:   `{"name":"a","customer":{"email":"a@b.c"},"items":[{"sku":"1","price":2},{"sku":"2","price":3}]}`,
+			want:   BodyAudit{UnexpectedFields: []string{"items.price"}},
+			wantOK: true,
+		},
+		{
+			name:   "Unchecked nested object",
+			body:   `{"name":"a","customer":{"email":"a@b.c"},"meta":{"anything":1}}`,
+			want:   BodyAudit{UnexpectedFields: []string{"meta"}},
+			wantOK: true,
+		},
+		{
+			name:   "Not a JSON object",
+			body:   `[1,2]`,
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := auditBody(tt.body, spec, time.Second, time.Now)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditBody() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// createOrder reads the request body for the middleware to capture it.
+func createOrder(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.ReadAll(r.Body)
+}
+
+func TestHTTPMiddlewareBodyAudit(t *testing.T) {
+	RegisterBodyAudit("/audited/orders", BodyAuditSpec{Expected: []string{"sku"}, Required: []string{"sku"}})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audited/orders", createOrder)
+	mux.HandleFunc("/unregistered", createOrder)
+
+	tests := []struct {
+		name      string
+		path      string
+		rate      float64
+		wantAudit bool
+	}{
+		{
+			name:      "Registered route",
+			path:      "/audited/orders",
+			rate:      1,
+			wantAudit: true,
+		},
+		{
+			name: "Audit disabled",
+			path: "/audited/orders",
+			rate: 0,
+		},
+		{
+			name: "Route without registration",
+			path: "/unregistered",
+			rate: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			handler := logger.HTTPMiddleware(MiddlewareOption{BodyAuditRate: tt.rate})(mux)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"legacy_id":1}`)))
+
+			audit, ok := decodeLines(t, buf)[0]["data"].(map[string]interface{})["body_audit"]
+			if ok != tt.wantAudit {
+				t.Fatalf("body_audit = %v, want present %v", audit, tt.wantAudit)
+			}
+			if ok && !reflect.DeepEqual(audit, map[string]interface{}{
+				"unexpected_fields": []interface{}{"legacy_id"},
+				"missing_required":  []interface{}{"sku"},
+			}) {
+				t.Errorf("unexpected body_audit %v", audit)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareBodyAuditSampling(t *testing.T) {
+	RegisterBodyAudit("/sampled", BodyAuditSpec{Required: []string{"sku"}})
+
+	audited := func(seed int64) []bool {
+		logger, buf := newTestLogger(NewProductionConfig())
+		handler := logger.HTTPMiddleware(MiddlewareOption{
+			BodyAuditRate:   0.5,
+			BodyAuditSample: rand.New(rand.NewSource(seed)).Float64,
+		})(http.HandlerFunc(createOrder))
+
+		for i := 0; i < 20; i++ {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/sampled", strings.NewReader(`{}`)))
+		}
+
+		var result []bool
+		for _, line := range decodeLines(t, buf) {
+			_, ok := line["data"].(map[string]interface{})["body_audit"]
+			result = append(result, ok)
+		}
+		return result
+	}
+
+	first, second := audited(42), audited(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("sampling with the same seed differs: %v and %v", first, second)
+	}
+
+	count := 0
+	for _, ok := range first {
+		if ok {
+			count++
+		}
+	}
+	if count == 0 || count == len(first) {
+		t.Errorf("%d of %d requests audited, want a sample", count, len(first))
+	}
+}
+
+func TestHTTPMiddlewareBodyAuditSampleSeed(t *testing.T) {
+	RegisterBodyAudit("/seeded", BodyAuditSpec{Required: []string{"sku"}})
+
+	audited := func(seed int64, requestIDs ...string) []bool {
+		c := NewProductionConfig()
+		c.SampleSeed = seed
+		logger, buf := newTestLogger(c)
+		handler := logger.HTTPMiddleware(MiddlewareOption{
+			BodyAuditRate:            0.5,
+			BodyAuditRequestIDHeader: "X-Request-ID",
+		})(http.HandlerFunc(createOrder))
+
+		for i := 0; i < 20; i++ {
+			r := httptest.NewRequest(http.MethodPost, "/seeded", strings.NewReader(`{}`))
+			if len(requestIDs) > 0 {
+				r.Header.Set("X-Request-ID", requestIDs[i%len(requestIDs)])
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+		}
+
+		var result []bool
+		for _, line := range decodeLines(t, buf) {
+			_, ok := line["data"].(map[string]interface{})["body_audit"]
+			result = append(result, ok)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		requestIDs []string
+	}{
+		{name: "Seeded source"},
+		{name: "Request IDs", requestIDs: []string{"req-1", "req-2", "req-3", "req-4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := audited(42, tt.requestIDs...), audited(42, tt.requestIDs...)
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("sampling with the same seed differs: %v and %v", first, second)
+			}
+		})
+	}
+
+	t.Run("Same request ID", func(t *testing.T) {
+		decisions := audited(42, "req-1")
+		for i := range decisions {
+			if decisions[i] != decisions[0] {
+				t.Fatalf("request req-1 got decisions %v, want the same every time", decisions)
+			}
+		}
+	})
+}
+
+func TestRequestIDSample(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed      int64
+		requestID string
+	}{
+		{name: "Unseeded", requestID: "req-1"},
+		{name: "Seeded", seed: 42, requestID: "req-1"},
+		{name: "Empty request ID", seed: 42},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestIDSample(tt.seed, tt.requestID)
+			if got < 0 || got >= 1 {
+				t.Errorf("requestIDSample() = %v, want a number in [0, 1)", got)
+			}
+			if again := requestIDSample(tt.seed, tt.requestID); again != got {
+				t.Errorf("requestIDSample() = %v then %v, want the same", got, again)
+			}
+		})
+	}
+
+	if requestIDSample(1, "req-1") == requestIDSample(2, "req-1") {
+		t.Errorf("the seed does not change the sample")
+	}
+}
@@ -0,0 +1,118 @@
+package slog
+
+import (
+	"context"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+type requestBufferKey struct{}
+
+// RequestBuffer collects the logs of a single request and writes them as one
+// request_trace log on Flush, preserving their order and levels.
+type RequestBuffer struct {
+	logger  *SukiLogger
+	mu      sync.Mutex
+	entries []BufferedEntry
+}
+
+type BufferedEntry struct {
+	Level     string                 `json:"level"`
+	Timestamp time.Time              `json:"timestamp"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+func (s *SukiLogger) NewRequestBuffer() *RequestBuffer {
+	return &RequestBuffer{logger: s}
+}
+
+// ContextWithRequestBuffer returns a copy of ctx carrying b.
+func ContextWithRequestBuffer(ctx context.Context, b *RequestBuffer) context.Context {
+	return context.WithValue(ctx, requestBufferKey{}, b)
+}
+
+// RequestBufferFromContext returns the RequestBuffer carried by ctx, if any.
+func RequestBufferFromContext(ctx context.Context) (*RequestBuffer, bool) {
+	b, ok := ctx.Value(requestBufferKey{}).(*RequestBuffer)
+	return b, ok
+}
+
+func (b *RequestBuffer) Debug(message string, args ...interface{}) {
+	b.add(zapcore.DebugLevel, message, args)
+}
+
+func (b *RequestBuffer) Info(message string, args ...interface{}) {
+	b.add(zapcore.InfoLevel, message, args)
+}
+
+func (b *RequestBuffer) Warn(message string, args ...interface{}) {
+	b.add(zapcore.WarnLevel, message, args)
+}
+
+func (b *RequestBuffer) Error(message string, args ...interface{}) {
+	b.add(zapcore.ErrorLevel, message, args)
+}
+
+func (b *RequestBuffer) add(level zapcore.Level, message string, args []interface{}) {
+	if !b.logger.zapInstance.Core().Enabled(level) {
+		return
+	}
+
+	var fields map[string]interface{}
+	add := func(field LogField) {
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		fields[field.Key] = safeValue{key: field.Key, value: field.Value, report: b.logger.internalError}
+	}
+	for i := range args {
+		if field, ok := args[i].(LogField); ok {
+			add(field)
+		} else if list, ok := args[i].([]LogField); ok {
+			for _, field := range list {
+				add(field)
+			}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, BufferedEntry{
+		Level:     level.String(),
+		Timestamp: b.logger.clock().Now(),
+		Message:   message,
+		Fields:    fields,
+	})
+}
+
+// Flush writes the collected logs as a single request_trace log at the highest
+// level collected and empties the buffer, nothing is written when it is empty.
+func (b *RequestBuffer) Flush(message string, args ...interface{}) {
+	b.mu.Lock()
+	entries := b.entries
+	b.entries = nil
+	b.mu.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	level := zapcore.InfoLevel
+	for _, entry := range entries {
+		var l zapcore.Level
+		if err := l.Set(entry.Level); err == nil && l > level {
+			level = l
+		}
+	}
+
+	data := make(map[string]interface{})
+	alertLevel := b.logger.requestArgs(data, args)
+	data["entries"] = entries
+
+	if ce := b.logger.sampledLogger("request_trace", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(b.logger.envelope(alertLevel, data)...)
+	}
+}
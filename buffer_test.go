@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestBuffer(t *testing.T) {
+	config := NewProductionConfig()
+	config.LogLevel = LevelDebug
+	logger, buf := newTestLogger(config)
+
+	ctx := ContextWithRequestBuffer(context.Background(), logger.NewRequestBuffer())
+	b, ok := RequestBufferFromContext(ctx)
+	if !ok {
+		t.Fatal("RequestBufferFromContext() found no buffer")
+	}
+
+	b.Debug("parse request", Any("order_id", 1))
+	b.Info("load order")
+	b.Warn("stock is low", Any("remaining", 2))
+	if buf.Len() != 0 {
+		t.Fatalf("buffered logs were written before Flush: %s", buf.String())
+	}
+
+	b.Flush("request completed", WithTracing("trace", "span"))
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+	if lines[0]["log_type"] != "request_trace" || lines[0]["level"] != "warn" {
+		t.Errorf("log_type = %v, level = %v", lines[0]["log_type"], lines[0]["level"])
+	}
+
+	data := lines[0]["data"].(map[string]interface{})
+	if data["tracing"].(map[string]interface{})["trace_id"] != "trace" {
+		t.Errorf("tracing = %v", data["tracing"])
+	}
+
+	entries := data["entries"].([]interface{})
+	want := []struct{ level, message string }{
+		{"debug", "parse request"},
+		{"info", "load order"},
+		{"warn", "stock is low"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		entry := entries[i].(map[string]interface{})
+		if entry["level"] != w.level || entry["message"] != w.message {
+			t.Errorf("entries[%d] = %v, want %v", i, entry, w)
+		}
+	}
+	if entries[0].(map[string]interface{})["fields"].(map[string]interface{})["order_id"] != float64(1) {
+		t.Errorf("entries[0] fields = %v", entries[0])
+	}
+}
+
+func TestRequestBufferRespectsLevelAndEmptyFlush(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	b := logger.NewRequestBuffer()
+
+	b.Debug("not enabled")
+	b.Flush("request completed")
+	if buf.Len() != 0 {
+		t.Errorf("empty buffer wrote %s", buf.String())
+	}
+
+	if _, ok := RequestBufferFromContext(context.Background()); ok {
+		t.Error("RequestBufferFromContext() found a buffer in an empty context")
+	}
+}
@@ -0,0 +1,30 @@
+package slog
+
+// buildAppName and buildVersion are registered by SetBuildInfo.
+var (
+	buildAppName string
+	buildVersion string
+)
+
+// SetBuildInfo registers the application name and version of the build, usually
+// set with -ldflags "-X main.version=...", as the defaults of L() and Configure.
+// It should be called at the start of main before the logger is used.
+//
+// A non-empty Config.AppName or Config.Version passed to Configure takes
+// precedence, NewProductionConfig returns the registered values in place of its
+// own defaults. An empty argument leaves the corresponding default unchanged.
+func SetBuildInfo(appName string, version string) {
+	buildAppName = appName
+	buildVersion = version
+}
+
+// withBuildInfo fills the AppName and Version left empty in c from SetBuildInfo.
+func withBuildInfo(c Config) Config {
+	if c.AppName == "" {
+		c.AppName = buildAppName
+	}
+	if c.Version == "" {
+		c.Version = buildVersion
+	}
+	return c
+}
@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestSetBuildInfo(t *testing.T) {
+	defer SetBuildInfo("", "")
+
+	tests := []struct {
+		name        string
+		appName     string
+		version     string
+		config      func() Config
+		wantAppName string
+		wantVersion string
+	}{
+		{
+			name:        "Without build info",
+			config:      NewProductionConfig,
+			wantAppName: "application",
+			wantVersion: "1.0.0",
+		},
+		{
+			name:        "Build info replaces production defaults",
+			appName:     "order-service",
+			version:     "v1.4.2",
+			config:      NewProductionConfig,
+			wantAppName: "order-service",
+			wantVersion: "v1.4.2",
+		},
+		{
+			name:    "Config takes precedence",
+			appName: "order-service",
+			version: "v1.4.2",
+			config: func() Config {
+				c := NewProductionConfig()
+				c.Version = "v2.0.0"
+				return c
+			},
+			wantAppName: "order-service",
+			wantVersion: "v2.0.0",
+		},
+		{
+			name:        "Empty config fields use build info",
+			appName:     "order-service",
+			version:     "v1.4.2",
+			config:      func() Config { return Config{} },
+			wantAppName: "order-service",
+			wantVersion: "v1.4.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetBuildInfo(tt.appName, tt.version)
+			logger, buf := newTestLogger(tt.config())
+			logger.Warn("build info")
+
+			line := decodeLines(t, buf)[0]
+			if line["app_name"] != tt.wantAppName || line["version"] != tt.wantVersion {
+				t.Errorf("app_name, version = %v, %v, want %v, %v", line["app_name"], line["version"], tt.wantAppName, tt.wantVersion)
+			}
+		})
+	}
+}
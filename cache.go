@@ -0,0 +1,40 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// CacheStats is the data.cache of a cache_stats log, the state of an in-process cache.
+type CacheStats struct {
+	Name     string `json:"name"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+	// Evictions is the number of entries evicted since the cache was created.
+	Evictions int64 `json:"evictions"`
+	// HitRate is the ratio of lookups served from the cache, from 0 to 1.
+	HitRate float64 `json:"hit_rate"`
+}
+
+// CacheStatsLog writes a cache_stats log of the stats returned by stats every
+// interval until the returned stop func is called, e.g.
+//
+//	stop := slog.L().CacheStatsLog(time.Minute, func() slog.CacheStats {
+//		return slog.CacheStats{Name: "products", Size: c.Len(), Capacity: c.Cap(), Evictions: c.Evictions(), HitRate: c.HitRate()}
+//	})
+//	defer stop()
+func (s *SukiLogger) CacheStatsLog(interval time.Duration, stats func() CacheStats, args ...interface{}) (stop func()) {
+	e := s.startPeriodic("cache stats", interval, func() { s.writeCacheStats(stats(), args) })
+	return e.stop
+}
+
+func (s SukiLogger) writeCacheStats(stats CacheStats, args []interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["cache"] = stats
+
+	if ce := s.sampledLogger("cache_stats", alertLevel, zapcore.InfoLevel, "cache stats", data).Check(zapcore.InfoLevel, "cache stats"); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
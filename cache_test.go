@@ -0,0 +1,78 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheStatsLog(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	var evictions int64
+	stop := logger.CacheStatsLog(time.Minute, func() CacheStats {
+		evictions += 10
+		return CacheStats{Name: "products", Size: 1000, Capacity: 1000, Evictions: evictions, HitRate: 0.75}
+	}, WithTracing("trace-1", "span-1"))
+
+	clock.Advance(59 * time.Second)
+	if lines := decodeLines(t, buf); len(lines) != 0 {
+		t.Fatalf("%d logs before the first interval, want 0", len(lines))
+	}
+
+	clock.Advance(2*time.Minute + time.Second)
+	stop()
+	stop()
+	clock.Advance(time.Hour)
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 3 {
+		t.Fatalf("%d logs, want 3", len(lines))
+	}
+	for i, line := range lines {
+		if line["log_type"] != "cache_stats" || line["level"] != "info" || line["message"] != "cache stats" {
+			t.Errorf("log_type, level, message = %v, %v, %v, want cache_stats, info, cache stats", line["log_type"], line["level"], line["message"])
+		}
+		if want := time.Date(2024, 3, 1, 12, i+1, 0, 0, time.UTC).Format("2006-01-02T15:04:05.000Z"); line["timestamp"] != want {
+			t.Errorf("timestamp = %v, want %v", line["timestamp"], want)
+		}
+
+		data := line["data"].(map[string]interface{})
+		want := map[string]interface{}{
+			"name":      "products",
+			"size":      float64(1000),
+			"capacity":  float64(1000),
+			"evictions": float64(10 * (i + 1)),
+			"hit_rate":  0.75,
+		}
+		if !reflect.DeepEqual(data["cache"], want) {
+			t.Errorf("cache = %v, want %v", data["cache"], want)
+		}
+		if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+			t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+		}
+	}
+}
+
+func TestCacheStatsLogStopBeforeFirst(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	calls := 0
+	stop := logger.CacheStatsLog(time.Minute, func() CacheStats {
+		calls++
+		return CacheStats{}
+	})
+	stop()
+	clock.Advance(time.Hour)
+
+	if calls != 0 || buf.Len() != 0 {
+		t.Errorf("stats called %d times and %q written after stop, want none", calls, buf)
+	}
+}
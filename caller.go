@@ -0,0 +1,30 @@
+package slog
+
+import "go.uber.org/zap/zapcore"
+
+// CallerInfo overrides the caller of a log, see Caller.
+type CallerInfo struct {
+	File string
+	Line int
+}
+
+// Caller overrides the caller computed for the log it is passed to, for
+// frameworks logging on behalf of code several frames up, e.g.
+//
+//	_, file, line, _ := runtime.Caller(depth)
+//	slog.L().Info("step done", slog.Caller(file, line))
+func Caller(file string, line int) CallerInfo {
+	return CallerInfo{
+		File: file,
+		Line: line,
+	}
+}
+
+// withCaller sets the caller of ce to the CallerInfo found in args, if any.
+func withCaller(ce *zapcore.CheckedEntry, args []interface{}) {
+	for i := range args {
+		if caller, ok := args[i].(CallerInfo); ok {
+			ce.Entry.Caller = zapcore.NewEntryCaller(0, caller.File, caller.Line, true)
+		}
+	}
+}
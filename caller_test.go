@@ -0,0 +1,90 @@
+package slog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaller(t *testing.T) {
+	caller := Caller("/app/handlers/order.go", 42)
+
+	tests := []struct {
+		name string
+		log  func(s *SukiLogger)
+	}{
+		{
+			name: "Info",
+			log: func(s *SukiLogger) {
+				s.Info("message", caller)
+			},
+		},
+		{
+			name: "Error",
+			log: func(s *SukiLogger) {
+				s.Error("message", Any("id", 1), caller)
+			},
+		},
+		{
+			name: "Event",
+			log: func(s *SukiLogger) {
+				s.Event("message", WithEvent("order", ActionCreate, ResultSuccess, nil, "ref"), caller)
+			},
+		},
+		{
+			name: "Request HTTP",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("message", HTTPRequestInfo{}, WithHTTPResponse(200, 1, ""), caller)
+			},
+		},
+		{
+			name: "Request Kafka",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("message", KafkaMessage{}, WithKafkaResult(1), caller)
+			},
+		},
+		{
+			name: "Usage",
+			log: func(s *SukiLogger) {
+				_ = s.Usage(WithUsage("shop-1", "orders", 1, "order"), caller)
+			},
+		},
+		{
+			name: "Timer",
+			log: func(s *SukiLogger) {
+				s.StartTimer("step").Stop(caller)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			tt.log(logger)
+
+			if got := decodeLines(t, buf)[0]["caller"]; got != "handlers/order.go:42" {
+				t.Errorf("caller = %v, want handlers/order.go:42", got)
+			}
+		})
+	}
+}
+
+func TestCallerNotOverridden(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	logger.Info("computed", WithTracing("trace-1", "span-1"))
+	logger.Info("overridden", Caller("/app/jobs/import.go", 7))
+
+	lines := decodeLines(t, buf)
+	if !strings.Contains(lines[0]["caller"].(string), "caller_test.go:") {
+		t.Errorf("caller = %v, want the computed caller", lines[0]["caller"])
+	}
+	if _, ok := lines[1]["data"].(map[string]interface{})["application"]; ok {
+		t.Errorf("data = %v, want the caller kept out of the data", lines[1]["data"])
+	}
+
+	<-entries
+	if entry := <-entries; entry.Caller != "jobs/import.go:7" {
+		t.Errorf("subscribed caller = %q, want jobs/import.go:7", entry.Caller)
+	}
+}
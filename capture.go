@@ -0,0 +1,179 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// CaptureFilter selects the logs written by StartCapture.
+type CaptureFilter struct {
+	// Level is the minimum level captured, LevelDebug captures the debug logs
+	// even when the configured LogLevel filters them out of the main output.
+	Level LogLevel
+	// LogTypes are the captured log types, e.g. "handler.http", all when empty.
+	LogTypes []string
+}
+
+// StartCapture writes the logs matching filter to w, in addition to the main
+// output, until the returned stop func is called. The sampling of the main
+// output also applies to the captured logs below warn level.
+func (s *SukiLogger) StartCapture(filter CaptureFilter, w io.Writer) (stop func()) {
+	return s.captures.start(filter, w)
+}
+
+type capture struct {
+	level    zapcore.Level
+	logTypes map[string]struct{}
+	mu       sync.Mutex
+	w        io.Writer
+}
+
+func (c *capture) matches(level zapcore.Level, logType string) bool {
+	if level < c.level {
+		return false
+	}
+	if len(c.logTypes) == 0 {
+		return true
+	}
+	_, ok := c.logTypes[logType]
+	return ok
+}
+
+func (c *capture) write(p []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.w.Write(p)
+	return err
+}
+
+type captures struct {
+	mu  sync.RWMutex
+	set map[*capture]struct{}
+	// count is len(set), read without locking on every log.
+	count int32
+}
+
+func (h *captures) start(filter CaptureFilter, w io.Writer) func() {
+	c := &capture{level: zapcore.Level(filter.Level), w: w}
+	if len(filter.LogTypes) > 0 {
+		c.logTypes = make(map[string]struct{}, len(filter.LogTypes))
+		for _, logType := range filter.LogTypes {
+			c.logTypes[logType] = struct{}{}
+		}
+	}
+
+	h.mu.Lock()
+	if h.set == nil {
+		h.set = make(map[*capture]struct{})
+	}
+	h.set[c] = struct{}{}
+	atomic.StoreInt32(&h.count, int32(len(h.set)))
+	h.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.set, c)
+			atomic.StoreInt32(&h.count, int32(len(h.set)))
+			h.mu.Unlock()
+		})
+	}
+}
+
+func (h *captures) enabled(level zapcore.Level) bool {
+	if atomic.LoadInt32(&h.count) == 0 {
+		return false
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.set {
+		if level >= c.level {
+			return true
+		}
+	}
+	return false
+}
+
+// matching returns the captures of the logs of level and logType.
+func (h *captures) matching(level zapcore.Level, logType string) []*capture {
+	if atomic.LoadInt32(&h.count) == 0 {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var matched []*capture
+	for c := range h.set {
+		if c.matches(level, logType) {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// captureCore is the zapcore.Core writing the entries to the active captures.
+type captureCore struct {
+	hub *captures
+	enc zapcore.Encoder
+	// logTypeKey is the name of the log_type field, logType its value once added by With.
+	logTypeKey string
+	logType    string
+}
+
+func newCaptureCore(enc zapcore.Encoder, hub *captures, prefix string) zapcore.Core {
+	return &captureCore{hub: hub, enc: enc, logTypeKey: prefix + "log_type"}
+}
+
+func (c *captureCore) Enabled(level zapcore.Level) bool {
+	return c.hub.enabled(level)
+}
+
+func (c *captureCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		if f.Key == c.logTypeKey && f.Type == zapcore.StringType {
+			clone.logType = f.String
+		}
+		f.AddTo(clone.enc)
+	}
+	return &clone
+}
+
+func (c *captureCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if len(c.hub.matching(ent.Level, c.logType)) > 0 {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *captureCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	matched := c.hub.matching(ent.Level, c.logType)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	for _, capture := range matched {
+		if werr := capture.write(buf.Bytes()); werr != nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+func (c *captureCore) Sync() error {
+	return nil
+}
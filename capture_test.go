@@ -0,0 +1,155 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// emitCaptureLogs writes a debug and an info application log and a client.db log.
+func emitCaptureLogs(logger *SukiLogger) {
+	logger.Debug("debug application")
+	logger.Info("info application")
+	logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(1, 1))
+}
+
+func messages(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var msgs []string
+	for _, line := range decodeLines(t, buf) {
+		msgs = append(msgs, line["message"].(string))
+	}
+	return msgs
+}
+
+func TestStartCapture(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter CaptureFilter
+		want   []string
+	}{
+		{
+			name:   "Every log type from debug",
+			filter: CaptureFilter{Level: LevelDebug},
+			want:   []string{"debug application", "info application", "db query"},
+		},
+		{
+			name:   "One log type from debug",
+			filter: CaptureFilter{Level: LevelDebug, LogTypes: []string{"application"}},
+			want:   []string{"debug application", "info application"},
+		},
+		{
+			name:   "One log type from info",
+			filter: CaptureFilter{LogTypes: []string{"client.db"}},
+			want:   []string{"db query"},
+		},
+		{
+			name:   "Level above every log",
+			filter: CaptureFilter{Level: LevelError},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			captured := &bytes.Buffer{}
+
+			stop := logger.StartCapture(tt.filter, captured)
+			emitCaptureLogs(logger)
+			stop()
+
+			if got := messages(t, captured); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("captured = %v, want %v", got, tt.want)
+			}
+			if got, want := messages(t, buf), []string{"info application", "db query"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("main output = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestStartCaptureWritesTheEnvelope(t *testing.T) {
+	c := NewProductionConfig()
+	c.AppName = "shop"
+	logger, buf := newTestLogger(c)
+	captured := &bytes.Buffer{}
+
+	stop := logger.StartCapture(CaptureFilter{LogTypes: []string{"client.db"}}, captured)
+	defer stop()
+	logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(1, 1))
+
+	if captured.String() != buf.String() {
+		t.Errorf("captured = %s, want the main output %s", captured, buf)
+	}
+}
+
+func TestStartCaptureStop(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+
+	stopFirst := logger.StartCapture(CaptureFilter{Level: LevelDebug}, first)
+	stopSecond := logger.StartCapture(CaptureFilter{Level: LevelDebug}, second)
+	logger.Debug("both")
+
+	stopFirst()
+	stopFirst()
+	logger.Debug("second only")
+
+	stopSecond()
+	logger.Debug("none")
+
+	if got, want := messages(t, first), []string{"both"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("first = %v, want %v", got, want)
+	}
+	if got, want := messages(t, second), []string{"both", "second only"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second = %v, want %v", got, want)
+	}
+	if n := len(logger.captures.set); n != 0 {
+		t.Errorf("%d captures left after stop, want 0", n)
+	}
+	if logger.captures.enabled(zapcore.DebugLevel) {
+		t.Error("debug level still enabled after stop")
+	}
+}
+
+func TestStartCaptureConcurrent(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Debug(fmt.Sprintf("debug %d %d", i, j))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				stop := logger.StartCapture(CaptureFilter{Level: LevelDebug}, &lockedBuffer{})
+				stop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(logger.captures.set); n != 0 {
+		t.Errorf("%d captures left after stop, want 0", n)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
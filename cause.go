@@ -0,0 +1,17 @@
+package slog
+
+// CauseInfo is the cause object written by Cause, a machine-readable category
+// of what went wrong next to the human error message.
+type CauseInfo struct {
+	Category string `json:"category"`
+	Detail   string `json:"detail"`
+}
+
+// Cause returns the cause field of an error, e.g. Cause("dependency_timeout", "payment gateway").
+// Application logs write it next to their other fields, request logs as data.cause.
+func Cause(category string, detail string) LogField {
+	return Any("cause", CauseInfo{
+		Category: category,
+		Detail:   detail,
+	})
+}
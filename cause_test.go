@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCause(t *testing.T) {
+	wantCause := map[string]interface{}{"category": "dependency_timeout", "detail": "payment gateway"}
+
+	tests := []struct {
+		name      string
+		emit      func(s *SukiLogger)
+		wantCause func(data map[string]interface{}) interface{}
+		wantError func(data map[string]interface{}) interface{}
+	}{
+		{
+			name: "Application error log",
+			emit: func(s *SukiLogger) {
+				s.Error("charge failed", Error(errors.New("context deadline exceeded")), Cause("dependency_timeout", "payment gateway"))
+			},
+			wantCause: func(data map[string]interface{}) interface{} {
+				return data["shop"].(map[string]interface{})["cause"]
+			},
+			wantError: func(data map[string]interface{}) interface{} {
+				return data["shop"].(map[string]interface{})["error"]
+			},
+		},
+		{
+			name: "Request error response",
+			emit: func(s *SukiLogger) {
+				s.RequestHTTP(
+					"http request",
+					WithHTTPRequest("POST", "/charges", "", nil, nil, nil, ""),
+					WithHTTPResponse(504, 30000, "", WithError("context deadline exceeded")),
+					Cause("dependency_timeout", "payment gateway"),
+				)
+			},
+			wantCause: func(data map[string]interface{}) interface{} {
+				return data["cause"]
+			},
+			wantError: func(data map[string]interface{}) interface{} {
+				return data["http_response"].(map[string]interface{})["error"].(map[string]interface{})["name"]
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AppName = "shop"
+			logger, buf := newTestLogger(c)
+
+			tt.emit(logger)
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			if got := tt.wantCause(data); !reflect.DeepEqual(got, wantCause) {
+				t.Errorf("cause = %v, want %v", got, wantCause)
+			}
+			if got := tt.wantError(data); got != "context deadline exceeded" {
+				t.Errorf("error = %v, want context deadline exceeded", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package slog
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// ClientOption configures the behaviour of RoundTripper.
+type ClientOption struct {
+	// Quota adds data.quota parsed from the rate limit headers of the responses.
+	Quota *QuotaOption
+}
+
+// RoundTripper returns a http.RoundTripper that writes a client.http log for
+// every request sent through next, http.DefaultTransport when nil.
+func (s *SukiLogger) RoundTripper(next http.RoundTripper, opts ClientOption) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{logger: s, next: next, opts: opts}
+}
+
+type roundTripper struct {
+	logger *SukiLogger
+	next   http.RoundTripper
+	opts   ClientOption
+}
+
+func (t *roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := t.logger.clock().Now()
+	resp, err := t.next.RoundTrip(r)
+	end := t.logger.clock().Now()
+
+	request := WithHTTPRequest(
+		r.Method,
+		r.URL.Path,
+		"",
+		flattenValues(r.Header),
+		nil,
+		flattenValues(r.URL.Query()),
+		"",
+	)
+	request.Host = r.URL.Host
+	request.RawQuery = r.URL.RawQuery
+
+	if err != nil {
+		t.logger.ClientHTTP("http client request", request, WithHTTPResponseDuration(0, end.Sub(start), "", WithError(err.Error())))
+		return resp, err
+	}
+
+	request.Protocol = resp.Proto
+	if resp.TLS != nil {
+		request.TLSVersion = tlsVersionName(resp.TLS.Version)
+		request.CipherSuite = tls.CipherSuiteName(resp.TLS.CipherSuite)
+	}
+
+	var args []interface{}
+	if t.opts.Quota != nil {
+		quota, threshold := t.opts.Quota.quota(r.URL.Hostname(), resp.Header, end)
+		args = append(args, quota)
+		if quota.BelowThreshold && threshold.Alert != LevelNone {
+			args = append(args, LogOption{Alert: threshold.Alert})
+		}
+	}
+
+	t.logger.ClientHTTP("http client request", request, WithHTTPResponseDuration(int64(resp.StatusCode), end.Sub(start), ""), args...)
+	return resp, nil
+}
+
+// ClientHTTP writes a client.http log of an outbound request, a QuotaInfo in args
+// is written as data.quota and raises the level to warn when below its threshold.
+func (s SukiLogger) ClientHTTP(
+	message string,
+	request HTTPRequestInfo,
+	response HTTPResponseInfo,
+	args ...interface{},
+) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	level := s.httpData("client.http", &request, &response, data, args)
+	level = quotaArgs(level, data, args)
+
+	if ce := s.sampledLogger("client.http", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		s.writeHTTP(ce, alertLevel, data, request, response)
+	}
+}
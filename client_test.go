@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", r.URL.Query().Get("remaining"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		remaining string
+		wantLevel string
+		wantAlert float64
+	}{
+		{
+			name:      "Quota available",
+			remaining: "80",
+			wantLevel: "info",
+			wantAlert: 0,
+		},
+		{
+			name:      "Quota below threshold",
+			remaining: "5",
+			wantLevel: "warn",
+			wantAlert: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			client := &http.Client{Transport: logger.RoundTripper(nil, ClientOption{
+				Quota: &QuotaOption{Thresholds: map[string]QuotaThreshold{"": {Percent: 10, Alert: LevelAlert}}},
+			})}
+
+			resp, err := client.Get(server.URL + "/items?remaining=" + tt.remaining)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "client.http" || line["level"] != tt.wantLevel || line["alert"] != tt.wantAlert {
+				t.Errorf("log_type, level, alert = %v, %v, %v, want client.http, %v, %v", line["log_type"], line["level"], line["alert"], tt.wantLevel, tt.wantAlert)
+			}
+
+			data := line["data"].(map[string]interface{})
+			request := data["http_request"].(map[string]interface{})
+			if request["path"] != "/items" || request["host"] != server.Listener.Addr().String() {
+				t.Errorf("unexpected request %v", request)
+			}
+			quota := data["quota"].(map[string]interface{})
+			if quota["limit"] != float64(100) {
+				t.Errorf("quota = %v, want limit 100", quota)
+			}
+		})
+	}
+}
+
+func TestRoundTripperError(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	client := &http.Client{Transport: logger.RoundTripper(nil, ClientOption{})}
+
+	if _, err := client.Get("http://127.0.0.1:1/unreachable"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	response := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_response"].(map[string]interface{})
+	if response["status"] != float64(0) || response["error"].(map[string]interface{})["name"] == "" {
+		t.Errorf("unexpected response %v", response)
+	}
+}
@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of the logger, see package clock.
+type Clock = clock.Clock
+
+func (c Config) clock() Clock {
+	if c.Clock != nil {
+		return c.Clock
+	}
+	return clock.Real{}
+}
+
+func (s SukiLogger) clock() Clock {
+	return s.config.clock()
+}
+
+// zapClock times the zap entries with a Clock.
+type zapClock struct {
+	Clock
+	// forwarders forward the ticks of the tickers, nil when they are never stopped.
+	forwarders *tickForwarders
+}
+
+// NewTicker times the flushes of the Async buffer. zap wants a *time.Ticker, so
+// the ticks of a Clock other than the real one are forwarded to one by a
+// goroutine, which stops along with the ticker of the Clock once the
+// forwarders are closed.
+func (c zapClock) NewTicker(d time.Duration) *time.Ticker {
+	if _, ok := c.Clock.(clock.Real); ok {
+		return time.NewTicker(d)
+	}
+
+	forwarders := c.forwarders
+	if forwarders == nil {
+		forwarders = &tickForwarders{}
+	}
+	ticker := c.Clock.NewTicker(d)
+	ch := make(chan time.Time, 1)
+	forwarders.wg.Add(1)
+	go func() {
+		defer forwarders.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case tick := <-ticker.C():
+				select {
+				case ch <- tick:
+				default:
+				}
+			case <-forwarders.stop:
+				return
+			}
+		}
+	}()
+	return &time.Ticker{C: ch}
+}
+
+// tickForwarders are the goroutines of zapClock.NewTicker.
+type tickForwarders struct {
+	stop chan struct{}
+	once sync.Once
+	wg   sync.WaitGroup
+}
+
+func newTickForwarders() *tickForwarders {
+	return &tickForwarders{stop: make(chan struct{})}
+}
+
+// close stops the goroutines and their tickers, and waits for them to return.
+func (f *tickForwarders) close() {
+	f.once.Do(func() { close(f.stop) })
+	f.wg.Wait()
+}
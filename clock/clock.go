@@ -0,0 +1,52 @@
+// Package clock is the time source of the logger, it lets tests control time
+// with slogtest.FakeClock.
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the time and schedules tickers and timers.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Ticker delivers ticks on C at intervals, as time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer calls its func once its duration elapsed unless stopped, as the time.Timer of time.AfterFunc.
+type Timer interface {
+	Stop() bool
+}
+
+// Real is the Clock of the time package.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+func (Real) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
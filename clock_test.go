@@ -0,0 +1,23 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"testing"
+	"time"
+)
+
+func TestConfigClock(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	logger.Info("first")
+	clock.Advance(90 * time.Minute)
+	logger.Info("second")
+
+	lines := decodeLines(t, buf)
+	if lines[0]["timestamp"] != "2024-03-01T12:00:00.000Z" || lines[1]["timestamp"] != "2024-03-01T13:30:00.000Z" {
+		t.Errorf("timestamps = %v, %v, want the times of the clock", lines[0]["timestamp"], lines[1]["timestamp"])
+	}
+}
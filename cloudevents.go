@@ -0,0 +1,139 @@
+package slog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// CloudEventsSpecVersion is the version of the CloudEvents specification the event logs follow.
+const CloudEventsSpecVersion = "1.0"
+
+// CloudEventsConfig writes the event logs as CloudEvents (https://cloudevents.io)
+// in JSON format instead of the logger envelope, the other logs are unchanged.
+type CloudEventsConfig struct {
+	Enabled bool
+	// Source is the source attribute, AppName when empty.
+	Source string
+	// TypePrefix is prepended to the type attribute "<entity>.<action>", e.g. "com.sellsuki.".
+	TypePrefix string
+}
+
+// newCloudEventsLogger returns the loggers of the CloudEvents, sampled like
+// the other logs and unsampled for the alerting events. They write no level,
+// message nor caller and name the timestamp time. The subscribers receive the
+// events as entries of the event log type, the captures as CloudEvents.
+func newCloudEventsLogger(config zap.Config, c Config, sink zapcore.WriteSyncer, errorOutput zapcore.WriteSyncer, subs *subscribers, caps *captures) (*zap.Logger, *zap.Logger) {
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey: "time",
+		EncodeTime: func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+			enc.AppendString(t.UTC().Format(time.RFC3339Nano))
+		},
+		EncodeDuration: zapcore.MillisDurationEncoder,
+		LineEnding:     c.LineEnding,
+	})
+	envelopeKeys := map[string]bool{
+		c.FieldPrefix + "app_name": true,
+		c.FieldPrefix + "version":  true,
+		c.FieldPrefix + "log_type": true,
+		c.FieldPrefix + "alert":    true,
+	}
+
+	core := zapcore.NewTee(
+		cloudEventsCore{Core: zapcore.NewCore(encoder, sink, config.Level), envelopeKeys: envelopeKeys},
+		newSubscriberCore(config.Level, subs, c.FieldPrefix),
+		cloudEventsCore{Core: &captureCore{hub: caps, enc: encoder, logType: "event"}, envelopeKeys: envelopeKeys},
+	)
+
+	sampled := core
+	if config.Sampling != nil && len(c.SampleKeys) == 0 {
+		sampled = zapcore.NewSamplerWithOptions(core, time.Second, config.Sampling.Initial, config.Sampling.Thereafter)
+	}
+
+	opts := []zap.Option{
+		zap.ErrorOutput(errorOutput),
+		zap.WithClock(zapClock{Clock: c.clock()}),
+	}
+	return zap.New(sampled, opts...), zap.New(core, opts...)
+}
+
+// cloudEventsCore leaves the envelope fields, only read by the subscribers, out
+// of the CloudEvents written by Core.
+type cloudEventsCore struct {
+	zapcore.Core
+	envelopeKeys map[string]bool
+}
+
+func (c cloudEventsCore) With(fields []zapcore.Field) zapcore.Core {
+	return cloudEventsCore{Core: c.Core.With(c.attributes(fields)), envelopeKeys: c.envelopeKeys}
+}
+
+func (c cloudEventsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c cloudEventsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.attributes(fields))
+}
+
+// attributes returns fields without the envelope fields.
+func (c cloudEventsCore) attributes(fields []zapcore.Field) []zapcore.Field {
+	attributes := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if !c.envelopeKeys[f.Key] {
+			attributes = append(attributes, f)
+		}
+	}
+	return attributes
+}
+
+// cloudEventLogger returns the logger of the CloudEvents, unsampled for alerting events.
+func (s SukiLogger) cloudEventLogger(alertLevel AlertLevel) *zap.Logger {
+	if alertLevel >= LevelAlert {
+		return s.cloudEventsAlertInstance
+	}
+	return s.cloudEventsInstance
+}
+
+// cloudEvent writes event as a CloudEvent holding data, ReferenceID is its subject.
+// The checked entry ce was checked on the cloudEventLogger.
+func (s SukiLogger) cloudEvent(ce *zapcore.CheckedEntry, alertLevel AlertLevel, event EventLog, data map[string]interface{}) {
+
+	source := s.config.CloudEvents.Source
+	if source == "" {
+		source = s.config.AppName
+	}
+
+	fields := []zap.Field{
+		zap.String("specversion", CloudEventsSpecVersion),
+		zap.String("id", cloudEventID()),
+		zap.String("source", source),
+		zap.String("type", s.config.CloudEvents.TypePrefix+event.Entity+"."+string(event.Action)),
+	}
+	if event.ReferenceID != "" {
+		fields = append(fields, zap.String("subject", event.ReferenceID))
+	}
+	if s.config.OmitEmpty {
+		data = s.omitEmpty(data)
+	}
+	data = s.config.redactPaths(data)
+	data = s.config.allowFields(data)
+	fields = append(fields,
+		zap.String("datacontenttype", "application/json"),
+		zap.Any("data", data),
+		zap.Int(s.config.FieldPrefix+"alert", int(alertLevel)),
+	)
+	ce.Write(fields...)
+}
+
+// cloudEventID returns a random id unique to an event.
+func cloudEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,228 @@
+package slog
+
+import (
+	"bytes"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// cloudEventAttributes are the attributes of the CloudEvents JSON format the
+// logger writes, required ones set to true.
+var cloudEventAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"time":            false,
+	"subject":         false,
+	"datacontenttype": false,
+	"data":            false,
+}
+
+var cloudEventAttributeName = regexp.MustCompile(`^[a-z0-9]{1,20}$`)
+
+// checkCloudEvent reports the violations of the CloudEvents 1.0 JSON format by event.
+func checkCloudEvent(t *testing.T, event map[string]interface{}) {
+	t.Helper()
+	for name := range event {
+		if _, ok := cloudEventAttributes[name]; !ok {
+			t.Errorf("unexpected attribute %q", name)
+		}
+		if !cloudEventAttributeName.MatchString(name) {
+			t.Errorf("invalid attribute name %q", name)
+		}
+	}
+	for name, required := range cloudEventAttributes {
+		value, ok := event[name]
+		if !ok {
+			if required {
+				t.Errorf("missing required attribute %q", name)
+			}
+			continue
+		}
+		if s, isString := value.(string); name != "data" && (!isString || s == "") {
+			t.Errorf("%s = %v, want a non-empty string", name, value)
+		}
+	}
+	if event["specversion"] != "1.0" {
+		t.Errorf("specversion = %v, want 1.0", event["specversion"])
+	}
+	if source, _ := event["source"].(string); source != "" {
+		if _, err := url.Parse(source); err != nil {
+			t.Errorf("source %q is not a URI-reference: %v", source, err)
+		}
+	}
+	if tm, _ := event["time"].(string); tm != "" {
+		if _, err := time.Parse(time.RFC3339, tm); err != nil {
+			t.Errorf("time %q is not RFC 3339: %v", tm, err)
+		}
+	}
+	if _, ok := event["data"]; ok && event["datacontenttype"] != "application/json" {
+		t.Errorf("datacontenttype = %v, want application/json", event["datacontenttype"])
+	}
+}
+
+func TestEventCloudEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      CloudEventsConfig
+		event       EventLog
+		wantSource  string
+		wantType    string
+		wantSubject interface{}
+	}{
+		{
+			name:        "Default source",
+			config:      CloudEventsConfig{Enabled: true},
+			event:       WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"),
+			wantSource:  "shop",
+			wantType:    "order.create",
+			wantSubject: "order-1",
+		},
+		{
+			name:        "Source and type prefix",
+			config:      CloudEventsConfig{Enabled: true, Source: "https://shop.sellsuki.com/orders", TypePrefix: "com.sellsuki."},
+			event:       WithEvent("order", ActionDelete, ResultSuccess, nil, ""),
+			wantSource:  "https://shop.sellsuki.com/orders",
+			wantType:    "com.sellsuki.order.delete",
+			wantSubject: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AppName = "shop"
+			c.CloudEvents = tt.config
+			c.Clock = slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			logger, buf := newTestLogger(c)
+
+			logger.Event("order event", tt.event, WithTracing("trace-1", "span-1"))
+			logger.Event("order event", tt.event)
+			logger.Info("not an event")
+
+			lines := decodeLines(t, buf)
+			if len(lines) != 3 {
+				t.Fatalf("%d lines, want 3", len(lines))
+			}
+
+			event := lines[0]
+			checkCloudEvent(t, event)
+			if event["source"] != tt.wantSource || event["type"] != tt.wantType || event["subject"] != tt.wantSubject {
+				t.Errorf("source, type, subject = %v, %v, %v, want %v, %v, %v", event["source"], event["type"], event["subject"], tt.wantSource, tt.wantType, tt.wantSubject)
+			}
+			if event["time"] != "2024-03-01T12:00:00Z" {
+				t.Errorf("time = %v, want 2024-03-01T12:00:00Z", event["time"])
+			}
+			if event["id"] == lines[1]["id"] {
+				t.Errorf("two events share the id %v", event["id"])
+			}
+
+			data := event["data"].(map[string]interface{})
+			wantEvent := map[string]interface{}{
+				"entity":       tt.event.Entity,
+				"action":       string(tt.event.Action),
+				"result":       string(tt.event.Result),
+				"reference_id": tt.event.ReferenceID,
+				"data":         tt.event.Data,
+			}
+			if !reflect.DeepEqual(data["event"], wantEvent) {
+				t.Errorf("data.event = %v, want %v", data["event"], wantEvent)
+			}
+			if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+				t.Errorf("data.tracing = %v, want trace_id trace-1", data["tracing"])
+			}
+
+			if lines[2]["log_type"] != "application" || lines[2]["message"] != "not an event" {
+				t.Errorf("application log = %v, want the logger envelope", lines[2])
+			}
+		})
+	}
+}
+
+func TestEventCloudEventsDisabled(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.Event("order event", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+
+	line := decodeLines(t, buf)[0]
+	if line["log_type"] != "event" || line["specversion"] != nil {
+		t.Errorf("log_type, specversion = %v, %v, want event, none", line["log_type"], line["specversion"])
+	}
+}
+
+func TestEventCloudEventsSubscribersAndSampling(t *testing.T) {
+	c := NewProductionConfig()
+	c.CloudEvents = CloudEventsConfig{Enabled: true}
+	logger, buf := newTestLogger(c)
+
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+	alerts := logger.SubscribeAlerts()
+	defer alerts.Unsubscribe()
+
+	event := WithEvent("payment", ActionUpdate, ResultCompensate, nil, "payment-1")
+	logger.Event("payment failed", event, LogOption{Alert: LevelAlert})
+
+	select {
+	case entry := <-alerts.Entries():
+		if entry.LogType != "event" || entry.Alert != LevelAlert || entry.Message != "payment failed" {
+			t.Errorf("alert entry = %+v, want an alerting event", entry)
+		}
+		if entry.Data["event"].(map[string]interface{})["reference_id"] != "payment-1" {
+			t.Errorf("alert entry data = %v", entry.Data)
+		}
+	default:
+		t.Fatal("alerting event not received by SubscribeAlerts")
+	}
+	select {
+	case entry := <-entries:
+		if entry.LogType != "event" {
+			t.Errorf("entry log_type = %v, want event", entry.LogType)
+		}
+	default:
+		t.Fatal("event not received by Subscribe")
+	}
+
+	captured := logger.Capture(func() {
+		logger.Event("order created", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+	})
+	if len(captured) != 1 || captured[0].LogType != "event" || captured[0].Alert != LevelNone {
+		t.Errorf("Capture() = %+v, want one event", captured)
+	}
+
+	var capturedLines bytes.Buffer
+	stop := logger.StartCapture(CaptureFilter{LogTypes: []string{"event"}}, &capturedLines)
+	logger.Event("order created", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+	stop()
+	if lines := decodeLines(t, &capturedLines); len(lines) != 1 {
+		t.Errorf("StartCapture() wrote %d lines, want 1", len(lines))
+	} else {
+		checkCloudEvent(t, lines[0])
+	}
+
+	for i := 0; i < 1000; i++ {
+		logger.Event("order created", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+		logger.Event("payment failed", event, LogOption{Alert: LevelAlert})
+	}
+
+	lines := decodeLines(t, buf)
+	alerting, others := 0, 0
+	for _, line := range lines {
+		checkCloudEvent(t, line)
+		if line["type"] == "payment.update" {
+			alerting++
+		} else {
+			others++
+		}
+	}
+	if alerting != 1001 {
+		t.Errorf("wrote %d alerting events, want 1001", alerting)
+	}
+	if others >= 1000 {
+		t.Errorf("wrote %d other events, want them sampled", others)
+	}
+}
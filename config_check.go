@@ -0,0 +1,71 @@
+package slog
+
+import (
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"sort"
+)
+
+// LargeBodySize is the body size limit above which Configure warns, bodies
+// that large rarely belong in logs.
+const LargeBodySize = 10 * 1048576
+
+// ConfigIssue is a soft problem of a Config, reported by the config log written by Configure.
+type ConfigIssue struct {
+	Field string `json:"field"`
+	Issue string `json:"issue"`
+}
+
+// configIssues returns the soft problems of c, those which do not prevent Configure from succeeding.
+func configIssues(c Config) []ConfigIssue {
+	var issues []ConfigIssue
+	for field, size := range map[string]int{
+		"MaxBodySize":         c.MaxBodySize,
+		"MaxRequestBodySize":  c.MaxRequestBodySize,
+		"MaxResponseBodySize": c.MaxResponseBodySize,
+	} {
+		if size > LargeBodySize {
+			issues = append(issues, ConfigIssue{Field: field, Issue: fmt.Sprintf("%d bytes is above %d bytes", size, LargeBodySize)})
+		}
+	}
+
+	known := make(map[string]bool, len(logTypes))
+	for _, logType := range logTypes {
+		known[logType] = true
+	}
+	for logType, threshold := range c.SlowThresholdMs {
+		if !known[logType] {
+			issues = append(issues, ConfigIssue{Field: "SlowThresholdMs", Issue: fmt.Sprintf("unknown log type %q", logType)})
+		} else if threshold < 0 {
+			issues = append(issues, ConfigIssue{Field: "SlowThresholdMs", Issue: fmt.Sprintf("negative threshold for %q", logType)})
+		}
+	}
+
+	if c.BatchErrorRatio < 0 || c.BatchErrorRatio > 1 {
+		issues = append(issues, ConfigIssue{Field: "BatchErrorRatio", Issue: fmt.Sprintf("%v is not between 0 and 1", c.BatchErrorRatio)})
+	}
+	if !c.CloudEvents.Enabled && (c.CloudEvents.Source != "" || c.CloudEvents.TypePrefix != "") {
+		issues = append(issues, ConfigIssue{Field: "CloudEvents", Issue: "Source or TypePrefix set while disabled"})
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Field != issues[j].Field {
+			return issues[i].Field < issues[j].Field
+		}
+		return issues[i].Issue < issues[j].Issue
+	})
+	return issues
+}
+
+// warnConfigIssues writes a config log listing the soft problems of the configuration, if any.
+func (s SukiLogger) warnConfigIssues() {
+	issues := configIssues(s.config)
+	if len(issues) == 0 {
+		return
+	}
+
+	data := map[string]interface{}{"config_issues": issues}
+	if ce := s.sampledLogger("config", LevelNone, zapcore.WarnLevel, "config issues", data).Check(zapcore.WarnLevel, "config issues"); ce != nil {
+		ce.Write(s.envelope(LevelNone, data)...)
+	}
+}
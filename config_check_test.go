@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigWarnings(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     func(c *Config)
+		wantIssues []interface{}
+	}{
+		{
+			name:       "Production config",
+			config:     func(c *Config) {},
+			wantIssues: nil,
+		},
+		{
+			name: "Large body sizes",
+			config: func(c *Config) {
+				c.MaxBodySize = LargeBodySize + 1
+				c.MaxResponseBodySize = LargeBodySize
+			},
+			wantIssues: []interface{}{
+				map[string]interface{}{"field": "MaxBodySize", "issue": "10485761 bytes is above 10485760 bytes"},
+			},
+		},
+		{
+			name: "Slow thresholds",
+			config: func(c *Config) {
+				c.SlowThresholdMs = map[string]float64{"handler.http": -1, "handler.grpc": 100, "client.db": 100}
+			},
+			wantIssues: []interface{}{
+				map[string]interface{}{"field": "SlowThresholdMs", "issue": `negative threshold for "handler.http"`},
+				map[string]interface{}{"field": "SlowThresholdMs", "issue": `unknown log type "handler.grpc"`},
+			},
+		},
+		{
+			name: "Batch error ratio and CloudEvents",
+			config: func(c *Config) {
+				c.BatchErrorRatio = 50
+				c.CloudEvents.Source = "shop"
+			},
+			wantIssues: []interface{}{
+				map[string]interface{}{"field": "BatchErrorRatio", "issue": "50 is not between 0 and 1"},
+				map[string]interface{}{"field": "CloudEvents", "issue": "Source or TypePrefix set while disabled"},
+			},
+		},
+		{
+			name: "Suppressed",
+			config: func(c *Config) {
+				c.MaxBodySize = LargeBodySize + 1
+				c.SuppressConfigWarnings = true
+			},
+			wantIssues: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			tt.config(&c)
+			_, buf := newTestLogger(c)
+
+			lines := decodeLines(t, buf)
+			if tt.wantIssues == nil {
+				if len(lines) != 0 {
+					t.Fatalf("Configure wrote %v, want nothing", lines)
+				}
+				return
+			}
+			if len(lines) != 1 {
+				t.Fatalf("Configure wrote %d logs, want 1", len(lines))
+			}
+
+			line := lines[0]
+			if line["log_type"] != "config" || line["level"] != "warn" || line["message"] != "config issues" {
+				t.Errorf("log_type, level, message = %v, %v, %v, want config, warn, config issues", line["log_type"], line["level"], line["message"])
+			}
+			if got := line["data"].(map[string]interface{})["config_issues"]; !reflect.DeepEqual(got, tt.wantIssues) {
+				t.Errorf("config_issues = %v, want %v", got, tt.wantIssues)
+			}
+		})
+	}
+}
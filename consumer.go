@@ -0,0 +1,76 @@
+package slog
+
+import "go.uber.org/zap/zapcore"
+
+type JobOutcome string
+
+const (
+	JobProcessed JobOutcome = "processed"
+	// JobRequeued is a failed job put back on its queue to be attempted again.
+	JobRequeued JobOutcome = "requeued"
+	// JobFailed is a failed job given up on, e.g. moved to a dead letter queue.
+	JobFailed JobOutcome = "failed"
+)
+
+// ConsumerJobInfo is the data.consumer_job of a consumer.job log, a job dequeued by a worker.
+type ConsumerJobInfo struct {
+	Queue    string `json:"queue"`
+	JobID    string `json:"job_id"`
+	Attempt  int    `json:"attempt"`
+	Priority int    `json:"priority"`
+}
+
+// ConsumerJobResult is the data.consumer_job_result of a consumer.job log. Duration is in milliseconds.
+type ConsumerJobResult struct {
+	Outcome         JobOutcome `json:"outcome"`
+	Duration        float64    `json:"duration"`
+	DurationAnomaly string     `json:"duration_anomaly,omitempty"`
+	Error           ErrorInfo  `json:"error"`
+}
+
+func WithConsumerJob(queue string, jobID string, attempt int, priority int) ConsumerJobInfo {
+	return ConsumerJobInfo{
+		Queue:    queue,
+		JobID:    jobID,
+		Attempt:  attempt,
+		Priority: priority,
+	}
+}
+
+func WithConsumerJobResult(outcome JobOutcome, duration float64, error ...ErrorInfo) ConsumerJobResult {
+	var e ErrorInfo
+	if len(error) > 0 {
+		e = error[0]
+	}
+	return ConsumerJobResult{
+		Outcome:  outcome,
+		Duration: duration,
+		Error:    e,
+	}
+}
+
+// ConsumeJob writes a consumer.job log of a job dequeued by a worker, at warn
+// level when it was requeued and at error level when it failed.
+func (s SukiLogger) ConsumeJob(message string, info ConsumerJobInfo, result ConsumerJobResult, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	result.Error = s.formatError(result.Error)
+	result.DurationAnomaly = s.checkDuration(&result.Duration)
+
+	data["consumer_job"] = info
+	data["consumer_job_result"] = result
+
+	level := s.durationLevel("consumer.job", result.Duration, data)
+	switch result.Outcome {
+	case JobRequeued:
+		level = zapcore.WarnLevel
+	case JobFailed:
+		level = zapcore.ErrorLevel
+	}
+
+	if ce := s.sampledLogger("consumer.job", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,71 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConsumeJob(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     ConsumerJobResult
+		wantLevel  string
+		wantResult map[string]interface{}
+	}{
+		{
+			name:      "Processed",
+			result:    WithConsumerJobResult(JobProcessed, 120),
+			wantLevel: "info",
+			wantResult: map[string]interface{}{
+				"outcome":  "processed",
+				"duration": float64(120),
+				"error":    map[string]interface{}{"name": "", "stack_trace": ""},
+			},
+		},
+		{
+			name:      "Requeued",
+			result:    WithConsumerJobResult(JobRequeued, 80, WithError("upstream timeout")),
+			wantLevel: "warn",
+			wantResult: map[string]interface{}{
+				"outcome":  "requeued",
+				"duration": float64(80),
+				"error":    map[string]interface{}{"name": "upstream timeout", "stack_trace": ""},
+			},
+		},
+		{
+			name:      "Failed",
+			result:    WithConsumerJobResult(JobFailed, 80, WithError("invalid payload")),
+			wantLevel: "error",
+			wantResult: map[string]interface{}{
+				"outcome":  "failed",
+				"duration": float64(80),
+				"error":    map[string]interface{}{"name": "invalid payload", "stack_trace": ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.ConsumeJob("send email", WithConsumerJob("emails", "job-1", 2, 5), tt.result, WithTracing("trace-1", "span-1"))
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "consumer.job" || line["level"] != tt.wantLevel || line["message"] != "send email" {
+				t.Errorf("log_type, level, message = %v, %v, %v, want consumer.job, %v, send email", line["log_type"], line["level"], line["message"], tt.wantLevel)
+			}
+			data := line["data"].(map[string]interface{})
+			wantJob := map[string]interface{}{
+				"queue":    "emails",
+				"job_id":   "job-1",
+				"attempt":  float64(2),
+				"priority": float64(5),
+			}
+			if !reflect.DeepEqual(data["consumer_job"], wantJob) {
+				t.Errorf("consumer_job = %v, want %v", data["consumer_job"], wantJob)
+			}
+			if !reflect.DeepEqual(data["consumer_job_result"], tt.wantResult) {
+				t.Errorf("consumer_job_result = %v, want %v", data["consumer_job_result"], tt.wantResult)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// ContextOutcome is the outcome of an operation bounded by a context.
+type ContextOutcome string
+
+const (
+	OutcomeCompleted        ContextOutcome = "completed"
+	OutcomeDeadlineExceeded ContextOutcome = "deadline_exceeded"
+	OutcomeCanceled         ContextOutcome = "canceled"
+)
+
+// LogContextResult writes an application log with the outcome field of the
+// operation bounded by ctx read from ctx.Err(): completed at info level,
+// deadline_exceeded at warn level and canceled, usually by the caller going
+// away, at info level. It returns the outcome, e.g.
+//
+//	err := importOrders(ctx)
+//	slog.L().LogContextResult(ctx, "import orders", slog.Error(err))
+func (s SukiLogger) LogContextResult(ctx context.Context, message string, args ...interface{}) ContextOutcome {
+	outcome, level := OutcomeCompleted, zapcore.InfoLevel
+	switch err := ctx.Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		outcome, level = OutcomeDeadlineExceeded, zapcore.WarnLevel
+	case errors.Is(err, context.Canceled):
+		outcome = OutcomeCanceled
+	}
+
+	args = append(args[:len(args):len(args)], ctx, Any("outcome", outcome))
+	logger, alertLevel, data := s.appLogBuilder(level, message, args...)
+	if ce := logger.Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+	return outcome
+}
@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLogContextResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func() context.Context
+		want      ContextOutcome
+		wantLevel string
+	}{
+		{
+			name:      "Completed",
+			ctx:       context.Background,
+			want:      OutcomeCompleted,
+			wantLevel: "info",
+		},
+		{
+			name: "Deadline exceeded",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+				defer cancel()
+				return ctx
+			},
+			want:      OutcomeDeadlineExceeded,
+			wantLevel: "warn",
+		},
+		{
+			name: "Canceled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			want:      OutcomeCanceled,
+			wantLevel: "info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AppName = "shop"
+			logger, buf := newTestLogger(c)
+			ctx := ContextWithTenant(tt.ctx(), "shop-1")
+
+			if got := logger.LogContextResult(ctx, "import orders", Any("orders", 3)); got != tt.want {
+				t.Errorf("LogContextResult() = %v, want %v", got, tt.want)
+			}
+
+			line := decodeLines(t, buf)[0]
+			if line["level"] != tt.wantLevel || line["message"] != "import orders" || line["log_type"] != "application" {
+				t.Errorf("level, message, log_type = %v, %v, %v, want %s, import orders, application", line["level"], line["message"], line["log_type"], tt.wantLevel)
+			}
+			data := line["data"].(map[string]interface{})
+			fields := data["shop"].(map[string]interface{})
+			if fields["outcome"] != string(tt.want) || fields["orders"] != float64(3) {
+				t.Errorf("fields = %v, want outcome %s and orders 3", fields, tt.want)
+			}
+			if data["tenant_id"] != "shop-1" {
+				t.Errorf("tenant_id = %v, want shop-1", data["tenant_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package slog
+
+import (
+	"database/sql"
+	"go.uber.org/zap/zapcore"
+)
+
+type DBQuery struct {
+	// System is the database, e.g. postgresql or mysql.
+	System    string `json:"system"`
+	Statement string `json:"statement"`
+	// Plan is the execution plan of the statement, see WithPlan.
+	Plan string `json:"plan,omitempty"`
+	// PlanTruncated is set when the plan exceeded MaxBodySize.
+	PlanTruncated bool `json:"plan_truncated,omitempty"`
+}
+
+type DBResult struct {
+	Duration        float64   `json:"duration"`
+	DurationAnomaly string    `json:"duration_anomaly,omitempty"`
+	Rows            int64     `json:"rows"`
+	Error           ErrorInfo `json:"error"`
+}
+
+// DBPoolInfo is the data.db_pool of a slow query, the snapshot of the
+// connection pool when the query finished. WaitDuration is in milliseconds.
+type DBPoolInfo struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	OpenConnections    int     `json:"open_connections"`
+	InUse              int     `json:"in_use"`
+	Idle               int     `json:"idle"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDuration       float64 `json:"wait_duration"`
+}
+
+// DBPoolStats returns the statistics of a connection pool, e.g. (*sql.DB).Stats.
+// Passed to RequestDB it is only called when the query is slow.
+type DBPoolStats func() sql.DBStats
+
+func WithDBQuery(system string, statement string) DBQuery {
+	return DBQuery{
+		System:    system,
+		Statement: statement,
+	}
+}
+
+// WithPlan attaches the execution plan of the statement, e.g. the output of
+// EXPLAIN fetched by the caller for a slow query. It is capped to MaxBodySize bytes, MaxBodyCeiling at most.
+func (q DBQuery) WithPlan(plan string) DBQuery {
+	q.Plan = plan
+	return q
+}
+
+func WithDBResult(duration float64, rows int64, error ...ErrorInfo) DBResult {
+	var e ErrorInfo
+	if len(error) > 0 {
+		e = error[0]
+	}
+	return DBResult{
+		Duration: duration,
+		Rows:     rows,
+		Error:    e,
+	}
+}
+
+// SlowQueryInfo is the data.slow_query of a slow_query log.
+type SlowQueryInfo struct {
+	ThresholdMs float64 `json:"threshold_ms"`
+}
+
+// RequestDB writes a client.db log of a database query, a query above the
+// SlowThresholdMs of "client.db" carries the pool statistics of a DBPoolStats arg in data.db_pool.
+// A query above SlowQueryThresholdMs is also written as an alerting slow_query log.
+func (s SukiLogger) RequestDB(message string, query DBQuery, result DBResult, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	result.Error = s.formatError(result.Error)
+	result.DurationAnomaly = s.checkDuration(&result.Duration)
+
+	query.Plan, query.PlanTruncated = truncateBody(query.Plan, s.config.MaxBodySize)
+
+	data["db_query"] = query
+	data["db_result"] = result
+	level := s.durationLevel("client.db", result.Duration, data)
+
+	if data["slow"] == true {
+		for i := range args {
+			if stats, ok := args[i].(DBPoolStats); ok {
+				data["db_pool"] = poolInfo(stats())
+			}
+		}
+	}
+
+	if ce := s.sampledLogger("client.db", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+
+	if threshold := s.config.SlowQueryThresholdMs; threshold > 0 && result.Duration > threshold {
+		s.slowQuery(message, query, result, threshold, args)
+	}
+}
+
+func (s SukiLogger) slowQuery(message string, query DBQuery, result DBResult, threshold float64, args []interface{}) {
+	data := make(map[string]interface{})
+	s.requestArgs(data, args)
+
+	data["db_query"] = query
+	data["db_result"] = result
+	data["slow_query"] = SlowQueryInfo{ThresholdMs: threshold}
+
+	if ce := s.sampledLogger("slow_query", LevelAlert, zapcore.WarnLevel, message, data).Check(zapcore.WarnLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(LevelAlert, data)...)
+	}
+}
+
+func poolInfo(stats sql.DBStats) DBPoolInfo {
+	return DBPoolInfo{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       toMillis(stats.WaitDuration),
+	}
+}
@@ -0,0 +1,229 @@
+package slog
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestDB(t *testing.T) {
+	stats := DBPoolStats(func() sql.DBStats {
+		return sql.DBStats{MaxOpenConnections: 1, OpenConnections: 1, InUse: 1, WaitCount: 3, WaitDuration: 1500 * time.Microsecond}
+	})
+
+	tests := []struct {
+		name      string
+		result    DBResult
+		args      []interface{}
+		wantLevel string
+		wantPool  interface{}
+	}{
+		{
+			name:      "Fast query",
+			result:    WithDBResult(20, 1),
+			args:      []interface{}{stats},
+			wantLevel: "info",
+			wantPool:  nil,
+		},
+		{
+			name:      "Slow query with pool stats",
+			result:    WithDBResult(250, 1),
+			args:      []interface{}{stats},
+			wantLevel: "warn",
+			wantPool: map[string]interface{}{
+				"max_open_connections": float64(1),
+				"open_connections":     float64(1),
+				"in_use":               float64(1),
+				"idle":                 float64(0),
+				"wait_count":           float64(3),
+				"wait_duration":        1.5,
+			},
+		},
+		{
+			name:      "Slow query without pool stats",
+			result:    WithDBResult(250, 1),
+			wantLevel: "warn",
+			wantPool:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.SlowThresholdMs = map[string]float64{"client.db": 100}
+			logger, buf := newTestLogger(c)
+
+			logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), tt.result, tt.args...)
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "client.db" || line["level"] != tt.wantLevel {
+				t.Errorf("log_type, level = %v, %v, want client.db, %v", line["log_type"], line["level"], tt.wantLevel)
+			}
+
+			data := line["data"].(map[string]interface{})
+			if !reflect.DeepEqual(data["db_pool"], tt.wantPool) {
+				t.Errorf("db_pool = %v, want %v", data["db_pool"], tt.wantPool)
+			}
+			if query := data["db_query"].(map[string]interface{}); query["system"] != "postgresql" || query["statement"] != "SELECT 1" {
+				t.Errorf("db_query = %v", query)
+			}
+		})
+	}
+}
+
+func TestRequestDBPoolStatsOnlyCalledWhenSlow(t *testing.T) {
+	calls := 0
+	stats := DBPoolStats(func() sql.DBStats {
+		calls++
+		return sql.DBStats{}
+	})
+
+	c := NewProductionConfig()
+	c.SlowThresholdMs = map[string]float64{"client.db": 100}
+	logger, _ := newTestLogger(c)
+
+	for i := 0; i < 10; i++ {
+		logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(5, 1), stats)
+	}
+	if calls != 0 {
+		t.Errorf("pool stats read %d times for fast queries, want 0", calls)
+	}
+}
+
+func TestRequestDBSlowQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		duration  float64
+		wantTypes []interface{}
+	}{
+		{
+			name:      "Below threshold",
+			threshold: 500,
+			duration:  499,
+			wantTypes: []interface{}{"client.db"},
+		},
+		{
+			name:      "At threshold",
+			threshold: 500,
+			duration:  500,
+			wantTypes: []interface{}{"client.db"},
+		},
+		{
+			name:      "Above threshold",
+			threshold: 500,
+			duration:  501,
+			wantTypes: []interface{}{"client.db", "slow_query"},
+		},
+		{
+			name:      "Disabled",
+			duration:  10000,
+			wantTypes: []interface{}{"client.db"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.SlowQueryThresholdMs = tt.threshold
+			logger, buf := newTestLogger(c)
+
+			logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(tt.duration, 1), WithTracing("trace-1", "span-1"))
+
+			lines := decodeLines(t, buf)
+			var types []interface{}
+			for _, line := range lines {
+				types = append(types, line["log_type"])
+			}
+			if !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Fatalf("log types = %v, want %v", types, tt.wantTypes)
+			}
+			if len(lines) < 2 {
+				return
+			}
+
+			slow := lines[1]
+			if slow["level"] != "warn" || slow["alert"] != float64(1) || slow["message"] != "db query" {
+				t.Errorf("level, alert, message = %v, %v, %v, want warn, 1, db query", slow["level"], slow["alert"], slow["message"])
+			}
+			data := slow["data"].(map[string]interface{})
+			if data["slow_query"].(map[string]interface{})["threshold_ms"] != tt.threshold {
+				t.Errorf("slow_query = %v, want threshold_ms %v", data["slow_query"], tt.threshold)
+			}
+			if data["db_result"].(map[string]interface{})["duration"] != tt.duration {
+				t.Errorf("db_result = %v, want duration %v", data["db_result"], tt.duration)
+			}
+			if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+				t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+			}
+		})
+	}
+}
+
+func TestRequestDBPlan(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxBodySize   int
+		plan          string
+		wantPlan      interface{}
+		wantTruncated interface{}
+	}{
+		{
+			name:          "No plan",
+			maxBodySize:   16,
+			wantPlan:      nil,
+			wantTruncated: nil,
+		},
+		{
+			name:          "Plan",
+			maxBodySize:   16,
+			plan:          "Seq Scan on a",
+			wantPlan:      "Seq Scan on a",
+			wantTruncated: nil,
+		},
+		{
+			name:          "Plan truncated",
+			maxBodySize:   16,
+			plan:          "Seq Scan on orders  (cost=0.00..35.50 rows=2550 width=4)",
+			wantPlan:      "Seq Scan on orde",
+			wantTruncated: true,
+		},
+		{
+			name:          "Rune not split",
+			maxBodySize:   16,
+			plan:          "Seq Scan on คำสั่งซื้อ",
+			wantPlan:      "Seq Scan on ค",
+			wantTruncated: true,
+		},
+		{
+			name:          "Ceiling",
+			plan:          strings.Repeat("a", MaxBodyCeiling+1),
+			wantPlan:      strings.Repeat("a", MaxBodyCeiling),
+			wantTruncated: true,
+		},
+		{
+			name:          "Unlimited",
+			plan:          "Seq Scan on orders  (cost=0.00..35.50 rows=2550 width=4)",
+			wantPlan:      "Seq Scan on orders  (cost=0.00..35.50 rows=2550 width=4)",
+			wantTruncated: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MaxBodySize = tt.maxBodySize
+			logger, buf := newTestLogger(c)
+
+			query := WithDBQuery("postgresql", "SELECT id FROM orders")
+			if tt.plan != "" {
+				query = query.WithPlan(tt.plan)
+			}
+			logger.RequestDB("db query", query, WithDBResult(250, 1))
+
+			got := decodeLines(t, buf)[0]["data"].(map[string]interface{})["db_query"].(map[string]interface{})
+			if got["plan"] != tt.wantPlan || got["plan_truncated"] != tt.wantTruncated {
+				t.Errorf("plan, plan_truncated = %v, %v, want %v, %v", got["plan"], got["plan_truncated"], tt.wantPlan, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DeprecationInterval is the minimum time between two deprecation logs of the same feature.
+const DeprecationInterval = time.Minute
+
+// maxDeprecationFrames bounds the stack written with a deprecation log.
+const maxDeprecationFrames = 32
+
+// DeprecationInfo is the data.deprecation of a deprecation log, Suppressed
+// counts the uses of the feature not logged since the previous log.
+type DeprecationInfo struct {
+	Feature    string       `json:"feature"`
+	RemoveBy   string       `json:"remove_by"`
+	Suppressed int          `json:"suppressed"`
+	Stack      []StackFrame `json:"stack"`
+}
+
+// deprecations rate limits the deprecation logs per feature.
+type deprecations struct {
+	mu       sync.Mutex
+	features map[string]*deprecationState
+}
+
+type deprecationState struct {
+	last       time.Time
+	suppressed int
+}
+
+// allow reports whether a use of feature at now is logged, along with the
+// number of uses suppressed since the last logged one.
+func (d *deprecations) allow(feature string, now time.Time) (bool, int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.features == nil {
+		d.features = make(map[string]*deprecationState)
+	}
+	state, ok := d.features[feature]
+	if !ok {
+		d.features[feature] = &deprecationState{last: now}
+		return true, 0
+	}
+	if now.Sub(state.last) < DeprecationInterval {
+		state.suppressed++
+		return false, 0
+	}
+
+	suppressed := state.suppressed
+	state.last = now
+	state.suppressed = 0
+	return true, suppressed
+}
+
+// Deprecated writes a deprecation log with the stack of its caller for a use of
+// a deprecated feature to be removed by removeBy, e.g. a version or a date. It
+// is written at most once per DeprecationInterval for each feature.
+func (s SukiLogger) Deprecated(feature string, removeBy string, args ...interface{}) {
+	suppressed := 0
+	if s.deprecations != nil {
+		var ok bool
+		if ok, suppressed = s.deprecations.allow(feature, s.clock().Now()); !ok {
+			return
+		}
+	}
+
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["deprecation"] = DeprecationInfo{
+		Feature:    feature,
+		RemoveBy:   removeBy,
+		Suppressed: suppressed,
+		Stack:      callerStack(3),
+	}
+
+	if ce := s.sampledLogger("deprecation", alertLevel, zapcore.WarnLevel, "deprecated "+feature, data).Check(zapcore.WarnLevel, "deprecated "+feature); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// callerStack returns the stack from the caller skip frames up, as runtime.Callers.
+func callerStack(skip int) []StackFrame {
+	pc := make([]uintptr, maxDeprecationFrames)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(skip, pc)])
+
+	var stack []StackFrame
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, StackFrame{Func: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			return stack
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeprecated(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	for i := 0; i < 5; i++ {
+		logger.Deprecated("orders-v1", "v3.0.0")
+	}
+	logger.Deprecated("legacy-auth", "2025-01-01")
+	clock.Advance(30 * time.Second)
+	logger.Deprecated("orders-v1", "v3.0.0")
+	clock.Advance(30 * time.Second)
+	logger.Deprecated("orders-v1", "v3.0.0", WithTracing("trace-1", "span-1"))
+
+	lines := decodeLines(t, buf)
+	var got []string
+	var suppressed []interface{}
+	for _, line := range lines {
+		deprecation := line["data"].(map[string]interface{})["deprecation"].(map[string]interface{})
+		got = append(got, deprecation["feature"].(string))
+		suppressed = append(suppressed, deprecation["suppressed"])
+	}
+	if want := []string{"orders-v1", "legacy-auth", "orders-v1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("features = %v, want %v", got, want)
+	}
+	if want := []interface{}{float64(0), float64(0), float64(5)}; !reflect.DeepEqual(suppressed, want) {
+		t.Errorf("suppressed = %v, want %v", suppressed, want)
+	}
+
+	line := lines[0]
+	if line["log_type"] != "deprecation" || line["level"] != "warn" || line["message"] != "deprecated orders-v1" {
+		t.Errorf("log_type, level, message = %v, %v, %v", line["log_type"], line["level"], line["message"])
+	}
+	if !strings.Contains(line["caller"].(string), "deprecation_test.go:") {
+		t.Errorf("caller = %v, want the caller of Deprecated", line["caller"])
+	}
+
+	deprecation := line["data"].(map[string]interface{})["deprecation"].(map[string]interface{})
+	if deprecation["remove_by"] != "v3.0.0" {
+		t.Errorf("remove_by = %v, want v3.0.0", deprecation["remove_by"])
+	}
+	frame := deprecation["stack"].([]interface{})[0].(map[string]interface{})
+	if !strings.HasSuffix(frame["func"].(string), ".TestDeprecated") || !strings.HasSuffix(frame["file"].(string), "deprecation_test.go") {
+		t.Errorf("stack starts with %v, want the caller of Deprecated", frame)
+	}
+
+	if lines[2]["data"].(map[string]interface{})["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+		t.Errorf("tracing = %v, want trace_id trace-1", lines[2]["data"])
+	}
+}
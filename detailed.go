@@ -0,0 +1,32 @@
+package slog
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugDetailed writes an application log with short as its message and detailed
+// as message_detail, for dashboards listing the short message of logs whose detail pages show more.
+func (s SukiLogger) DebugDetailed(short string, detailed string, args ...interface{}) {
+	s.detailed(zapcore.DebugLevel, short, detailed, args)
+}
+
+func (s SukiLogger) InfoDetailed(short string, detailed string, args ...interface{}) {
+	s.detailed(zapcore.InfoLevel, short, detailed, args)
+}
+
+func (s SukiLogger) WarnDetailed(short string, detailed string, args ...interface{}) {
+	s.detailed(zapcore.WarnLevel, short, detailed, args)
+}
+
+func (s SukiLogger) ErrorDetailed(short string, detailed string, args ...interface{}) {
+	s.detailed(zapcore.ErrorLevel, short, detailed, args)
+}
+
+func (s SukiLogger) detailed(level zapcore.Level, short string, detailed string, args []interface{}) {
+	logger, alertLevel, data := s.appLogBuilder(level, short, args...)
+	if ce := logger.Check(level, short); ce != nil {
+		withCaller(ce, args)
+		ce.Write(append([]zap.Field{zap.String(s.config.FieldPrefix+"message_detail", detailed)}, s.envelope(alertLevel, data)...)...)
+	}
+}
@@ -0,0 +1,61 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestDetailed(t *testing.T) {
+	tests := []struct {
+		name      string
+		log       func(s *SukiLogger, short string, detailed string, args ...interface{})
+		wantLevel string
+	}{
+		{
+			name:      "Debug",
+			log:       (*SukiLogger).DebugDetailed,
+			wantLevel: "debug",
+		},
+		{
+			name:      "Info",
+			log:       (*SukiLogger).InfoDetailed,
+			wantLevel: "info",
+		},
+		{
+			name:      "Warn",
+			log:       (*SukiLogger).WarnDetailed,
+			wantLevel: "warn",
+		},
+		{
+			name:      "Error",
+			log:       (*SukiLogger).ErrorDetailed,
+			wantLevel: "error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.LogLevel = LevelDebug
+			c.AppName = "shop"
+			logger, buf := newTestLogger(c)
+
+			tt.log(logger, "payment failed", "payment of order 42 declined by the gateway: insufficient funds", Any("order_id", 42))
+
+			line := decodeLines(t, buf)[0]
+			if line["level"] != tt.wantLevel || line["log_type"] != "application" {
+				t.Errorf("level, log_type = %v, %v, want %v, application", line["level"], line["log_type"], tt.wantLevel)
+			}
+			if line["message"] != "payment failed" {
+				t.Errorf("message = %v, want payment failed", line["message"])
+			}
+			if line["message_detail"] != "payment of order 42 declined by the gateway: insufficient funds" {
+				t.Errorf("message_detail = %v, want the detailed message", line["message_detail"])
+			}
+			if line["message"] == line["message_detail"] {
+				t.Error("message and message_detail are the same")
+			}
+			if data := line["data"].(map[string]interface{}); data["shop"].(map[string]interface{})["order_id"] != float64(42) {
+				t.Errorf("data = %v, want shop.order_id 42", data)
+			}
+		})
+	}
+}
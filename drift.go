@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// DriftInfo is a field of a resource whose actual value differs from the desired one.
+type DriftInfo struct {
+	Resource string      `json:"resource"`
+	Field    string      `json:"field"`
+	Desired  interface{} `json:"desired"`
+	Actual   interface{} `json:"actual"`
+}
+
+func WithDrift(resource string, field string, desired interface{}, actual interface{}) DriftInfo {
+	return DriftInfo{
+		Resource: resource,
+		Field:    field,
+		Desired:  desired,
+		Actual:   actual,
+	}
+}
+
+// Drift writes a drift log at warn level listing the drifted fields found by a reconciliation.
+func (s SukiLogger) Drift(items []DriftInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	drift := make([]DriftInfo, len(items))
+	for i, item := range items {
+		item.Desired = safeValue{key: "desired", value: item.Desired, report: s.internalError}
+		item.Actual = safeValue{key: "actual", value: item.Actual, report: s.internalError}
+		drift[i] = item
+	}
+	data["drift"] = drift
+
+	if ce := s.sampledLogger("drift", alertLevel, zapcore.WarnLevel, "configuration drift", data).Check(zapcore.WarnLevel, "configuration drift"); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
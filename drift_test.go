@@ -0,0 +1,28 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.Drift([]DriftInfo{
+		WithDrift("deployment/order-service", "replicas", 3, 1),
+		WithDrift("configmap/order-service", "LOG_LEVEL", "info", "debug"),
+	}, WithTracing("trace-1", "span-1"))
+
+	line := decodeLines(t, buf)[0]
+	if line["level"] != "warn" || line["log_type"] != "drift" {
+		t.Errorf("level, log_type = %v, %v, want warn, drift", line["level"], line["log_type"])
+	}
+
+	want := []interface{}{
+		map[string]interface{}{"resource": "deployment/order-service", "field": "replicas", "desired": float64(3), "actual": float64(1)},
+		map[string]interface{}{"resource": "configmap/order-service", "field": "LOG_LEVEL", "desired": "info", "actual": "debug"},
+	}
+	if got := line["data"].(map[string]interface{})["drift"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("drift = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package slog
+
+import "sync/atomic"
+
+const (
+	DurationNegative    = "negative"
+	DurationImplausible = "implausible"
+)
+
+// DurationAnomalies counts the durations a logger flagged with a duration_anomaly
+// since it was first configured, see SukiLogger.DurationAnomalies.
+type DurationAnomalies struct {
+	Negative    int64 `json:"negative"`
+	Implausible int64 `json:"implausible"`
+}
+
+// durationStats are the counters behind DurationAnomalies, shared by the copies of a logger.
+type durationStats struct {
+	negative    int64
+	implausible int64
+}
+
+// checkDuration validates the duration of a log when it is written, whatever
+// built it: a negative duration, usually caused by a clock adjustment, is
+// clamped to 0 and flagged "negative", one above MaxPlausibleDurationMs is kept
+// and flagged "implausible". It returns the duration_anomaly of the log and
+// counts it in the DurationAnomalies of s.
+func (s SukiLogger) checkDuration(duration *float64) string {
+	switch {
+	case *duration < 0:
+		*duration = 0
+		if s.durationStats != nil {
+			atomic.AddInt64(&s.durationStats.negative, 1)
+		}
+		return DurationNegative
+	case s.implausibleDuration(*duration):
+		if s.durationStats != nil {
+			atomic.AddInt64(&s.durationStats.implausible, 1)
+		}
+		return DurationImplausible
+	}
+	return ""
+}
+
+// implausibleDuration reports whether duration exceeds the configured MaxPlausibleDurationMs.
+func (s SukiLogger) implausibleDuration(duration float64) bool {
+	return s.config.MaxPlausibleDurationMs > 0 && duration > s.config.MaxPlausibleDurationMs
+}
+
+// DurationAnomalies returns the number of durations flagged with each duration_anomaly so far.
+func (s SukiLogger) DurationAnomalies() DurationAnomalies {
+	if s.durationStats == nil {
+		return DurationAnomalies{}
+	}
+	return DurationAnomalies{
+		Negative:    atomic.LoadInt64(&s.durationStats.negative),
+		Implausible: atomic.LoadInt64(&s.durationStats.implausible),
+	}
+}
@@ -0,0 +1,49 @@
+package slog
+
+import "testing"
+
+func TestDurationAnomaliesCounted(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, -3, ""))
+	logger.RequestKafka("kafka", KafkaMessage{}, WithKafkaResult(9.2e12))
+	logger.Lock("orders:42", true, -1)
+	logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, 12, ""))
+
+	want := DurationAnomalies{Negative: 2, Implausible: 1}
+	if got := logger.DurationAnomalies(); got != want {
+		t.Errorf("DurationAnomalies() = %+v, want %+v", got, want)
+	}
+
+	lock := decodeLines(t, buf)[2]["data"].(map[string]interface{})["lock"].(map[string]interface{})
+	if lock["wait_ms"] != float64(0) || lock["wait_anomaly"] != DurationNegative {
+		t.Errorf("wait_ms, wait_anomaly = %v, %v, want 0, %v", lock["wait_ms"], lock["wait_anomaly"], DurationNegative)
+	}
+}
+
+func TestCheckDuration(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxPlausible float64
+		duration     float64
+		wantDuration float64
+		wantAnomaly  string
+	}{
+		{name: "Clean", maxPlausible: 1000, duration: 12, wantDuration: 12},
+		{name: "Negative", maxPlausible: 1000, duration: -3, wantDuration: 0, wantAnomaly: DurationNegative},
+		{name: "Implausible", maxPlausible: 1000, duration: 5000, wantDuration: 5000, wantAnomaly: DurationImplausible},
+		{name: "No ceiling", duration: 9.2e12, wantDuration: 9.2e12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewProductionConfig()
+			config.MaxPlausibleDurationMs = tt.maxPlausible
+			logger, _ := newTestLogger(config)
+
+			duration := tt.duration
+			if anomaly := logger.checkDuration(&duration); anomaly != tt.wantAnomaly || duration != tt.wantDuration {
+				t.Errorf("checkDuration(%v) = %v, %q, want %v, %q", tt.duration, duration, anomaly, tt.wantDuration, tt.wantAnomaly)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DurationSummary accumulates the durations of the items of a batch job to be
+// written as a single summary log rather than a log per item.
+type DurationSummary struct {
+	logger *SukiLogger
+
+	mu        sync.Mutex
+	durations []time.Duration
+}
+
+// DurationStats is the data.summary of a summary log, all values but Count are in milliseconds.
+type DurationStats struct {
+	Count int     `json:"count"`
+	MinMs float64 `json:"min_ms"`
+	MaxMs float64 `json:"max_ms"`
+	AvgMs float64 `json:"avg_ms"`
+	P95Ms float64 `json:"p95_ms"`
+}
+
+// NewDurationSummary returns an empty DurationSummary, e.g.
+//
+//	summary := slog.L().NewDurationSummary()
+//	for _, order := range orders {
+//		start := time.Now()
+//		importOrder(order)
+//		summary.Add(time.Since(start))
+//	}
+//	summary.Log("import orders")
+func (s *SukiLogger) NewDurationSummary() *DurationSummary {
+	return &DurationSummary{logger: s}
+}
+
+func (d *DurationSummary) Add(duration time.Duration) {
+	d.mu.Lock()
+	d.durations = append(d.durations, duration)
+	d.mu.Unlock()
+}
+
+// Stats returns the stats of the durations added so far, p95 is the nearest-rank percentile.
+func (d *DurationSummary) Stats() DurationStats {
+	d.mu.Lock()
+	sorted := append([]time.Duration(nil), d.durations...)
+	d.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return DurationStats{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, duration := range sorted {
+		total += duration
+	}
+	p95 := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+
+	return DurationStats{
+		Count: len(sorted),
+		MinMs: toMillis(sorted[0]),
+		MaxMs: toMillis(sorted[len(sorted)-1]),
+		AvgMs: toMillis(total / time.Duration(len(sorted))),
+		P95Ms: toMillis(sorted[p95]),
+	}
+}
+
+// Log writes a summary log of the stats of the durations added so far.
+func (d *DurationSummary) Log(message string, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := d.logger.requestArgs(data, args)
+	data["summary"] = d.Stats()
+
+	if ce := d.logger.sampledLogger("summary", alertLevel, zapcore.InfoLevel, message, data).Check(zapcore.InfoLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(d.logger.envelope(alertLevel, data)...)
+	}
+}
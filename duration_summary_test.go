@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDurationSummaryStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      DurationStats
+	}{
+		{
+			name: "No durations",
+			want: DurationStats{},
+		},
+		{
+			name:      "One duration",
+			durations: []time.Duration{250 * time.Millisecond},
+			want:      DurationStats{Count: 1, MinMs: 250, MaxMs: 250, AvgMs: 250, P95Ms: 250},
+		},
+		{
+			name:      "Unordered durations",
+			durations: []time.Duration{30 * time.Millisecond, 10 * time.Millisecond, 1500 * time.Microsecond, 20 * time.Millisecond},
+			want:      DurationStats{Count: 4, MinMs: 1.5, MaxMs: 30, AvgMs: 15.375, P95Ms: 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := newTestLogger(NewProductionConfig())
+			summary := logger.NewDurationSummary()
+			for _, d := range tt.durations {
+				summary.Add(d)
+			}
+
+			if got := summary.Stats(); got != tt.want {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationSummaryP95(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	summary := logger.NewDurationSummary()
+	for ms := 100; ms >= 1; ms-- {
+		summary.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	if got := summary.Stats(); got.P95Ms != 95 || got.AvgMs != 50.5 || got.Count != 100 {
+		t.Errorf("Stats() = %+v, want p95 95, avg 50.5 and count 100", got)
+	}
+}
+
+func TestDurationSummaryLog(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	summary := logger.NewDurationSummary()
+	summary.Add(10 * time.Millisecond)
+	summary.Add(30 * time.Millisecond)
+
+	summary.Log("import orders", WithTracing("trace", "span"))
+
+	line := decodeLines(t, buf)[0]
+	if line["log_type"] != "summary" || line["level"] != "info" || line["message"] != "import orders" {
+		t.Errorf("log_type, level, message = %v, %v, %v, want summary, info, import orders", line["log_type"], line["level"], line["message"])
+	}
+	want := map[string]interface{}{
+		"count":  float64(2),
+		"min_ms": float64(10),
+		"max_ms": float64(30),
+		"avg_ms": float64(20),
+		"p95_ms": float64(30),
+	}
+	if got := line["data"].(map[string]interface{})["summary"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("summary = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+package slog
+
+import (
+	"time"
+)
+
+// measureEncoding returns data with _encode_ms, the milliseconds taken to marshal it.
+// The wall clock is used rather than the logger clock as it times the work itself.
+func measureEncoding(data map[string]interface{}) map[string]interface{} {
+	start := time.Now()
+	_, _ = marshalNoEscape(data)
+	elapsed := time.Since(start)
+
+	result := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		result[k] = v
+	}
+	result["_encode_ms"] = toMillis(elapsed)
+	return result
+}
@@ -0,0 +1,36 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestMeasureEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		measure bool
+	}{
+		{name: "Enabled", measure: true},
+		{name: "Disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MeasureEncoding = tt.measure
+			logger, buf := newTestLogger(c)
+
+			logger.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "", nil, nil, nil, `{"sku":"A1"}`), WithHTTPResponse(200, 1, "ok"))
+			logger.Event("event", WithEvent("order", ActionCreate, ResultSuccess, map[string]string{"sku": "A1"}, "1"))
+			logger.Info("app", Any("sku", "A1"))
+
+			for _, line := range decodeLines(t, buf) {
+				ms, ok := line["data"].(map[string]interface{})["_encode_ms"]
+				if ok != tt.measure {
+					t.Fatalf("%v: _encode_ms present %v, want %v", line["log_type"], ok, tt.measure)
+				}
+				if ok && ms.(float64) <= 0 {
+					t.Errorf("%v: _encode_ms = %v, want positive", line["log_type"], ms)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package slog
+
+import "encoding/json"
+
+// decode unmarshals the data field key of e into v, it reports false when the
+// field is missing or does not have the shape of v.
+func (e Entry) decode(key string, v interface{}) bool {
+	value, ok := e.Data[key]
+	if !ok || value == nil {
+		return false
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(b, v) == nil
+}
+
+// Tracing returns the data.tracing of e.
+func (e Entry) Tracing() (TraceInfo, bool) {
+	var info TraceInfo
+	ok := e.decode("tracing", &info)
+	return info, ok
+}
+
+// TraceID returns the trace id of e, empty without data.tracing.
+func (e Entry) TraceID() string {
+	info, _ := e.Tracing()
+	return info.TraceID
+}
+
+// SpanID returns the span id of e, empty without data.tracing.
+func (e Entry) SpanID() string {
+	info, _ := e.Tracing()
+	return info.SpanID
+}
+
+// RequestID returns the request id of e, empty without data.tracing.
+func (e Entry) RequestID() string {
+	info, _ := e.Tracing()
+	return info.RequestID
+}
+
+// TenantID returns the data.tenant_id of e, empty when missing.
+func (e Entry) TenantID() string {
+	tenantID, _ := e.Data["tenant_id"].(string)
+	return tenantID
+}
+
+// User returns the data.user of e.
+func (e Entry) User() (UserInfo, bool) {
+	var info UserInfo
+	ok := e.decode("user", &info)
+	return info, ok
+}
+
+// HTTPRequest returns the data.http_request of a handler.http or client.http entry.
+func (e Entry) HTTPRequest() (HTTPRequestInfo, bool) {
+	var info HTTPRequestInfo
+	ok := e.decode("http_request", &info)
+	return info, ok
+}
+
+// HTTPResponse returns the data.http_response of a handler.http or client.http entry.
+func (e Entry) HTTPResponse() (HTTPResponseInfo, bool) {
+	var info HTTPResponseInfo
+	ok := e.decode("http_response", &info)
+	return info, ok
+}
+
+// KafkaMessage returns the data.kafka_message of a handler.kafka entry.
+func (e Entry) KafkaMessage() (KafkaMessage, bool) {
+	var message KafkaMessage
+	ok := e.decode("kafka_message", &message)
+	return message, ok
+}
+
+// KafkaResult returns the data.kafka_result of a handler.kafka entry.
+func (e Entry) KafkaResult() (KafkaResult, bool) {
+	var result KafkaResult
+	ok := e.decode("kafka_result", &result)
+	return result, ok
+}
+
+// DBQuery returns the data.db_query of a client.db entry.
+func (e Entry) DBQuery() (DBQuery, bool) {
+	var query DBQuery
+	ok := e.decode("db_query", &query)
+	return query, ok
+}
+
+// DBResult returns the data.db_result of a client.db entry.
+func (e Entry) DBResult() (DBResult, bool) {
+	var result DBResult
+	ok := e.decode("db_result", &result)
+	return result, ok
+}
+
+// Event returns the data.event of an event entry.
+func (e Entry) Event() (EventLog, bool) {
+	var event EventLog
+	ok := e.decode("event", &event)
+	return event, ok
+}
+
+// Timer returns the data.timer of a timer entry.
+func (e Entry) Timer() (TimerInfo, bool) {
+	var info TimerInfo
+	ok := e.decode("timer", &info)
+	return info, ok
+}
+
+// Audit returns the data.audit of an audit entry.
+func (e Entry) Audit() (AuditInfo, bool) {
+	var info AuditInfo
+	ok := e.decode("audit", &info)
+	return info, ok
+}
+
+// Payment returns the data.payment of a payment entry.
+func (e Entry) Payment() (PaymentInfo, bool) {
+	var info PaymentInfo
+	ok := e.decode("payment", &info)
+	return info, ok
+}
+
+// Usage returns the data.usage of a usage entry.
+func (e Entry) Usage() (UsageInfo, bool) {
+	var info UsageInfo
+	ok := e.decode("usage", &info)
+	return info, ok
+}
+
+// Notification returns the data.notification of a notification entry.
+func (e Entry) Notification() (NotificationInfo, bool) {
+	var info NotificationInfo
+	ok := e.decode("notification", &info)
+	return info, ok
+}
+
+// Rollout returns the data.rollout of a rollout entry.
+func (e Entry) Rollout() (RolloutInfo, bool) {
+	var info RolloutInfo
+	ok := e.decode("rollout", &info)
+	return info, ok
+}
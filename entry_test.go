@@ -0,0 +1,258 @@
+package slog
+
+import (
+	"context"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEntryAccessors(t *testing.T) {
+	sent := time.Date(2024, 3, 1, 11, 59, 0, 0, time.UTC)
+	request := WithHTTPRequest("POST", "/orders", "127.0.0.1", map[string]string{"Accept": "application/json"}, nil, map[string]string{"page": "1"}, `{"id":1}`)
+	response := WithHTTPResponse(201, 12.5, `{"ok":true}`)
+	message := WithKafkaMessage("orders", 1, 42, nil, "order-1", `{"id":1}`, sent)
+
+	tests := []struct {
+		name string
+		emit func(s *SukiLogger, clock *slogtest.FakeClock)
+		get  func(e Entry) (interface{}, bool)
+		want interface{}
+	}{
+		{
+			name: "Tracing",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Info("traced", WithTracing("trace-1", "span-1", "request-1"))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Tracing()
+				return []interface{}{info, e.TraceID(), e.SpanID(), e.RequestID()}, ok
+			},
+			want: []interface{}{TraceInfo{TraceID: "trace-1", SpanID: "span-1", RequestID: "request-1"}, "trace-1", "span-1", "request-1"},
+		},
+		{
+			name: "Tenant and user",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Info("tenant", ContextWithTenant(context.Background(), "shop-1"), WithUser("user-1", "admin"))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				user, ok := e.User()
+				return []interface{}{e.TenantID(), user}, ok
+			},
+			want: []interface{}{"shop-1", WithUser("user-1", "admin")},
+		},
+		{
+			name: "HTTP request",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestHTTP("http", request, response)
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.HTTPRequest()
+				return info, ok
+			},
+			want: request,
+		},
+		{
+			name: "HTTP response",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestHTTP("http", request, response)
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.HTTPResponse()
+				return info, ok
+			},
+			want: response,
+		},
+		{
+			name: "Kafka message",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestKafka("kafka", message, WithKafkaResult(3))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.KafkaMessage()
+				return info, ok
+			},
+			want: message,
+		},
+		{
+			name: "Kafka result",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestKafka("kafka", message, WithKafkaResult(3))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.KafkaResult()
+				return info, ok
+			},
+			want: WithKafkaResult(3),
+		},
+		{
+			name: "DB query",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestDB("db", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(2, 1))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.DBQuery()
+				return info, ok
+			},
+			want: WithDBQuery("postgresql", "SELECT 1"),
+		},
+		{
+			name: "DB result",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.RequestDB("db", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(2, 1))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.DBResult()
+				return info, ok
+			},
+			want: WithDBResult(2, 1),
+		},
+		{
+			name: "Event",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Event("event", WithEvent("order", ActionCreate, ResultSuccess, map[string]int{"id": 1}, "order-1"))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Event()
+				return info, ok
+			},
+			want: WithEvent("order", ActionCreate, ResultSuccess, map[string]int{"id": 1}, "order-1"),
+		},
+		{
+			name: "Timer",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				timer := s.StartTimer("checkout")
+				clock.Advance(250 * time.Millisecond)
+				timer.Stop()
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Timer()
+				return info, ok
+			},
+			want: TimerInfo{Name: "checkout", Duration: 250},
+		},
+		{
+			name: "Audit",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Audit(WithAudit("admin-1", "update", "order/1", map[string]interface{}{"status": "pending"}, map[string]interface{}{"status": "paid"}).Diff())
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Audit()
+				return info, ok
+			},
+			want: AuditInfo{Actor: "admin-1", Action: "update", Resource: "order/1", Changes: []AuditChange{{Field: "status", Before: "pending", After: "paid"}}},
+		},
+		{
+			name: "Payment",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Payment(PaymentCaptured, WithPayment("txn-1", "199.90", "THB", "captured", "omise"))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Payment()
+				return info, ok
+			},
+			want: WithPayment("txn-1", "199.90", "THB", "captured", "omise"),
+		},
+		{
+			name: "Usage",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Usage(WithUsage("shop-1", "orders.created", 3, "order"))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Usage()
+				return info, ok
+			},
+			want: WithUsage("shop-1", "orders.created", 3, "order"),
+		},
+		{
+			name: "Notification",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Notification(WithNotification("email", "hashed", "welcome", NotificationSent))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Notification()
+				return info, ok
+			},
+			want: WithNotification("email", hashRecipient("hashed"), "welcome", NotificationSent),
+		},
+		{
+			name: "Rollout",
+			emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+				s.Rollout(WithRollout("new-checkout", 25, 10))
+			},
+			get: func(e Entry) (interface{}, bool) {
+				info, ok := e.Rollout()
+				return info, ok
+			},
+			want: WithRollout("new-checkout", 25, 10),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			logger, _ := newTestLogger(c)
+
+			entries := logger.Capture(func() { tt.emit(logger, clock) })
+			if len(entries) != 1 {
+				t.Fatalf("%d entries, want 1", len(entries))
+			}
+
+			got, ok := tt.get(entries[0])
+			if !ok {
+				t.Fatal("accessor reported the field missing")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntryAccessorsMissingOrMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+	}{
+		{
+			name: "No data",
+		},
+		{
+			name: "Missing fields",
+			data: map[string]interface{}{"payload": map[string]interface{}{"id": 1}},
+		},
+		{
+			name: "Null fields",
+			data: map[string]interface{}{"tracing": nil, "http_request": nil, "db_query": nil},
+		},
+		{
+			name: "Malformed fields",
+			data: map[string]interface{}{
+				"tracing":      "trace-1",
+				"tenant_id":    1,
+				"http_request": map[string]interface{}{"method": 1},
+				"db_query":     []interface{}{"SELECT 1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{Data: tt.data}
+
+			if _, ok := e.Tracing(); ok {
+				t.Error("Tracing() ok = true, want false")
+			}
+			if e.TraceID() != "" || e.TenantID() != "" {
+				t.Errorf("TraceID(), TenantID() = %q, %q, want empty", e.TraceID(), e.TenantID())
+			}
+			if _, ok := e.HTTPRequest(); ok {
+				t.Error("HTTPRequest() ok = true, want false")
+			}
+			if _, ok := e.DBQuery(); ok {
+				t.Error("DBQuery() ok = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package slog
+
+import (
+	"io"
+	"regexp"
+	"testing"
+)
+
+var volatileFields = regexp.MustCompile(`"(timestamp|caller)":"[^"]*"`)
+
+// emitEnvelopeSamples writes one log of every log_type through logger.
+func emitEnvelopeSamples(logger *SukiLogger) {
+	trace := WithTracing("trace-1", "span-1", "request-1")
+
+	logger.Info("application log", Any("order_id", 1), trace)
+	logger.Warn("alerting application log", LogOption{Alert: LevelAlert})
+	logger.Event("event log", WithEvent("order", ActionUpdate, ResultSuccess, map[string]interface{}{"status": "paid"}, "order-1"), trace)
+	logger.RequestHTTP(
+		"http log",
+		WithHTTPRequest("GET", "/orders", "127.0.0.1", nil, nil, map[string]string{"page": "1"}, ""),
+		WithHTTPResponse(200, 12.5, `{"ok":true}`),
+		trace,
+	)
+	logger.RequestKafka(
+		"kafka log",
+		KafkaMessage{Topic: "orders", Partition: 1, Offset: 2, Key: "k", Payload: "{}"},
+		WithKafkaResult(3, WithError("timeout")),
+		LogOption{Alert: LevelAlert},
+	)
+	logger.Batch("batch log", WithBatchResult(4, 3, WithBatchItemError("a", WithError("boom"))))
+	_ = logger.Usage(WithUsage("shop-1", "orders", 1, "order"))
+
+	buf := logger.NewRequestBuffer()
+	buf.Info("step", Any("n", 1))
+	buf.Flush("request trace log")
+}
+
+// envelopeGolden is the output of emitEnvelopeSamples with timestamp and caller masked,
+// it pins the emitted JSON byte for byte.
+var envelopeGolden = `{"level":"info","timestamp":"-","caller":"-","message":"application log","app_name":"shop","version":"1.2.3","log_type":"application","alert":0,"data":{"shop":{"order_id":1},"tracing":{"trace_id":"trace-1","span_id":"span-1","request_id":"request-1"}}}
+{"level":"warn","timestamp":"-","caller":"-","message":"alerting application log","app_name":"shop","version":"1.2.3","log_type":"application","alert":1,"data":{}}
+{"level":"info","timestamp":"-","caller":"-","message":"event log","app_name":"shop","version":"1.2.3","log_type":"event","alert":0,"data":{"event":{"entity":"order","action":"update","result":"success","reference_id":"order-1","data":"{\"status\":\"paid\"}"},"tracing":{"trace_id":"trace-1","span_id":"span-1","request_id":""}}}
+{"level":"info","timestamp":"-","caller":"-","message":"http log","app_name":"shop","version":"1.2.3","log_type":"handler.http","alert":0,"data":{"http_request":{"method":"GET","path":"/orders","remote_ip":"127.0.0.1","headers":{},"params":{},"query":{"page":"1"},"body":""},"http_response":{"status":200,"duration":12.5,"body":"{\"ok\":true}","error":{"name":"","stack_trace":""}},"tracing":{"trace_id":"trace-1","span_id":"span-1","request_id":""}}}
+{"level":"info","timestamp":"-","caller":"-","message":"kafka log","app_name":"shop","version":"1.2.3","log_type":"handler.kafka","alert":1,"data":{"kafka_message":{"topic":"orders","partition":1,"offset":2,"headers":null,"key":"k","payload":"{}","timestamp":"-"},"kafka_result":{"duration":3,"error":{"name":"timeout","stack_trace":""}}}}
+{"level":"warn","timestamp":"-","caller":"-","message":"batch log","app_name":"shop","version":"1.2.3","log_type":"batch","alert":0,"data":{"batch":{"total":4,"succeeded":3,"failed":1,"error_breakdown":{"boom":1},"errors":[{"id":"a","error":{"name":"boom","stack_trace":""}}]}}}
+{"level":"info","timestamp":"-","caller":"-","message":"usage","app_name":"shop","version":"1.2.3","log_type":"usage","alert":0,"data":{"usage":{"account":"shop-1","metric":"orders","quantity":1,"unit":"order"}}}
+{"level":"info","timestamp":"-","caller":"-","message":"request trace log","app_name":"shop","version":"1.2.3","log_type":"request_trace","alert":0,"data":{"entries":[{"level":"info","timestamp":"-","message":"step","fields":{"n":1}}]}}
+`
+
+func TestEnvelopeGolden(t *testing.T) {
+	c := NewProductionConfig()
+	c.AppName = "shop"
+	c.Version = "1.2.3"
+	c.LogLevel = LevelDebug
+	logger, buf := newTestLogger(c)
+
+	emitEnvelopeSamples(logger)
+
+	got := volatileFields.ReplaceAllString(buf.String(), `"$1":"-"`)
+	if got != envelopeGolden {
+		t.Errorf("output differs from golden\ngot:\n%s\nwant:\n%s", got, envelopeGolden)
+	}
+}
+
+func newBenchmarkLogger(b *testing.B) *SukiLogger {
+	b.Helper()
+	c := NewProductionConfig()
+	c.Output = io.Discard
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		b.Fatal(err)
+	}
+	return logger
+}
+
+func BenchmarkInfo(b *testing.B) {
+	logger := newBenchmarkLogger(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("benchmark", Any("i", i), LogOption{Alert: LevelAlert})
+	}
+}
+
+func BenchmarkRequestHTTP(b *testing.B) {
+	logger := newBenchmarkLogger(b)
+	request := WithHTTPRequest("GET", "/orders", "127.0.0.1", nil, nil, nil, "")
+	response := WithHTTPResponse(200, 12.5, "")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.RequestHTTP("benchmark", request, response, LogOption{Alert: LevelAlert})
+	}
+}
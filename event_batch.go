@@ -0,0 +1,112 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+// DefaultEventBatchSize is the number of events of a full batch when EventBatcherOption.MaxEvents is unset.
+const DefaultEventBatchSize = 100
+
+type EventBatcherOption struct {
+	// MaxEvents flushes the batch once it holds MaxEvents events, DefaultEventBatchSize when 0.
+	MaxEvents int
+	// FlushInterval flushes the batch FlushInterval after its first event (0 = Only when full).
+	FlushInterval time.Duration
+}
+
+// EventBatcher accumulates events to write them as a single event_batch log
+// holding data.events, for bulk processors emitting events in a tight loop.
+type EventBatcher struct {
+	logger  *SukiLogger
+	message string
+	args    []interface{}
+	opts    EventBatcherOption
+
+	mu     sync.Mutex
+	events []EventLog
+	timer  clock.Timer
+	closed bool
+}
+
+// NewEventBatcher returns an EventBatcher writing its batches with message and args, e.g.
+//
+//	b := slog.L().NewEventBatcher("orders imported", slog.EventBatcherOption{FlushInterval: time.Second})
+//	defer b.Close()
+func (s *SukiLogger) NewEventBatcher(message string, opts EventBatcherOption, args ...interface{}) *EventBatcher {
+	if opts.MaxEvents <= 0 {
+		opts.MaxEvents = DefaultEventBatchSize
+	}
+	return &EventBatcher{logger: s, message: message, args: args, opts: opts}
+}
+
+// Add adds event to the batch, flushing it when full. An event added after
+// Close is written at once in a batch of its own.
+func (b *EventBatcher) Add(event EventLog) {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		b.write([]EventLog{event})
+		return
+	}
+
+	b.events = append(b.events, event)
+	if len(b.events) >= b.opts.MaxEvents {
+		events := b.take()
+		b.mu.Unlock()
+		b.write(events)
+		return
+	}
+
+	if b.timer == nil && b.opts.FlushInterval > 0 {
+		b.timer = b.logger.clock().AfterFunc(b.opts.FlushInterval, b.Flush)
+	}
+	b.mu.Unlock()
+}
+
+// Flush writes the events added so far, nothing is written when there are none.
+func (b *EventBatcher) Flush() {
+	b.mu.Lock()
+	events := b.take()
+	b.mu.Unlock()
+
+	b.write(events)
+}
+
+// Close flushes the remaining events and stops the periodic flush.
+func (b *EventBatcher) Close() {
+	b.mu.Lock()
+	b.closed = true
+	events := b.take()
+	b.mu.Unlock()
+
+	b.write(events)
+}
+
+// take empties the batch and stops its pending flush, b.mu must be held.
+func (b *EventBatcher) take() []EventLog {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	events := b.events
+	b.events = nil
+	return events
+}
+
+func (b *EventBatcher) write(events []EventLog) {
+	if len(events) == 0 {
+		return
+	}
+
+	data := make(map[string]interface{})
+	alertLevel := b.logger.requestArgs(data, b.args)
+	data["events"] = events
+
+	if ce := b.logger.sampledLogger("event_batch", alertLevel, zapcore.InfoLevel, b.message, data).Check(zapcore.InfoLevel, b.message); ce != nil {
+		withCaller(ce, b.args)
+		ce.Write(b.logger.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,179 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchSizes returns the number of events of every event_batch line.
+func batchSizes(t *testing.T, lines []map[string]interface{}) []int {
+	t.Helper()
+	var sizes []int
+	for _, line := range lines {
+		if line["log_type"] != "event_batch" {
+			t.Fatalf("log_type = %v, want event_batch", line["log_type"])
+		}
+		sizes = append(sizes, len(line["data"].(map[string]interface{})["events"].([]interface{})))
+	}
+	return sizes
+}
+
+func TestEventBatcher(t *testing.T) {
+	event := WithEvent("order", ActionCreate, ResultSuccess, nil, "ref")
+
+	tests := []struct {
+		name      string
+		opts      EventBatcherOption
+		run       func(b *EventBatcher, clock *slogtest.FakeClock)
+		wantSizes []int
+	}{
+		{
+			name: "Flushed when full",
+			opts: EventBatcherOption{MaxEvents: 3},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				for i := 0; i < 7; i++ {
+					b.Add(event)
+				}
+			},
+			wantSizes: []int{3, 3},
+		},
+		{
+			name: "Final flush on Close",
+			opts: EventBatcherOption{MaxEvents: 3},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				for i := 0; i < 7; i++ {
+					b.Add(event)
+				}
+				b.Close()
+			},
+			wantSizes: []int{3, 3, 1},
+		},
+		{
+			name: "Flushed an interval after the first event",
+			opts: EventBatcherOption{MaxEvents: 10, FlushInterval: time.Second},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				b.Add(event)
+				clock.Advance(600 * time.Millisecond)
+				b.Add(event)
+				clock.Advance(400 * time.Millisecond)
+				b.Add(event)
+				clock.Advance(999 * time.Millisecond)
+				clock.Advance(time.Millisecond)
+			},
+			wantSizes: []int{2, 1},
+		},
+		{
+			name: "Full batch cancels the pending flush",
+			opts: EventBatcherOption{MaxEvents: 2, FlushInterval: time.Second},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				b.Add(event)
+				b.Add(event)
+				b.Add(event)
+				clock.Advance(500 * time.Millisecond)
+				b.Add(event)
+				clock.Advance(time.Second)
+			},
+			wantSizes: []int{2, 2},
+		},
+		{
+			name: "Explicit flush",
+			opts: EventBatcherOption{},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				b.Add(event)
+				b.Flush()
+				b.Flush()
+			},
+			wantSizes: []int{1},
+		},
+		{
+			name: "Add after Close is written at once",
+			opts: EventBatcherOption{},
+			run: func(b *EventBatcher, clock *slogtest.FakeClock) {
+				b.Close()
+				b.Add(event)
+				b.Close()
+			},
+			wantSizes: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			logger, buf := newTestLogger(c)
+
+			tt.run(logger.NewEventBatcher("events", tt.opts), clock)
+
+			var sizes []int
+			if buf.Len() > 0 {
+				sizes = batchSizes(t, decodeLines(t, buf))
+			}
+			if !reflect.DeepEqual(sizes, tt.wantSizes) {
+				t.Errorf("batch sizes = %v, want %v", sizes, tt.wantSizes)
+			}
+			if pending := clock.Pending(); len(pending) != 0 {
+				t.Errorf("pending flushes = %v, want none", pending)
+			}
+		})
+	}
+}
+
+func TestEventBatcherLine(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	b := logger.NewEventBatcher("orders imported", EventBatcherOption{}, WithTracing("trace-1", "span-1"))
+
+	b.Add(WithEvent("order", ActionCreate, ResultSuccess, nil, "1"))
+	b.Add(WithEvent("order", ActionCreate, ResultCompensate, nil, "2"))
+	b.Close()
+
+	line := decodeLines(t, buf)[0]
+	if line["message"] != "orders imported" || line["level"] != "info" {
+		t.Errorf("message, level = %v, %v, want orders imported, info", line["message"], line["level"])
+	}
+
+	data := line["data"].(map[string]interface{})
+	events := data["events"].([]interface{})
+	if events[0].(map[string]interface{})["reference_id"] != "1" || events[1].(map[string]interface{})["result"] != string(ResultCompensate) {
+		t.Errorf("events = %v, want both events in order", events)
+	}
+	if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+		t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+	}
+}
+
+func TestEventBatcherLosesNoEvents(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+	b := logger.NewEventBatcher("events", EventBatcherOption{MaxEvents: 7, FlushInterval: time.Second})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				b.Add(WithEvent("order", ActionCreate, ResultSuccess, nil, strconv.Itoa(i*50+j)))
+				if j%10 == 0 {
+					clock.Advance(300 * time.Millisecond)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	b.Close()
+
+	total := 0
+	for _, size := range batchSizes(t, decodeLines(t, buf)) {
+		total += size
+	}
+	if total != 500 {
+		t.Errorf("%d events written, want 500", total)
+	}
+}
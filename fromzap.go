@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+)
+
+// NewFromZap returns a logger writing its envelope through z instead of a zap
+// logger built by Configure, so the cores and options of an existing zap
+// logger are kept while migrating to the logger, e.g.
+//
+//	logger := slog.NewFromZap(zapLogger, slog.Config{AppName: "shop", Version: "1.2.3"})
+//
+// The level, encoding, sampling and output of z are used as is, which leaves
+// the Config fields only read to build the zap logger ignored: LogLevel,
+// Output, OnWriteError, Async, CallerKey, FunctionKey, StacktraceKey,
+// LevelNames, LineEnding, IncludeLineSize, IncludeMonotonic, IncludeUptime and
+// CloudEvents. FieldPrefix only applies to the envelope fields and
+// StackTraceSeparator to ErrorInfo.StackTrace. The alerting logs and the logs
+// of debug requests go through the level and sampling of z like the others.
+// Invalid RedactionRules are reported on stderr and not applied.
+func NewFromZap(z *zap.Logger, c Config) *SukiLogger {
+	c = withBuildInfo(c)
+
+	stderr := zapcore.Lock(os.Stderr)
+	s := &SukiLogger{
+		errorOutput:   stderr,
+		subscribers:   &subscribers{},
+		captures:      &captures{},
+		heartbeats:    &heartbeats{},
+		deprecations:  &deprecations{},
+		durationStats: &durationStats{},
+		started:       c.clock().Now(),
+	}
+	if err := c.RedactionRules.compile(); err != nil {
+		s.internalError(fmt.Errorf("redaction rules not applied: %w", err))
+		c.RedactionRules = RedactionRules{}
+	}
+
+	encoder := zapcore.NewJSONEncoder(newZapConfig(zapcore.DebugLevel, c).EncoderConfig)
+	opts := []zap.Option{
+		zap.AddCallerSkip(1),
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(
+				core,
+				newSubscriberCore(core, s.subscribers, c.FieldPrefix),
+				newCaptureCore(encoder, s.captures, c.FieldPrefix),
+			)
+		}),
+	}
+	if c.Clock != nil {
+		opts = append(opts, zap.WithClock(zapClock{Clock: c.Clock}))
+	}
+
+	logger := z.WithOptions(opts...)
+	s.zapInstance = logger
+	s.alertInstance = logger
+	s.debugInstance = logger
+	s.config = c
+	s.buildEnvelopes()
+
+	if !c.SuppressConfigWarnings {
+		s.warnConfigIssues()
+	}
+	return s
+}
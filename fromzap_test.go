@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"bytes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+// newCustomZap returns a zap logger with its own encoding, level and fields, as a team migrating would have.
+func newCustomZap(buf *bytes.Buffer) *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.MessageKey = "msg"
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(buf), zapcore.InfoLevel)
+	return zap.New(core, zap.AddCaller(), zap.Fields(zap.String("team", "checkout")))
+}
+
+func TestNewFromZap(t *testing.T) {
+	buf := &bytes.Buffer{}
+	c := NewProductionConfig()
+	c.AppName = "shop"
+	c.Version = "1.2.3"
+	c.LogLevel = LevelDebug
+	logger := NewFromZap(newCustomZap(buf), c)
+
+	logger.Debug("filtered by the zap logger")
+	logger.Info("application", Any("order_id", 1))
+	logger.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(1, 1))
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("%d logs, want 2", len(lines))
+	}
+	tests := []struct {
+		line    map[string]interface{}
+		msg     string
+		logType string
+		dataKey string
+	}{
+		{line: lines[0], msg: "application", logType: "application", dataKey: "shop"},
+		{line: lines[1], msg: "db query", logType: "client.db", dataKey: "db_query"},
+	}
+	for _, tt := range tests {
+		if tt.line["msg"] != tt.msg || tt.line["team"] != "checkout" {
+			t.Errorf("msg, team = %v, %v, want %s, checkout", tt.line["msg"], tt.line["team"], tt.msg)
+		}
+		if tt.line["log_type"] != tt.logType || tt.line["app_name"] != "shop" || tt.line["version"] != "1.2.3" {
+			t.Errorf("log_type, app_name, version = %v, %v, %v", tt.line["log_type"], tt.line["app_name"], tt.line["version"])
+		}
+		if _, ok := tt.line["data"].(map[string]interface{})[tt.dataKey]; !ok {
+			t.Errorf("data = %v, want %s", tt.line["data"], tt.dataKey)
+		}
+		if caller, _ := tt.line["caller"].(string); !strings.Contains(caller, "fromzap_test.go:") {
+			t.Errorf("caller = %v, want fromzap_test.go", tt.line["caller"])
+		}
+	}
+}
+
+func TestNewFromZapCapture(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewFromZap(newCustomZap(buf), NewProductionConfig())
+
+	entries := logger.Capture(func() {
+		logger.Info("captured", WithTracing("trace-1", "span-1"))
+	})
+
+	if len(entries) != 1 || entries[0].Message != "captured" || entries[0].TraceID() != "trace-1" {
+		t.Errorf("entries = %+v, want the captured log", entries)
+	}
+	if lines := decodeLines(t, buf); len(lines) != 1 {
+		t.Errorf("%d logs, want 1", len(lines))
+	}
+}
@@ -2,10 +2,12 @@ module github.com/Sellsuki/sellsuki-go-logger
 
 go 1.18
 
-require go.uber.org/zap v1.24.0
+require (
+	github.com/pkg/errors v0.9.1
+	go.uber.org/zap v1.24.0
+)
 
 require (
-	github.com/pkg/errors v0.9.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
 )
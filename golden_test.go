@@ -0,0 +1,450 @@
+package slog
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of TestGolden")
+
+// goldenVolatile matches the values depending on the machine or the line numbers of the tests.
+var goldenVolatile = regexp.MustCompile(`"(?:\w*_)?(?:caller|stacktrace)":"(?:[^"\\]|\\.)*"|"stack":\[[^\]]*\]|"goroutines":\d+`)
+
+type goldenCase struct {
+	name   string
+	config func(c *Config)
+	emit   func(s *SukiLogger, clock *slogtest.FakeClock)
+}
+
+// goldenCases write every log_type with representative inputs, along with
+// their masking, truncation and escalation variants. Each case is compared to
+// testdata/golden/<name>.golden, run `go test -run TestGolden . -update` to
+// rewrite the files after a deliberate change of the output.
+var goldenCases = []goldenCase{
+	{
+		name: "application",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			trace := WithTracing("trace-1", "span-1", "request-1")
+			s.Debug("debug log", Any("step", "validate"))
+			s.Info("info log", Any("order_id", 1), trace, WithExperiment("checkout", "b"))
+			s.Warn("alerting log", LogOption{Alert: LevelAlert})
+			s.Info("truncated ids", IDs("order_ids", []string{"1", "2", "3", "4", "5"}, 2))
+			s.Error("error log", Error(errors.New("boom")))
+			s.InfoDetailed("short log", "detailed log", trace)
+		},
+	},
+	{
+		name: "event",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Event("event log", WithEvent("order", ActionUpdate, ResultSuccess, map[string]interface{}{"status": "paid"}, "order-1"), WithTracing("trace-1", "span-1"))
+			s.Event("compensated", WithEvent("order", ActionDelete, ResultCompensate, "raw", "order-2"))
+		},
+	},
+	{
+		name: "event_batch",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			b := s.NewEventBatcher("orders imported", EventBatcherOption{MaxEvents: 2})
+			b.Add(WithEvent("order", ActionCreate, ResultSuccess, nil, "1"))
+			b.Add(WithEvent("order", ActionCreate, ResultSuccess, nil, "2"))
+			b.Add(WithEvent("order", ActionCreate, ResultSuccess, nil, "3"))
+			b.Close()
+		},
+	},
+	{
+		name: "handler_http",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			request := WithHTTPRequest("POST", "/orders", "127.0.0.1", map[string]string{"Content-Type": "application/json"}, nil, map[string]string{"page": "1"}, `{"id":1}`)
+			s.RequestHTTP("http log", request, WithHTTPResponse(201, 12.5, `{"ok":true}`), WithTracing("trace-1", "span-1"))
+			s.RequestHTTP("negative duration", request, WithHTTPResponse(200, -1, ""))
+			s.RequestHTTP("implausible duration", request, WithHTTPResponse(200, 9.2e12, ""))
+		},
+	},
+	{
+		name: "handler_http_slow",
+		config: func(c *Config) {
+			c.SlowThresholdMs = map[string]float64{"handler.http": 100}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			request := WithHTTPRequest("GET", "/orders", "127.0.0.1", nil, nil, nil, "")
+			s.RequestHTTP("fast", request, WithHTTPResponse(200, 100, ""))
+			s.RequestHTTP("slow", request, WithHTTPResponse(200, 100.5, ""))
+		},
+	},
+	{
+		name: "handler_http_truncated",
+		config: func(c *Config) {
+			c.MaxBodySize = 16
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			request := WithHTTPRequest("POST", "/orders", "127.0.0.1", nil, nil, nil, `{"items":[1,2,3,4,5]}`)
+			s.RequestHTTP("truncated", request, WithHTTPResponse(200, 1, `{"ok":true}`))
+		},
+	},
+	{
+		name: "handler_http_masked",
+		config: func(c *Config) {
+			c.RedactPatterns = RedactPatterns{Email: true, Card: true}
+			c.RedactionRules = RedactionRules{
+				Version:       RedactionRulesVersion,
+				SensitiveKeys: []string{"password"},
+				Headers:       []HeaderRule{{Name: "Authorization", Action: HeaderMask}, {Name: "Cookie", Action: HeaderDrop}},
+			}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			request := WithHTTPRequest(
+				"POST",
+				"/login",
+				"127.0.0.1",
+				map[string]string{"Authorization": "Bearer secret", "Cookie": "session=1", "Accept": "*/*"},
+				nil,
+				nil,
+				`{"email":"jane@example.com","password":"hunter2","card":"4111111111111111"}`,
+			)
+			response := WithHTTPResponse(200, 1, `{"token":"abc"}`)
+			response.Trailers = map[string]string{"Grpc-Status": "5"}
+			s.RequestHTTP("masked", request, response)
+		},
+	},
+	{
+		name: "handler_kafka",
+		config: func(c *Config) {
+			c.MaxBodySize = 8
+			c.RedactPatterns = RedactPatterns{Email: true}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			message := WithKafkaMessage("orders", 1, 2, map[string]string{"source": "shop"}, "k", "jane@example.com", time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC))
+			s.RequestKafka("kafka log", message, WithKafkaResult(3), LogOption{Alert: LevelAlert})
+			s.RequestKafka("kafka error", message, WithKafkaResult(4, WithError("timeout")))
+			s.RequestKafka("deserialization", message, WithKafkaResult(1), WithDeserializationError(errors.New("invalid character"), `{"id":"not a number"}`, struct{ ID int }{}))
+		},
+	},
+	{
+		name: "client_http",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			limit, remaining := int64(100), int64(3)
+			request := WithHTTPRequest("GET", "/v2/orders", "", nil, nil, nil, "")
+			request.Host = "partner.example.com"
+			s.ClientHTTP("http client request", request, WithHTTPResponse(200, 25, ""))
+			s.ClientHTTP("http client request", request, WithHTTPResponse(200, 25, ""), QuotaInfo{Limit: &limit, Remaining: &remaining, BelowThreshold: true})
+		},
+	},
+	{
+		name: "client_db",
+		config: func(c *Config) {
+			c.SlowThresholdMs = map[string]float64{"client.db": 100}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			stats := DBPoolStats(func() sql.DBStats {
+				return sql.DBStats{MaxOpenConnections: 10, OpenConnections: 10, InUse: 10, WaitCount: 4, WaitDuration: 250 * time.Millisecond}
+			})
+			query := WithDBQuery("postgresql", "SELECT id FROM orders WHERE shop_id = $1")
+			s.RequestDB("db query", query, WithDBResult(2, 10), stats)
+			s.RequestDB("db query", query, WithDBResult(300, 10), stats)
+			s.RequestDB("db query", query, WithDBResult(1, 0, WithError("syntax error")))
+		},
+	},
+	{
+		name: "slow_query",
+		config: func(c *Config) {
+			c.SlowQueryThresholdMs = 500
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			query := WithDBQuery("postgresql", "SELECT id FROM orders WHERE shop_id = $1")
+			s.RequestDB("db query", query, WithDBResult(20, 10))
+			s.RequestDB("db query", query, WithDBResult(1200, 10), WithTracing("trace-1", "span-1"))
+		},
+	},
+	{
+		name: "timer",
+		config: func(c *Config) {
+			c.SlowThresholdMs = map[string]float64{"timer": 100}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			t := s.StartTimer("db-call")
+			clock.Advance(20 * time.Millisecond)
+			t.Stop(WithTracing("trace-1", "span-1"))
+
+			t = s.StartTimer("slow-call")
+			clock.Advance(150 * time.Millisecond)
+			t.Stop()
+		},
+	},
+	{
+		name: "operation_summary",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			op := s.StartOperation("import")
+			clock.Advance(time.Second)
+			op.End()
+
+			op = s.StartOperation("import")
+			op.Error()
+			op.Warning()
+			op.Retry()
+			op.Count("skipped", 2)
+			clock.Advance(time.Second)
+			op.End()
+		},
+	},
+	{
+		name: "drift",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Drift([]DriftInfo{WithDrift("deployment/orders", "replicas", 3, 2)})
+		},
+	},
+	{
+		name: "deprecation",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			for i := 0; i < 3; i++ {
+				s.Deprecated("orders-v1", "v3.0.0")
+			}
+			clock.Advance(DeprecationInterval)
+			s.Deprecated("orders-v1", "v3.0.0")
+		},
+	},
+	{
+		name: "notification",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Notification(WithNotification("email", "jane@example.com", "order_confirmed", NotificationSent))
+			s.Notification(WithNotification("sms", "+66812345678", "otp", NotificationFailed))
+		},
+	},
+	{
+		name: "authz",
+		config: func(c *Config) {
+			c.AlertOnAuthzDeny = true
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Authz(AuthzAllow, "orders.read", WithAuthzScopes([]string{"orders:read"}, []string{"orders:read", "orders:write"}), WithUser("user-1", "admin"))
+			s.Authz(AuthzDeny, "orders.delete", WithAuthzScopes([]string{"orders:delete"}, []string{"orders:read"}), WithTracing("trace-1", "span-1"))
+		},
+	},
+	{
+		name: "batch",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Batch("clean batch", WithBatchResult(4, 4))
+			s.Batch("some failures", WithBatchResult(4, 3, WithBatchItemError("a", WithError("boom"))))
+			s.Batch("mostly failed", WithBatchResult(4, 1, WithBatchItemError("a", WithError("boom")), WithBatchItemError("b", WithError("boom")), WithBatchItemError("c", WithError("timeout"))))
+		},
+	},
+	{
+		name: "usage",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			_ = s.Usage(WithUsage("shop-1", "orders", 1, "order"))
+		},
+	},
+	{
+		name: "cache_stats",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			stop := s.CacheStatsLog(time.Minute, func() CacheStats {
+				return CacheStats{Name: "products", Size: 980, Capacity: 1000, Evictions: 42, HitRate: 0.93}
+			})
+			clock.Advance(time.Minute)
+			stop()
+		},
+	},
+	{
+		name: "request_trace",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			b := s.NewRequestBuffer()
+			b.Info("step", Any("n", 1))
+			clock.Advance(time.Millisecond)
+			b.Warn("retrying")
+			b.Flush("request trace log", WithTracing("trace-1", "span-1"))
+		},
+	},
+	{
+		name: "config",
+		config: func(c *Config) {
+			c.MaxBodySize = 64 * 1048576
+			c.SlowThresholdMs = map[string]float64{"handler.grpc": 100}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {},
+	},
+	{
+		name: "payment",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			info := WithPayment("txn-1", "199.90", "THB", "approved", "omise")
+			info.Card = "4242424242424242"
+			s.Payment(PaymentAuthorized, info, WithTracing("trace-1", "span-1"))
+			info.Status = "insufficient_funds"
+			s.Payment(PaymentFailed, info)
+		},
+	},
+	{
+		name: "rollout",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Rollout(WithRollout("new-checkout", 25, 10), WithTracing("trace-1", "span-1"))
+			s.Rollout(WithRollout("new-checkout", 25, 80))
+		},
+	},
+	{
+		name: "heartbeat",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			stop := s.StartHeartbeat(time.Minute)
+			defer stop()
+			clock.Advance(time.Minute)
+		},
+	},
+	{
+		name: "audit",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			before := map[string]interface{}{"status": "pending", "address": map[string]interface{}{"city": "Bangkok"}}
+			after := map[string]interface{}{"status": "paid", "address": map[string]interface{}{"city": "Bangkok"}}
+			s.Audit(WithAudit("admin-1", "update", "order/1", before, after), WithTracing("trace-1", "span-1"))
+			s.Audit(WithAudit("admin-1", "update", "order/1", before, after).Diff())
+		},
+	},
+	{
+		name: "webhook",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			info := WithWebhook("https://hooks.example.com/orders", "order.created", 1, 503, 1200, WithError("service unavailable"))
+			info.Headers = map[string]string{"Content-Type": "application/json", "X-Hub-Signature-256": "sha256=2f7a1c"}
+			s.Webhook(info, WithTracing("trace-1", "span-1"))
+			info.Attempt, info.Status, info.Error = 2, 200, ErrorInfo{}
+			s.Webhook(info)
+		},
+	},
+	{
+		name: "lock",
+		config: func(c *Config) {
+			c.SlowThresholdMs = map[string]float64{"lock": 500}
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Lock("orders:42", true, 12, WithLockTTL(30*time.Second), WithTracing("trace-1", "span-1"))
+			s.Lock("orders:42", false, 750)
+		},
+	},
+	{
+		name: "summary",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			summary := s.NewDurationSummary()
+			for _, ms := range []int{40, 10, 30, 20} {
+				summary.Add(time.Duration(ms) * time.Millisecond)
+			}
+			summary.Log("import orders", WithTracing("trace-1", "span-1"))
+		},
+	},
+	{
+		name: "migration",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Migration(WithMigration("20240301120000", MigrationUp, 1250, MigrationSucceeded), WithTracing("trace-1", "span-1"))
+			s.Migration(WithMigration("20240302090000", MigrationUp, 80, MigrationFailed, WithError("relation \"orders\" already exists")))
+		},
+	},
+	{
+		name: "anomaly",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Anomaly(WithAnomaly("orders_per_minute", 130, 100), WithTracing("trace-1", "span-1"))
+			s.Anomaly(WithAnomaly("orders_per_minute", 20, 100))
+		},
+	},
+	{
+		name: "pool_stats",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			stop := s.PoolStatsLog(time.Minute, func() PoolStats {
+				return PoolStats{Name: "orders", Open: 10, Idle: 3, InUse: 7, WaitCount: 42, WaitDurationMs: 1250}
+			})
+			clock.Advance(time.Minute)
+			stop()
+		},
+	},
+	{
+		name: "consumer_job",
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.ConsumeJob("send email", WithConsumerJob("emails", "job-1", 1, 5), WithConsumerJobResult(JobProcessed, 120), WithTracing("trace-1", "span-1"))
+			s.ConsumeJob("send email", WithConsumerJob("emails", "job-2", 3, 5), WithConsumerJobResult(JobRequeued, 80, WithError("upstream timeout")))
+		},
+	},
+	{
+		name: "field_prefix",
+		config: func(c *Config) {
+			c.FieldPrefix = "suki_"
+			c.OmitEmpty = true
+		},
+		emit: func(s *SukiLogger, clock *slogtest.FakeClock) {
+			s.Info("prefixed", Any("order_id", 1))
+			s.RequestHTTP("prefixed", WithHTTPRequest("GET", "/orders", "127.0.0.1", nil, nil, nil, ""), WithHTTPResponse(200, 1, ""))
+		},
+	},
+}
+
+// runGoldenCase returns the output of tc with its volatile values masked.
+func runGoldenCase(tc goldenCase) string {
+	c := NewProductionConfig()
+	c.AppName = "shop"
+	c.Version = "1.2.3"
+	c.LogLevel = LevelDebug
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c.Clock = clock
+	if tc.config != nil {
+		tc.config(&c)
+	}
+	logger, buf := newTestLogger(c)
+
+	tc.emit(logger, clock)
+
+	return goldenVolatile.ReplaceAllStringFunc(buf.String(), func(s string) string {
+		if strings.HasPrefix(s, `"stack":`) {
+			return `"stack":"-"`
+		}
+		return s[:strings.Index(s, ":")] + `:"-"`
+	})
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join("testdata", "golden", tc.name+".golden")
+			got := runGoldenCase(tc)
+
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v, run go test -run TestGolden . -update to write it", err)
+			}
+			if got != string(want) {
+				t.Errorf("output differs from %s, run go test -run TestGolden . -update after a deliberate change\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+// TestGoldenCoversLogTypes fails when a log_type is written by none of the golden cases.
+func TestGoldenCoversLogTypes(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, tc := range goldenCases {
+		for _, line := range strings.Split(strings.TrimSpace(runGoldenCase(tc)), "\n") {
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if logType, ok := entry["log_type"].(string); ok {
+				covered[logType] = true
+			}
+		}
+	}
+
+	for _, logType := range logTypes {
+		if !covered[logType] {
+			t.Errorf("log_type %s has no golden case", logType)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package slog
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// GoNamed runs fn in a new goroutine, logging "goroutine started" and
+// "goroutine stopped" with its name. The stack of the GoNamed call is captured
+// so a panic of fn, logged at error level with the stack of the goroutine, also
+// tells where the goroutine was launched from. The panic is then raised again
+// and crashes the program as it would without GoNamed.
+func (s SukiLogger) GoNamed(name string, fn func()) {
+	spawn := callerStack(3)
+	go s.runNamed(name, spawn, fn, false)
+}
+
+// GoNamedRecover is GoNamed for the goroutines whose panic is recovered once
+// logged, the program keeps running without the goroutine.
+func (s SukiLogger) GoNamedRecover(name string, fn func()) {
+	spawn := callerStack(3)
+	go s.runNamed(name, spawn, fn, true)
+}
+
+func (s SukiLogger) runNamed(name string, spawn []StackFrame, fn func(), recoverPanic bool) {
+	s.Info("goroutine started", Any("goroutine", name))
+	defer s.Info("goroutine stopped", Any("goroutine", name))
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		s.Error("goroutine panicked",
+			Any("goroutine", name),
+			Any("panic", fmt.Sprint(r)),
+			Any("stack", string(debug.Stack())),
+			Any("spawn_stack", spawn),
+		)
+		if !recoverPanic {
+			// The program is about to crash, the panic log must reach the output first.
+			_ = s.zapInstance.Sync()
+			panic(r)
+		}
+	}()
+
+	fn()
+}
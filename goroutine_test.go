@@ -0,0 +1,102 @@
+package slog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func spawnWorker(logger *SukiLogger, fn func()) {
+	logger.GoNamedRecover("worker", fn)
+}
+
+// nextEntry returns the next entry of entries, failing the test when none comes.
+func nextEntry(t *testing.T, entries <-chan Entry) Entry {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		return entry
+	case <-time.After(5 * time.Second):
+		t.Fatal("no entry received")
+		return Entry{}
+	}
+}
+
+func TestGoNamedRecover(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       func()
+		messages []string
+	}{
+		{name: "Returns", fn: func() {}, messages: []string{"goroutine started", "goroutine stopped"}},
+		{name: "Panics", fn: func() { panic("boom") }, messages: []string{"goroutine started", "goroutine panicked", "goroutine stopped"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := newTestLogger(NewProductionConfig())
+			entries, unsubscribe := logger.Subscribe()
+			defer unsubscribe()
+
+			spawnWorker(logger, tt.fn)
+
+			for i, message := range tt.messages {
+				entry := nextEntry(t, entries)
+				fields := entry.Data[logger.config.AppName].(map[string]interface{})
+				if entry.Message != message || fields["goroutine"] != "worker" {
+					t.Errorf("entry %d = %q %v, want %q of worker", i, entry.Message, fields, message)
+				}
+			}
+		})
+	}
+}
+
+func TestGoNamedPanicRaisedAgain(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	// runNamed is called directly, the panic raised again by GoNamed would crash the test binary.
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("recovered %v, want the panic raised again", r)
+			}
+		}()
+		logger.runNamed("worker", nil, func() { panic("boom") }, false)
+	}()
+
+	for _, message := range []string{"goroutine started", "goroutine panicked", "goroutine stopped"} {
+		if entry := nextEntry(t, entries); entry.Message != message {
+			t.Errorf("message = %q, want %q", entry.Message, message)
+		}
+	}
+}
+
+func TestGoNamedPanicSpawnStack(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	spawnWorker(logger, func() { panic("boom") })
+
+	entry := nextEntry(t, entries)
+	for entry.Message != "goroutine panicked" {
+		entry = nextEntry(t, entries)
+	}
+	if entry.Level != "error" {
+		t.Errorf("level = %v, want error", entry.Level)
+	}
+	fields := entry.Data[logger.config.AppName].(map[string]interface{})
+	if fields["panic"] != "boom" {
+		t.Errorf("panic = %v, want boom", fields["panic"])
+	}
+	if !strings.Contains(fields["stack"].(string), "goroutine_test.go") {
+		t.Errorf("stack does not hold the panicking func: %v", fields["stack"])
+	}
+	spawn := fields["spawn_stack"].([]interface{})
+	first := spawn[0].(map[string]interface{})
+	if !strings.HasSuffix(first["func"].(string), ".spawnWorker") || !strings.HasSuffix(first["file"].(string), "goroutine_test.go") {
+		t.Errorf("spawn_stack starts at %v, want spawnWorker", first)
+	}
+}
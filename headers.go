@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// capHeaders keeps MaxHeaders of headers, the PriorityHeaders first in their
+// order then the others alphabetically, and returns the number dropped.
+func (c Config) capHeaders(headers map[string]string) (map[string]string, int) {
+	if c.MaxHeaders <= 0 || len(headers) <= c.MaxHeaders {
+		return headers, 0
+	}
+
+	rank := make(map[string]int, len(c.PriorityHeaders))
+	for i, name := range c.PriorityHeaders {
+		if _, ok := rank[http.CanonicalHeaderKey(name)]; !ok {
+			rank[http.CanonicalHeaderKey(name)] = i
+		}
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iPriority := rank[http.CanonicalHeaderKey(keys[i])]
+		rj, jPriority := rank[http.CanonicalHeaderKey(keys[j])]
+		if iPriority != jPriority {
+			return iPriority
+		}
+		if iPriority && ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+
+	kept := make(map[string]string, c.MaxHeaders)
+	for _, k := range keys[:c.MaxHeaders] {
+		kept[k] = headers[k]
+	}
+	return kept, len(headers) - c.MaxHeaders
+}
+
+// lookupHeader returns the value of the header name in headers, whatever the case of its key.
+func lookupHeader(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package slog
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMaxHeaders(t *testing.T) {
+	headers := map[string]string{
+		"Accept":          "*/*",
+		"Accept-Encoding": "gzip",
+		"Content-Type":    "application/json",
+		"User-Agent":      "curl/8.0",
+		"X-Request-Id":    "request-1",
+		"X-Forwarded-For": "10.0.0.1",
+	}
+
+	tests := []struct {
+		name        string
+		maxHeaders  int
+		priority    []string
+		want        []string
+		wantDropped interface{}
+	}{
+		{
+			name:        "Unlimited",
+			want:        []string{"Accept", "Accept-Encoding", "Content-Type", "User-Agent", "X-Forwarded-For", "X-Request-Id"},
+			wantDropped: nil,
+		},
+		{
+			name:        "Under the cap",
+			maxHeaders:  6,
+			want:        []string{"Accept", "Accept-Encoding", "Content-Type", "User-Agent", "X-Forwarded-For", "X-Request-Id"},
+			wantDropped: nil,
+		},
+		{
+			name:        "Alphabetical without priority",
+			maxHeaders:  3,
+			want:        []string{"Accept", "Accept-Encoding", "Content-Type"},
+			wantDropped: float64(3),
+		},
+		{
+			name:        "Priority first",
+			maxHeaders:  3,
+			priority:    []string{"user-agent", "content-type"},
+			want:        []string{"Accept", "Content-Type", "User-Agent"},
+			wantDropped: float64(3),
+		},
+		{
+			name:        "More priority headers than the cap",
+			maxHeaders:  2,
+			priority:    []string{"x-request-id", "user-agent", "content-type", "x-absent"},
+			want:        []string{"User-Agent", "X-Request-Id"},
+			wantDropped: float64(4),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MaxHeaders = tt.maxHeaders
+			c.PriorityHeaders = tt.priority
+			logger, buf := newTestLogger(c)
+
+			// The same headers logged twice keep the same ones.
+			for i := 0; i < 2; i++ {
+				logger.RequestHTTP("http request", WithHTTPRequest("GET", "/orders", "", headers, nil, nil, ""), WithHTTPResponse(200, 1, ""))
+			}
+
+			for _, line := range decodeLines(t, buf) {
+				request := line["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+				var got []string
+				for k := range request["headers"].(map[string]interface{}) {
+					got = append(got, k)
+				}
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("headers = %v, want %v", got, tt.want)
+				}
+				if request["headers_dropped"] != tt.wantDropped {
+					t.Errorf("headers_dropped = %v, want %v", request["headers_dropped"], tt.wantDropped)
+				}
+			}
+			if len(headers) != 6 {
+				t.Errorf("headers of the caller modified: %v", headers)
+			}
+		})
+	}
+}
+
+func TestPromoteContentHeaders(t *testing.T) {
+	tests := []struct {
+		name            string
+		promote         bool
+		headers         map[string]string
+		wantContentType interface{}
+		wantAccept      interface{}
+	}{
+		{
+			name:            "Promoted",
+			promote:         true,
+			headers:         map[string]string{"Content-Type": "application/json", "Accept": "application/xml", "User-Agent": "curl/8.0"},
+			wantContentType: "application/json",
+			wantAccept:      "application/xml",
+		},
+		{
+			name:            "Lower case keys",
+			promote:         true,
+			headers:         map[string]string{"content-type": "text/plain", "accept": "*/*"},
+			wantContentType: "text/plain",
+			wantAccept:      "*/*",
+		},
+		{
+			name:    "Absent",
+			promote: true,
+			headers: map[string]string{"User-Agent": "curl/8.0"},
+		},
+		{
+			name:    "Disabled",
+			headers: map[string]string{"Content-Type": "application/json", "Accept": "application/xml"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.PromoteContentHeaders = tt.promote
+			logger, buf := newTestLogger(c)
+
+			logger.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "127.0.0.1", tt.headers, nil, nil, ""), WithHTTPResponse(200, 1, ""))
+
+			request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+			if request["content_type"] != tt.wantContentType || request["accept"] != tt.wantAccept {
+				t.Errorf("content_type, accept = %v, %v, want %v, %v", request["content_type"], request["accept"], tt.wantContentType, tt.wantAccept)
+			}
+			if got := request["headers"].(map[string]interface{}); len(got) != len(tt.headers) {
+				t.Errorf("headers = %v, want %v", got, tt.headers)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// HeartbeatInfo is the data.heartbeat of a heartbeat log, app_name and version
+// are already in the envelope.
+type HeartbeatInfo struct {
+	// UptimeSeconds is the time since the logger was first configured.
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	Goroutines    int     `json:"goroutines"`
+	// DurationAnomalies counts the durations flagged by the logger so far.
+	DurationAnomalies DurationAnomalies `json:"duration_anomalies"`
+}
+
+// StartHeartbeat writes a heartbeat log every interval until the returned stop
+// func or Close is called, confirming a low traffic service is alive, e.g.
+//
+//	stop := slog.L().StartHeartbeat(time.Minute)
+//	defer stop()
+func (s *SukiLogger) StartHeartbeat(interval time.Duration) (stop func()) {
+	e := s.startPeriodic("heartbeat", interval, func() { s.writeHeartbeat() })
+	s.heartbeats.add(e)
+	return func() {
+		e.stop()
+		s.heartbeats.remove(e)
+	}
+}
+
+func (s SukiLogger) writeHeartbeat() {
+	data := map[string]interface{}{
+		"heartbeat": HeartbeatInfo{
+			UptimeSeconds:     s.clock().Now().Sub(s.started).Seconds(),
+			Goroutines:        runtime.NumGoroutine(),
+			DurationAnomalies: s.DurationAnomalies(),
+		},
+	}
+
+	if ce := s.sampledLogger("heartbeat", LevelNone, zapcore.InfoLevel, "heartbeat", data).Check(zapcore.InfoLevel, "heartbeat"); ce != nil {
+		ce.Write(s.envelope(LevelNone, data)...)
+	}
+}
+
+// heartbeats are the running heartbeats of a logger, stopped by Close.
+type heartbeats struct {
+	mu  sync.Mutex
+	set map[*periodicEmitter]struct{}
+}
+
+func (h *heartbeats) add(e *periodicEmitter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.set == nil {
+		h.set = make(map[*periodicEmitter]struct{})
+	}
+	h.set[e] = struct{}{}
+}
+
+func (h *heartbeats) remove(e *periodicEmitter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.set, e)
+}
+
+func (h *heartbeats) stopAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for e := range h.set {
+		e.stop()
+	}
+	h.set = nil
+}
@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"testing"
+	"time"
+)
+
+func TestStartHeartbeat(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.AppName = "shop"
+	c.Version = "1.2.3"
+	logger, buf := newTestLogger(c)
+
+	stop := logger.StartHeartbeat(30 * time.Second)
+
+	clock.Advance(29 * time.Second)
+	if lines := decodeLines(t, buf); len(lines) != 0 {
+		t.Fatalf("%d logs before the first interval, want 0", len(lines))
+	}
+
+	clock.Advance(time.Minute + time.Second)
+	stop()
+	stop()
+	clock.Advance(time.Hour)
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 3 {
+		t.Fatalf("%d logs, want 3", len(lines))
+	}
+	for i, line := range lines {
+		if line["log_type"] != "heartbeat" || line["message"] != "heartbeat" || line["app_name"] != "shop" || line["version"] != "1.2.3" {
+			t.Errorf("log_type, message, app_name, version = %v, %v, %v, %v", line["log_type"], line["message"], line["app_name"], line["version"])
+		}
+
+		heartbeat := line["data"].(map[string]interface{})["heartbeat"].(map[string]interface{})
+		if want := float64(30 * (i + 1)); heartbeat["uptime_seconds"] != want {
+			t.Errorf("uptime_seconds = %v, want %v", heartbeat["uptime_seconds"], want)
+		}
+		if goroutines, _ := heartbeat["goroutines"].(float64); goroutines < 1 {
+			t.Errorf("goroutines = %v, want at least 1", heartbeat["goroutines"])
+		}
+	}
+	if pending := clock.Pending(); len(pending) != 0 {
+		t.Errorf("%d timers left after stop, want 0", len(pending))
+	}
+}
+
+func TestCloseStopsHeartbeats(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	stop := logger.StartHeartbeat(time.Minute)
+	logger.StartHeartbeat(time.Hour)
+
+	clock.Advance(time.Minute)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	stop()
+	clock.Advance(24 * time.Hour)
+
+	if lines := decodeLines(t, buf); len(lines) != 1 {
+		t.Errorf("%d logs, want 1", len(lines))
+	}
+	if pending := clock.Pending(); len(pending) != 0 {
+		t.Errorf("%d timers left after Close, want 0", len(pending))
+	}
+	if n := len(logger.heartbeats.set); n != 0 {
+		t.Errorf("%d heartbeats left after Close, want 0", n)
+	}
+}
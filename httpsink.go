@@ -0,0 +1,308 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultHTTPSinkBatchSize is the number of logs posted at once when HTTPSink.BatchSize is 0.
+	DefaultHTTPSinkBatchSize = 100
+	// DefaultHTTPSinkFlushInterval is how long a batch waits for more logs when HTTPSink.FlushInterval is 0.
+	DefaultHTTPSinkFlushInterval = 5 * time.Second
+	// DefaultHTTPSinkQueueSize is the number of batches waiting to be posted when HTTPSink.QueueSize is 0.
+	DefaultHTTPSinkQueueSize = 10
+	// DefaultHTTPSinkBackoff is the delay before the first retry when HTTPSink.Backoff is 0.
+	DefaultHTTPSinkBackoff = 500 * time.Millisecond
+	// DefaultHTTPSinkTimeout bounds a post when HTTPSink.Client is nil.
+	DefaultHTTPSinkTimeout = 10 * time.Second
+)
+
+// defaultHTTPSinkClient sends the posts of the sinks without a Client, a hung
+// endpoint fails a post after DefaultHTTPSinkTimeout instead of stalling the sink.
+var defaultHTTPSinkClient = &http.Client{Timeout: DefaultHTTPSinkTimeout}
+
+// HTTPSink posts the logs written to it as newline-delimited JSON to an ingest
+// endpoint, for environments without a file or stdout collector. It is used as
+// the Output of the logger, e.g.
+//
+//	config.Output = &slog.HTTPSink{URL: "https://logs.example.com/ingest", BatchSize: 500}
+//
+// The logs are batched and a batch is queued for posting once it holds BatchSize
+// logs, FlushInterval after its first log, or on Sync. The batches are posted by
+// a background goroutine so a slow endpoint never blocks the logger: the batches
+// which do not fit the queue, and those still failing after MaxRetries retries of
+// a network error, a 429 or a 5xx status, are dropped and counted in Stats.
+type HTTPSink struct {
+	// URL receives a POST with a batch of logs, one JSON log per line.
+	URL string
+	// Headers are set on every post, e.g. an Authorization header.
+	Headers map[string]string
+	// BatchSize is the number of logs posted at once, DefaultHTTPSinkBatchSize when 0.
+	BatchSize int
+	// FlushInterval is how long a batch waits for more logs before being posted,
+	// DefaultHTTPSinkFlushInterval when 0 (-1 = Only when full or synced).
+	FlushInterval time.Duration
+	// QueueSize bounds the batches waiting to be posted, DefaultHTTPSinkQueueSize when 0.
+	QueueSize int
+	// MaxRetries is the number of retries of a failed post (0 = No retry).
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for every other retry,
+	// DefaultHTTPSinkBackoff when 0.
+	Backoff time.Duration
+	// Client sends the requests, a client timing out after DefaultHTTPSinkTimeout when nil.
+	Client *http.Client
+	// Clock times the flush interval and the backoff, the real clock when nil.
+	Clock clock.Clock
+
+	once     sync.Once
+	queue    chan httpSinkBatch
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	count int
+	timer clock.Timer
+	// timerGeneration identifies the current timer, a timer which fired after
+	// being replaced must not flush the logs of the next batch.
+	timerGeneration uint64
+	closed          bool
+
+	sent, dropped int64
+}
+
+// HTTPSinkStats are the counters of an HTTPSink, in logs.
+type HTTPSinkStats struct {
+	// Sent are the logs posted.
+	Sent int64
+	// Dropped are the logs of the batches which did not fit the queue or whose post failed.
+	Dropped int64
+}
+
+// httpSinkBatch is a batch of logs to post, or a flush marker when flushed is set.
+type httpSinkBatch struct {
+	body    []byte
+	logs    int
+	flushed chan struct{}
+}
+
+func (h *HTTPSink) init() {
+	h.once.Do(func() {
+		size := h.QueueSize
+		if size <= 0 {
+			size = DefaultHTTPSinkQueueSize
+		}
+		h.queue = make(chan httpSinkBatch, size)
+		h.stop = make(chan struct{})
+		h.done = make(chan struct{})
+		go h.deliver()
+	})
+}
+
+// Write buffers a log, queuing the batch once it holds BatchSize logs.
+func (h *HTTPSink) Write(p []byte) (int, error) {
+	h.init()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		atomic.AddInt64(&h.dropped, 1)
+		return len(p), nil
+	}
+
+	h.buf.Write(p)
+	h.count++
+	if h.count == 1 && h.flushInterval() > 0 {
+		h.timerGeneration++
+		generation := h.timerGeneration
+		h.timer = h.clock().AfterFunc(h.flushInterval(), func() { h.flushBatch(generation) })
+	}
+
+	batchSize := h.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultHTTPSinkBatchSize
+	}
+	if h.count >= batchSize {
+		h.enqueue()
+	}
+	return len(p), nil
+}
+
+// Sync queues the buffered logs and returns once the batches queued before it are posted or dropped.
+func (h *HTTPSink) Sync() error {
+	h.init()
+
+	h.mu.Lock()
+	h.enqueue()
+	h.mu.Unlock()
+
+	flushed := make(chan struct{})
+	select {
+	case h.queue <- httpSinkBatch{flushed: flushed}:
+	case <-h.done:
+		return nil
+	}
+	select {
+	case <-flushed:
+	case <-h.done:
+	}
+	return nil
+}
+
+// Close posts the buffered logs and stops the sink, the logs written afterwards are dropped.
+func (h *HTTPSink) Close() error {
+	h.init()
+
+	h.mu.Lock()
+	h.enqueue()
+	h.closed = true
+	h.mu.Unlock()
+
+	h.stopOnce.Do(func() { close(h.stop) })
+	<-h.done
+	return nil
+}
+
+func (h *HTTPSink) Stats() HTTPSinkStats {
+	return HTTPSinkStats{
+		Sent:    atomic.LoadInt64(&h.sent),
+		Dropped: atomic.LoadInt64(&h.dropped),
+	}
+}
+
+// flushBatch queues the buffered logs once the timer of generation fires, unless
+// the batch was queued meanwhile and another timer armed for the next one.
+func (h *HTTPSink) flushBatch(generation uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.timer == nil || generation != h.timerGeneration {
+		return
+	}
+	h.timer = nil
+	h.enqueue()
+}
+
+// enqueue queues the buffered logs without waiting, h.mu must be held.
+func (h *HTTPSink) enqueue() {
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if h.count == 0 {
+		return
+	}
+
+	batch := httpSinkBatch{body: append([]byte(nil), h.buf.Bytes()...), logs: h.count}
+	h.buf.Reset()
+	h.count = 0
+
+	select {
+	case h.queue <- batch:
+	default:
+		atomic.AddInt64(&h.dropped, int64(batch.logs))
+	}
+}
+
+func (h *HTTPSink) deliver() {
+	defer close(h.done)
+
+	for {
+		select {
+		case batch := <-h.queue:
+			h.post(batch)
+		case <-h.stop:
+			for {
+				select {
+				case batch := <-h.queue:
+					h.post(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// post posts batch, retrying the retryable failures up to MaxRetries times.
+func (h *HTTPSink) post(batch httpSinkBatch) {
+	if batch.flushed != nil {
+		close(batch.flushed)
+		return
+	}
+
+	backoff := h.Backoff
+	if backoff <= 0 {
+		backoff = DefaultHTTPSinkBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		retryable, err := h.send(batch.body)
+		if err == nil {
+			atomic.AddInt64(&h.sent, int64(batch.logs))
+			return
+		}
+		if !retryable || attempt >= h.MaxRetries {
+			atomic.AddInt64(&h.dropped, int64(batch.logs))
+			return
+		}
+		h.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send posts body once, reporting whether a failure is worth a retry.
+func (h *HTTPSink) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := h.Client
+	if client == nil {
+		client = defaultHTTPSinkClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("http sink: %s responded %s", redactUserinfo(h.URL), resp.Status)
+}
+
+func (h *HTTPSink) flushInterval() time.Duration {
+	if h.FlushInterval == 0 {
+		return DefaultHTTPSinkFlushInterval
+	}
+	return h.FlushInterval
+}
+
+func (h *HTTPSink) clock() clock.Clock {
+	if h.Clock == nil {
+		return clock.Real{}
+	}
+	return h.Clock
+}
+
+func (h *HTTPSink) sleep(d time.Duration) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	h.clock().AfterFunc(d, wg.Done)
+	wg.Wait()
+}
@@ -0,0 +1,225 @@
+package slog
+
+import (
+	"encoding/json"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ingestServer records the bodies posted to it, answering with the statuses in
+// order then 200. Every post is signalled on posts, and waits for release when set.
+type ingestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   []string
+	headers  []http.Header
+	statuses []int
+	posts    chan struct{}
+	release  chan struct{}
+}
+
+func newIngestServer(statuses ...int) *ingestServer {
+	s := &ingestServer{statuses: statuses, posts: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if s.release != nil {
+			<-s.release
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, string(body))
+		s.headers = append(s.headers, r.Header)
+		if len(s.statuses) > 0 {
+			w.WriteHeader(s.statuses[0])
+			s.statuses = s.statuses[1:]
+		}
+		s.posts <- struct{}{}
+	}))
+	return s
+}
+
+func (s *ingestServer) posted() ([]string, []http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...), append([]http.Header(nil), s.headers...)
+}
+
+func (s *ingestServer) waitPost(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.posts:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no post received")
+	}
+}
+
+func TestHTTPSinkBatches(t *testing.T) {
+	server := newIngestServer()
+	defer server.Close()
+
+	c := NewProductionConfig()
+	sink := &HTTPSink{URL: server.URL, BatchSize: 2, Headers: map[string]string{"Authorization": "Bearer token"}}
+	c.Output = sink
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, message := range []string{"first", "second", "third"} {
+		logger.Warn(message)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bodies, headers := server.posted()
+	want := [][]string{{"first", "second"}, {"third"}}
+	if len(bodies) != len(want) {
+		t.Fatalf("got %d posts, want %d", len(bodies), len(want))
+	}
+	for i, body := range bodies {
+		lines := strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+		if len(lines) != len(want[i]) {
+			t.Fatalf("post %d holds %d logs, want %v", i, len(lines), want[i])
+		}
+		for j, line := range lines {
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("post %d line %d is not JSON: %v", i, j, err)
+			}
+			if entry["message"] != want[i][j] {
+				t.Errorf("post %d line %d message = %v, want %v", i, j, entry["message"], want[i][j])
+			}
+		}
+		if headers[i].Get("Authorization") != "Bearer token" || headers[i].Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("post %d headers = %v", i, headers[i])
+		}
+	}
+	if stats := sink.Stats(); stats != (HTTPSinkStats{Sent: 3}) {
+		t.Errorf("Stats() = %+v, want 3 sent", stats)
+	}
+}
+
+func TestHTTPSinkFlushInterval(t *testing.T) {
+	server := newIngestServer()
+	defer server.Close()
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sink := &HTTPSink{URL: server.URL, FlushInterval: time.Second, Clock: clock}
+	defer sink.Close()
+
+	sink.Write([]byte("{\"message\":\"hello\"}\n"))
+	clock.Advance(time.Second)
+	server.waitPost(t)
+
+	if bodies, _ := server.posted(); len(bodies) != 1 || bodies[0] != "{\"message\":\"hello\"}\n" {
+		t.Errorf("posted %q, want the partial batch once the interval elapsed", bodies)
+	}
+}
+
+func TestHTTPSinkStaleFlushTimer(t *testing.T) {
+	server := newIngestServer()
+	defer server.Close()
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sink := &HTTPSink{URL: server.URL, BatchSize: 2, FlushInterval: time.Second, Clock: clock}
+	defer sink.Close()
+
+	sink.Write([]byte("{\"message\":\"a\"}\n"))
+	sink.Write([]byte("{\"message\":\"b\"}\n"))
+	server.waitPost(t)
+	sink.Write([]byte("{\"message\":\"c\"}\n"))
+
+	// The timer of the first batch fires after the batch was queued by size.
+	sink.flushBatch(1)
+
+	sink.mu.Lock()
+	count, armed := sink.count, sink.timer != nil
+	sink.mu.Unlock()
+	if count != 1 || !armed {
+		t.Fatalf("count, timer armed = %d, %v after a stale timer, want 1, true", count, armed)
+	}
+
+	clock.Advance(time.Second)
+	server.waitPost(t)
+	if bodies, _ := server.posted(); len(bodies) != 2 || bodies[1] != "{\"message\":\"c\"}\n" {
+		t.Errorf("posted %q, want the second batch once its own interval elapsed", bodies)
+	}
+}
+
+func TestHTTPSinkDoesNotBlockOnHungEndpoint(t *testing.T) {
+	server := newIngestServer()
+	server.release = make(chan struct{})
+	defer server.Close()
+	sink := &HTTPSink{URL: server.URL, BatchSize: 1, QueueSize: 1, FlushInterval: -1}
+
+	// The first batch is being posted, the second is queued and the third does not fit.
+	for i := 0; i < 3; i++ {
+		if _, err := sink.Write([]byte("{}\n")); err != nil {
+			t.Fatalf("Write() error = %v, want the failures kept off the logger", err)
+		}
+	}
+	close(server.release)
+	sink.Close()
+
+	stats := sink.Stats()
+	if stats.Sent+stats.Dropped != 3 || stats.Sent < 1 {
+		t.Errorf("Stats() = %+v, want the 3 logs sent or dropped", stats)
+	}
+}
+
+func TestHTTPSinkRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		retries     int
+		wantPosts   int
+		wantDropped int64
+	}{
+		{name: "Retried until accepted", statuses: []int{503, 429}, retries: 2, wantPosts: 3},
+		{name: "Retries exhausted", statuses: []int{500, 500, 500}, retries: 1, wantPosts: 2, wantDropped: 1},
+		{name: "Client error not retried", statuses: []int{400}, retries: 2, wantPosts: 1, wantDropped: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newIngestServer(tt.statuses...)
+			defer server.Close()
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			sink := &HTTPSink{URL: server.URL, MaxRetries: tt.retries, Backoff: time.Second, FlushInterval: -1, Clock: clock}
+
+			if _, err := sink.Write([]byte("{\"message\":\"hello\"}\n")); err != nil {
+				t.Fatal(err)
+			}
+
+			done := make(chan error)
+			go func() { done <- sink.Sync() }()
+			backoff := time.Second
+			for i := 1; i < tt.wantPosts; i++ {
+				clock.BlockUntil(1)
+				clock.Advance(backoff)
+				backoff *= 2
+			}
+			if err := <-done; err != nil {
+				t.Errorf("Sync() error = %v, want the failures counted as drops", err)
+			}
+
+			bodies, _ := server.posted()
+			if len(bodies) != tt.wantPosts {
+				t.Errorf("got %d posts, want %d", len(bodies), tt.wantPosts)
+			}
+			for _, body := range bodies {
+				if body != "{\"message\":\"hello\"}\n" {
+					t.Errorf("posted %q, want the same batch", body)
+				}
+			}
+			if stats := sink.Stats(); stats.Dropped != tt.wantDropped {
+				t.Errorf("Stats().Dropped = %d, want %d", stats.Dropped, tt.wantDropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package slog
+
+// IdempotencyReplay is the data.idempotency of an HTTP log, marking a request
+// answered with the cached response of a prior request with the same idempotency key.
+type IdempotencyReplay struct {
+	Replayed bool   `json:"replayed"`
+	Key      string `json:"key,omitempty"`
+	// OriginalRequestID is the request ID of the request whose response was replayed.
+	OriginalRequestID string `json:"original_request_id"`
+}
+
+func WithIdempotencyReplay(key string, originalRequestID string) IdempotencyReplay {
+	return IdempotencyReplay{
+		Replayed:          true,
+		Key:               key,
+		OriginalRequestID: originalRequestID,
+	}
+}
@@ -0,0 +1,40 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdempotencyReplay(t *testing.T) {
+	tests := []struct {
+		name string
+		args []interface{}
+		want interface{}
+	}{
+		{
+			name: "Replayed request",
+			args: []interface{}{WithIdempotencyReplay("key-123", "req-1")},
+			want: map[string]interface{}{
+				"replayed":            true,
+				"key":                 "key-123",
+				"original_request_id": "req-1",
+			},
+		},
+		{
+			name: "Fresh request",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.RequestHTTP("http request", WithHTTPRequest("POST", "/payments", "", nil, nil, nil, ""), WithHTTPResponse(201, 1, ""), tt.args...)
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			if got := data["idempotency"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data.idempotency = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package slog
+
+// idList is the value of an IDs field, capped to its first ids.
+type idList struct {
+	ids  []string
+	more int
+}
+
+// IDs returns a field logging ids as a JSON array, e.g. the records touched by a
+// bulk update. When limit is given only the first limit ids are logged and the
+// count of the others is logged as key_more.
+func IDs(key string, ids []string, limit ...int) LogField {
+	list := idList{ids: ids}
+	if len(limit) > 0 && limit[0] >= 0 && len(ids) > limit[0] {
+		list.ids = ids[:limit[0]]
+		list.more = len(ids) - limit[0]
+	}
+	if list.ids == nil {
+		list.ids = []string{}
+	}
+	return Any(key, list)
+}
+
+func (l idList) MarshalJSON() ([]byte, error) {
+	return marshalNoEscape(l.ids)
+}
+
+// addAppField adds field to the application data of a log.
+func addAppField(appData map[string]interface{}, field LogField) {
+	appData[field.Key] = field.Value
+	if list, ok := field.Value.(idList); ok && list.more > 0 {
+		appData[field.Key+"_more"] = list.more
+	}
+}
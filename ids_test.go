@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		field LogField
+		want  map[string]interface{}
+	}{
+		{
+			name:  "Array",
+			field: IDs("order_ids", []string{"o-1", "o-2", "o-3"}),
+			want:  map[string]interface{}{"order_ids": []interface{}{"o-1", "o-2", "o-3"}},
+		},
+		{
+			name:  "Empty",
+			field: IDs("order_ids", nil),
+			want:  map[string]interface{}{"order_ids": []interface{}{}},
+		},
+		{
+			name:  "Within limit",
+			field: IDs("order_ids", []string{"o-1", "o-2"}, 2),
+			want:  map[string]interface{}{"order_ids": []interface{}{"o-1", "o-2"}},
+		},
+		{
+			name:  "Capped",
+			field: IDs("order_ids", []string{"o-1", "o-2", "o-3", "o-4", "o-5"}, 2),
+			want: map[string]interface{}{
+				"order_ids":      []interface{}{"o-1", "o-2"},
+				"order_ids_more": float64(3),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.AppName = "shop"
+			logger, buf := newTestLogger(c)
+
+			logger.Info("bulk update", tt.field)
+
+			if got := decodeLines(t, buf)[0]["data"].(map[string]interface{})["shop"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data.shop = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"strconv"
+)
+
+var bufferPool = buffer.NewPool()
+
+// lineSizeEncoder appends the size of every encoded line to the line under key.
+type lineSizeEncoder struct {
+	zapcore.Encoder
+	key string
+}
+
+func (e lineSizeEncoder) Clone() zapcore.Encoder {
+	return lineSizeEncoder{Encoder: e.Encoder.Clone(), key: e.key}
+}
+
+func (e lineSizeEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	line := buf.Bytes()
+	end := len(line) - 1
+	for end >= 0 && line[end] != '}' {
+		end--
+	}
+	if end < 0 {
+		return buf, nil
+	}
+
+	// The size counts the field itself, whose digits may grow the line once more.
+	prefix := `,"` + e.key + `":`
+	size := len(line) + len(prefix)
+	for len(line)+len(prefix)+len(strconv.Itoa(size)) != size {
+		size = len(line) + len(prefix) + len(strconv.Itoa(size))
+	}
+
+	out := bufferPool.Get()
+	out.Write(line[:end])
+	out.AppendString(prefix)
+	out.AppendInt(int64(size))
+	out.Write(line[end:])
+	buf.Free()
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIncludeLineSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		message string
+	}{
+		{
+			name:    "Short line",
+			message: "a",
+		},
+		{
+			name:    "Long line",
+			message: strings.Repeat("a", 760),
+		},
+		{
+			name:    "With field prefix",
+			prefix:  "suki_",
+			message: "prefixed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.IncludeLineSize = true
+			c.FieldPrefix = tt.prefix
+			logger, buf := newTestLogger(c)
+
+			logger.Info(tt.message, Any("order_id", 1))
+
+			line := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+			var decoded map[string]interface{}
+			if err := json.Unmarshal(line, &decoded); err != nil {
+				t.Fatalf("invalid JSON %s: %v", line, err)
+			}
+			if got := decoded[tt.prefix+"log_size_bytes"]; got != float64(len(line)+1) {
+				t.Errorf("log_size_bytes = %v, want %v", got, len(line)+1)
+			}
+		})
+	}
+}
+
+func TestWithoutLineSize(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.Info("no size")
+
+	if _, ok := decodeLines(t, buf)[0]["log_size_bytes"]; ok {
+		t.Error("log_size_bytes should be omitted")
+	}
+}
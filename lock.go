@@ -0,0 +1,51 @@
+package slog
+
+import "time"
+
+// LockInfo is the data.lock of a lock log, an attempt to acquire a distributed
+// lock e.g. in Redis or etcd. WaitMs and TTLMs are in milliseconds.
+type LockInfo struct {
+	Key      string  `json:"key"`
+	Acquired bool    `json:"acquired"`
+	WaitMs   float64 `json:"wait_ms"`
+	TTLMs    float64 `json:"ttl_ms,omitempty"`
+	// WaitAnomaly is the duration_anomaly of WaitMs.
+	WaitAnomaly string `json:"wait_anomaly,omitempty"`
+}
+
+// LockTTL is the time to live of an acquired lock, passed as an arg of Lock.
+type LockTTL time.Duration
+
+func WithLockTTL(ttl time.Duration) LockTTL {
+	return LockTTL(ttl)
+}
+
+// Lock writes a lock log of an attempt to acquire the lock key after waiting
+// waitMs, it is written at warn level with slow: true above the
+// SlowThresholdMs of "lock", e.g.
+//
+//	slog.L().Lock("orders:42", true, 120, slog.WithLockTTL(30*time.Second))
+func (s SukiLogger) Lock(key string, acquired bool, waitMs float64, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	anomaly := s.checkDuration(&waitMs)
+	info := LockInfo{Key: key, Acquired: acquired, WaitMs: waitMs, WaitAnomaly: anomaly}
+	for i := range args {
+		if ttl, ok := args[i].(LockTTL); ok {
+			info.TTLMs = toMillis(time.Duration(ttl))
+		}
+	}
+	data["lock"] = info
+	level := s.durationLevel("lock", waitMs, data)
+
+	message := "lock acquired"
+	if !acquired {
+		message = "lock contended"
+	}
+
+	if ce := s.sampledLogger("lock", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,78 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		acquired    bool
+		waitMs      float64
+		args        []interface{}
+		wantLevel   string
+		wantMessage string
+		wantSlow    bool
+		want        map[string]interface{}
+	}{
+		{
+			name:        "Acquired",
+			acquired:    true,
+			waitMs:      12,
+			args:        []interface{}{WithLockTTL(30 * time.Second)},
+			wantLevel:   "info",
+			wantMessage: "lock acquired",
+			want:        map[string]interface{}{"key": "orders:42", "acquired": true, "wait_ms": float64(12), "ttl_ms": float64(30000)},
+		},
+		{
+			name:        "Contended",
+			acquired:    false,
+			waitMs:      80,
+			wantLevel:   "info",
+			wantMessage: "lock contended",
+			want:        map[string]interface{}{"key": "orders:42", "acquired": false, "wait_ms": float64(80)},
+		},
+		{
+			name:        "Acquired after a slow wait",
+			acquired:    true,
+			waitMs:      750,
+			args:        []interface{}{WithLockTTL(time.Second)},
+			wantLevel:   "warn",
+			wantMessage: "lock acquired",
+			wantSlow:    true,
+			want:        map[string]interface{}{"key": "orders:42", "acquired": true, "wait_ms": float64(750), "ttl_ms": float64(1000)},
+		},
+		{
+			name:        "Contended after a slow wait",
+			acquired:    false,
+			waitMs:      2000,
+			wantLevel:   "warn",
+			wantMessage: "lock contended",
+			wantSlow:    true,
+			want:        map[string]interface{}{"key": "orders:42", "acquired": false, "wait_ms": float64(2000)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.SlowThresholdMs = map[string]float64{"lock": 500}
+			logger, buf := newTestLogger(c)
+
+			logger.Lock("orders:42", tt.acquired, tt.waitMs, tt.args...)
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "lock" || line["level"] != tt.wantLevel || line["message"] != tt.wantMessage {
+				t.Errorf("log_type, level, message = %v, %v, %v, want lock, %s, %s", line["log_type"], line["level"], line["message"], tt.wantLevel, tt.wantMessage)
+			}
+			data := line["data"].(map[string]interface{})
+			if !reflect.DeepEqual(data["lock"], tt.want) {
+				t.Errorf("lock = %v, want %v", data["lock"], tt.want)
+			}
+			if slow := data["slow"] == true; slow != tt.wantSlow {
+				t.Errorf("slow = %v, want %v", data["slow"], tt.wantSlow)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package slog
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// messagePrefixEncoder prepends prefix to the message of every line.
+type messagePrefixEncoder struct {
+	zapcore.Encoder
+	prefix string
+}
+
+func (e messagePrefixEncoder) Clone() zapcore.Encoder {
+	return messagePrefixEncoder{Encoder: e.Encoder.Clone(), prefix: e.prefix}
+}
+
+func (e messagePrefixEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = e.prefix + ent.Message
+	return e.Encoder.EncodeEntry(ent, fields)
+}
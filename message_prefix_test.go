@@ -0,0 +1,45 @@
+package slog
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMessagePrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		want   []string
+	}{
+		{
+			name: "No prefix",
+			want: []string{"order created", "http"},
+		},
+		{
+			name:   "Component prefix",
+			prefix: "[checkout] ",
+			want:   []string{"[checkout] order created", "[checkout] http"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MessagePrefix = tt.prefix
+			logger, buf := newTestLogger(c)
+			captured := &bytes.Buffer{}
+			defer logger.StartCapture(CaptureFilter{}, captured)()
+
+			logger.Info("order created")
+			logger.RequestHTTP("http", WithHTTPRequest("GET", "/orders", "127.0.0.1", nil, nil, nil, ""), WithHTTPResponse(200, 1, ""))
+
+			for i, line := range decodeLines(t, buf) {
+				if line["message"] != tt.want[i] {
+					t.Errorf("message = %v, want %v", line["message"], tt.want[i])
+				}
+			}
+			if captured.String() != buf.String() {
+				t.Errorf("captured = %s, want the main output %s", captured, buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package slog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// MiddlewareOption configures the behaviour of HTTPMiddleware.
+type MiddlewareOption struct {
+	// DetailedTiming adds data.http_response.timing with the latency breakdown of the request.
+	DetailedTiming bool
+	// BodyAuditRate is the ratio of requests, between 0 and 1, whose JSON body is
+	// audited against the BodyAuditSpec registered for their route.
+	BodyAuditRate float64
+	// BodyAuditSample returns a number in [0, 1) to sample the audited requests, rand.Float64,
+	// or a source seeded with Config.SampleSeed when set, when nil.
+	BodyAuditSample func() float64
+	// BodyAuditRequestIDHeader names the header, e.g. X-Request-ID, whose value is hashed with
+	// Config.SampleSeed to sample a request, so a request ID always gets the same decision.
+	// Requests without the header are sampled by BodyAuditSample.
+	BodyAuditRequestIDHeader string
+	// BodyAuditBudget bounds the time spent auditing a body, DefaultBodyAuditBudget when zero.
+	BodyAuditBudget time.Duration
+	// DebugHeader names the header, e.g. X-Debug, marking a request to debug when set to true.
+	// Its log is written at debug level whatever the configured level, with debug: true
+	// and its bodies up to DebugMaxBodySize instead of MaxBodySize. Redaction still applies.
+	DebugHeader string
+	// DebugTrusted tells whether the DebugHeader of a request is honoured, e.g. for the requests
+	// of the internal network or carrying a secret. The header is ignored when nil.
+	DebugTrusted func(r *http.Request) bool
+	// DebugMaxBodySize bounds the bodies of a debug request, DefaultDebugMaxBodySize when 0.
+	DebugMaxBodySize int
+}
+
+// DefaultDebugMaxBodySize bounds the bodies of a debug request when MiddlewareOption.DebugMaxBodySize is 0.
+const DefaultDebugMaxBodySize = 1048576
+
+// debugRequest marks the RequestHTTP args of a request carrying the DebugHeader,
+// its bodies are bounded by maxBodySize instead of the configured sizes.
+type debugRequest struct {
+	maxBodySize int
+}
+
+func debugArgs(args []interface{}) (debugRequest, bool) {
+	for i := range args {
+		if debug, ok := args[i].(debugRequest); ok {
+			return debug, true
+		}
+	}
+	return debugRequest{}, false
+}
+
+func (o MiddlewareOption) debug(r *http.Request) bool {
+	return o.DebugHeader != "" && o.DebugTrusted != nil &&
+		strings.EqualFold(r.Header.Get(o.DebugHeader), "true") && o.DebugTrusted(r)
+}
+
+func (o MiddlewareOption) debugMaxBodySize() int {
+	if o.DebugMaxBodySize <= 0 {
+		return DefaultDebugMaxBodySize
+	}
+	return o.DebugMaxBodySize
+}
+
+// HTTPMiddleware returns a net/http middleware that writes a handler.http log for every request.
+func (s *SukiLogger) HTTPMiddleware(opts MiddlewareOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		name := handlerName(next)
+		sample := opts.BodyAuditSample
+		if sample == nil {
+			sample = s.config.sampler()
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := s.clock().Now
+			start := now()
+
+			debug := opts.debug(r)
+			requestLimit := captureLimit(s.config.maxRequestBodySize())
+			responseLimit := captureLimit(s.config.maxResponseBodySize())
+			if debug {
+				requestLimit = captureLimit(opts.debugMaxBodySize())
+				responseLimit = requestLimit
+			}
+
+			// The body is captured as the handler reads it, so a chunked body without
+			// Content-Length is captured the same way up to MaxRequestBodySize.
+			reqBody := &bodyCapture{limit: requestLimit}
+			if r.Body != nil && r.Body != http.NoBody {
+				reqBody.ReadCloser = r.Body
+				r.Body = reqBody
+			}
+
+			rec := &responseRecorder{
+				ResponseWriter: w,
+				body:           bodyCapture{limit: responseLimit},
+				now:            now,
+			}
+
+			handlerStart := now()
+			next.ServeHTTP(rec, r)
+			handlerEnd := now()
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			request := WithHTTPRequest(
+				r.Method,
+				r.URL.Path,
+				remoteIP(r.RemoteAddr),
+				flattenValues(r.Header),
+				nil,
+				flattenValues(r.URL.Query()),
+				reqBody.buf.String(),
+			)
+			request.Handler = name
+			request.RawQuery = r.URL.RawQuery
+			request.Protocol = protocol(r)
+			if r.TLS != nil {
+				request.TLSVersion = tlsVersionName(r.TLS.Version)
+				request.CipherSuite = tls.CipherSuiteName(r.TLS.CipherSuite)
+			}
+			route := r.URL.Path
+			if mux, ok := next.(*http.ServeMux); ok {
+				h, pattern := mux.Handler(r)
+				request.Handler = handlerName(h)
+				route = pattern
+			}
+
+			end := now()
+			response := WithHTTPResponse(
+				int64(rec.status),
+				toMillis(end.Sub(start)),
+				rec.body.buf.String(),
+			)
+
+			response.Trailers = trailers(rec.Header())
+
+			if opts.DetailedTiming {
+				response.Timing = rec.timing(start, handlerStart, handlerEnd, end)
+			}
+
+			var args []interface{}
+			requestSample := sample
+			if id := r.Header.Get(opts.BodyAuditRequestIDHeader); opts.BodyAuditRequestIDHeader != "" && id != "" {
+				requestSample = func() float64 { return requestIDSample(s.config.SampleSeed, id) }
+			}
+			if audit, ok := s.auditRequestBody(opts, requestSample, route, request.Body); ok {
+				args = append(args, audit)
+			}
+			if debug {
+				args = append(args, debugRequest{maxBodySize: opts.debugMaxBodySize()})
+			}
+
+			s.RequestHTTP("http request", request, response, args...)
+		})
+	}
+}
+
+// bodyCapture keeps a copy of the first limit bytes that pass through it.
+type bodyCapture struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture(p[:n])
+	return n, err
+}
+
+func (b *bodyCapture) capture(p []byte) {
+	if b.buf.Len()+len(p) > b.limit {
+		p = p[:b.limit-b.buf.Len()]
+	}
+	b.buf.Write(p)
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status    int
+	body      bodyCapture
+	firstByte time.Time
+	lastWrite time.Time
+	now       func() time.Time
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+		r.firstByte = r.now()
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+		r.firstByte = r.now()
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.body.capture(p[:n])
+	r.lastWrite = r.now()
+	return n, err
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hijacks the connection of the wrapped writer, e.g. for a websocket upgrade.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("slog: %T does not implement http.Hijacker", r.ResponseWriter)
+	}
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+		r.firstByte = r.now()
+	}
+	return h.Hijack()
+}
+
+// Push pushes target through the wrapped writer when it supports HTTP/2 server push.
+func (r *responseRecorder) Push(target string, opts *http.PushOptions) error {
+	if p, ok := r.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// ReadFrom lets the wrapped writer copy src its own way, capturing the body on the way.
+func (r *responseRecorder) ReadFrom(src io.Reader) (int64, error) {
+	rf, ok := r.ResponseWriter.(io.ReaderFrom)
+	if !ok {
+		return io.Copy(writerOnly{r}, src)
+	}
+	if r.status == 0 {
+		r.status = http.StatusOK
+		r.firstByte = r.now()
+	}
+	n, err := rf.ReadFrom(io.TeeReader(src, captureWriter{&r.body}))
+	r.lastWrite = r.now()
+	return n, err
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// writerOnly hides the ReadFrom of a writer so io.Copy goes through its Write.
+type writerOnly struct {
+	io.Writer
+}
+
+// captureWriter captures what is written to it in a bodyCapture.
+type captureWriter struct {
+	body *bodyCapture
+}
+
+func (c captureWriter) Write(p []byte) (int, error) {
+	c.body.capture(p)
+	return len(p), nil
+}
+
+func (r *responseRecorder) timing(start, handlerStart, handlerEnd, end time.Time) *HTTPTiming {
+	// A handler that never writes has its response committed by net/http once it returns.
+	firstByte := r.firstByte
+	if firstByte.IsZero() {
+		firstByte = handlerEnd
+	}
+
+	var write time.Duration
+	if !r.lastWrite.IsZero() {
+		write = r.lastWrite.Sub(firstByte)
+	}
+
+	return &HTTPTiming{
+		TotalMs:   toMillis(end.Sub(start)),
+		HandlerMs: toMillis(handlerEnd.Sub(handlerStart)),
+		WriteMs:   toMillis(write),
+		TTFBMs:    toMillis(firstByte.Sub(start)),
+	}
+}
+
+// handlerName returns the function name of a http.HandlerFunc or the type name of any other handler.
+func handlerName(h http.Handler) string {
+	if f, ok := h.(http.HandlerFunc); ok {
+		if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+			return fn.Name()
+		}
+	}
+	return reflect.TypeOf(h).String()
+}
+
+// protocol returns the protocol of r, telling HTTP/2 without TLS apart as h2c.
+func protocol(r *http.Request) string {
+	if r.ProtoMajor == 2 && r.TLS == nil {
+		return "h2c"
+	}
+	return r.Proto
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// trailers returns the trailers of a response once its handler returned, those
+// declared in the Trailer header and those set with the http.TrailerPrefix.
+func trailers(header http.Header) map[string]string {
+	result := make(map[string]string)
+	for _, declared := range header.Values("Trailer") {
+		for _, key := range strings.Split(declared, ",") {
+			key = http.CanonicalHeaderKey(strings.TrimSpace(key))
+			if value := header.Get(key); key != "" && value != "" {
+				result[key] = value
+			}
+		}
+	}
+	for key, values := range header {
+		if strings.HasPrefix(key, http.TrailerPrefix) && len(values) > 0 {
+			result[http.CanonicalHeaderKey(strings.TrimPrefix(key, http.TrailerPrefix))] = values[0]
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+func flattenValues(values map[string][]string) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		if len(v) > 0 {
+			result[k] = v[0]
+		}
+	}
+	return result
+}
+
+// auditRequestBody audits a sampled request body of route, it inspects the
+// captured copy of the body once truncated and redacted as it would be logged.
+func (s *SukiLogger) auditRequestBody(opts MiddlewareOption, sample func() float64, route string, body string) (BodyAudit, bool) {
+	if opts.BodyAuditRate <= 0 || body == "" {
+		return BodyAudit{}, false
+	}
+
+	spec, ok := bodyAuditSpec(route)
+	if !ok {
+		return BodyAudit{}, false
+	}
+
+	if sample() >= opts.BodyAuditRate {
+		return BodyAudit{}, false
+	}
+
+	if limit := s.config.maxRequestBodySize(); limit > 0 && len(body) > limit {
+		body = body[:limit]
+	}
+
+	budget := opts.BodyAuditBudget
+	if budget <= 0 {
+		budget = DefaultBodyAuditBudget
+	}
+	return auditBody(s.redactBody(body, route), spec, budget, s.clock().Now)
+}
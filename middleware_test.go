@@ -0,0 +1,571 @@
+package slog
+
+import (
+	"bufio"
+	"errors"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func timingOf(t *testing.T, line map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	response := line["data"].(map[string]interface{})["http_response"].(map[string]interface{})
+	timing, ok := response["timing"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("timing is missing from %v", response)
+	}
+	return timing
+}
+
+func TestHTTPMiddlewareDetailedTiming(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    func(clock *slogtest.FakeClock) http.HandlerFunc
+		wantStatus float64
+		want       map[string]interface{}
+	}{
+		{
+			name: "Handler never writes",
+			handler: func(clock *slogtest.FakeClock) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					clock.Advance(3 * time.Millisecond)
+				}
+			},
+			wantStatus: 200,
+			want: map[string]interface{}{
+				"duration_total_ms":   float64(3),
+				"duration_handler_ms": float64(3),
+				"duration_write_ms":   float64(0),
+				"ttfb_ms":             float64(3),
+			},
+		},
+		{
+			name: "Handler only calls WriteHeader",
+			handler: func(clock *slogtest.FakeClock) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					clock.Advance(2 * time.Millisecond)
+					w.WriteHeader(http.StatusNoContent)
+					clock.Advance(time.Millisecond)
+				}
+			},
+			wantStatus: 204,
+			want: map[string]interface{}{
+				"duration_total_ms":   float64(3),
+				"duration_handler_ms": float64(3),
+				"duration_write_ms":   float64(0),
+				"ttfb_ms":             float64(2),
+			},
+		},
+		{
+			name: "Handler writes body without WriteHeader",
+			handler: func(clock *slogtest.FakeClock) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					clock.Advance(5 * time.Millisecond)
+					_, _ = w.Write([]byte("hello"))
+				}
+			},
+			wantStatus: 200,
+			want: map[string]interface{}{
+				"duration_total_ms":   float64(5),
+				"duration_handler_ms": float64(5),
+				"duration_write_ms":   float64(0),
+				"ttfb_ms":             float64(5),
+			},
+		},
+		{
+			name: "Streaming handler",
+			handler: func(clock *slogtest.FakeClock) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					clock.Advance(time.Millisecond)
+					w.WriteHeader(http.StatusOK)
+					for i := 0; i < 3; i++ {
+						_, _ = w.Write([]byte("chunk"))
+						w.(http.Flusher).Flush()
+						clock.Advance(10 * time.Millisecond)
+					}
+					_, _ = w.Write([]byte("done"))
+				}
+			},
+			wantStatus: 200,
+			want: map[string]interface{}{
+				"duration_total_ms":   float64(31),
+				"duration_handler_ms": float64(31),
+				"duration_write_ms":   float64(30),
+				"ttfb_ms":             float64(1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			logger, buf := newTestLogger(c)
+			handler := logger.HTTPMiddleware(MiddlewareOption{DetailedTiming: true})(tt.handler(clock))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+			lines := decodeLines(t, buf)
+			if len(lines) != 1 {
+				t.Fatalf("got %d log lines, want 1", len(lines))
+			}
+			response := lines[0]["data"].(map[string]interface{})["http_response"].(map[string]interface{})
+			if response["status"] != tt.wantStatus {
+				t.Errorf("status = %v, want %v", response["status"], tt.wantStatus)
+			}
+
+			if timing := timingOf(t, lines[0]); !reflect.DeepEqual(timing, tt.want) {
+				t.Errorf("timing = %v, want %v", timing, tt.want)
+			}
+		})
+	}
+}
+
+// readerFromRecorder is a recorder whose writer copies bodies with ReadFrom.
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (r *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFrom = true
+	return io.Copy(r.ResponseRecorder.Body, src)
+}
+
+func TestHTTPMiddlewareResponseWriterInterfaces(t *testing.T) {
+	tests := []struct {
+		name       string
+		writer     func() http.ResponseWriter
+		handler    http.HandlerFunc
+		wantStatus float64
+		wantBody   interface{}
+	}{
+		{
+			name:   "ReadFrom forwarded and captured",
+			writer: func() http.ResponseWriter { return &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()} },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader(`{"status":"sent"}`))
+				if !w.(interface{ Unwrap() http.ResponseWriter }).Unwrap().(*readerFromRecorder).readFrom {
+					t.Error("ReadFrom of the wrapped writer not called")
+				}
+			},
+			wantStatus: 200,
+			wantBody:   `{"status":"sent"}`,
+		},
+		{
+			name:   "ReadFrom without a ReaderFrom writer",
+			writer: func() http.ResponseWriter { return httptest.NewRecorder() },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.(io.ReaderFrom).ReadFrom(strings.NewReader(`{"status":"sent"}`))
+			},
+			wantStatus: 200,
+			wantBody:   `{"status":"sent"}`,
+		},
+		{
+			name:   "Push not supported",
+			writer: func() http.ResponseWriter { return httptest.NewRecorder() },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if err := w.(http.Pusher).Push("/app.js", nil); !errors.Is(err, http.ErrNotSupported) {
+					t.Errorf("Push() error = %v, want http.ErrNotSupported", err)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: 204,
+			wantBody:   "",
+		},
+		{
+			name:   "Hijack not supported",
+			writer: func() http.ResponseWriter { return httptest.NewRecorder() },
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+					t.Error("Hijack() error = nil, want the writer not supporting it")
+				}
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantStatus: 204,
+			wantBody:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			handler := logger.HTTPMiddleware(MiddlewareOption{})(tt.handler)
+
+			handler.ServeHTTP(tt.writer(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+			lines := decodeLines(t, buf)
+			if len(lines) != 1 {
+				t.Fatalf("got %d log lines, want 1", len(lines))
+			}
+			response := lines[0]["data"].(map[string]interface{})["http_response"].(map[string]interface{})
+			if response["status"] != tt.wantStatus || response["body"] != tt.wantBody {
+				t.Errorf("status, body = %v, %v, want %v, %v", response["status"], response["body"], tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareHijack(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logged := make(chan struct{})
+	handler := logger.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack() error = %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+	}))
+	// A hijacked connection is not waited for by the server, the log is written once the middleware returns.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		close(logged)
+	}))
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: example.com\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n"))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want 101", resp.StatusCode)
+	}
+	select {
+	case <-logged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request not logged")
+	}
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1", len(lines))
+	}
+	response := lines[0]["data"].(map[string]interface{})["http_response"].(map[string]interface{})
+	if response["status"] != float64(http.StatusSwitchingProtocols) {
+		t.Errorf("status = %v, want 101", response["status"])
+	}
+}
+
+func TestHTTPMiddlewareWithoutDetailedTiming(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	handler := logger.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ping?a=1", strings.NewReader("ping")))
+
+	lines := decodeLines(t, buf)
+	data := lines[0]["data"].(map[string]interface{})
+	request := data["http_request"].(map[string]interface{})
+	response := data["http_response"].(map[string]interface{})
+	if _, ok := response["timing"]; ok {
+		t.Errorf("timing should be omitted, got %v", response["timing"])
+	}
+	if response["body"] != "pong" {
+		t.Errorf("response body = %v, want pong", response["body"])
+	}
+	if request["path"] != "/ping" || request["query"].(map[string]interface{})["a"] != "1" {
+		t.Errorf("unexpected request %v", request)
+	}
+}
+
+func getOrder(w http.ResponseWriter, r *http.Request) {}
+
+type orderHandler struct{}
+
+func (orderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+func TestHTTPMiddlewareHandlerName(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", getOrder)
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    string
+	}{
+		{
+			name:    "Named handler func",
+			handler: http.HandlerFunc(getOrder),
+			want:    "github.com/Sellsuki/sellsuki-go-logger.getOrder",
+		},
+		{
+			name:    "Handler resolved by ServeMux",
+			handler: mux,
+			want:    "github.com/Sellsuki/sellsuki-go-logger.getOrder",
+		},
+		{
+			name:    "Handler type",
+			handler: orderHandler{},
+			want:    "slog.orderHandler",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			logger.HTTPMiddleware(MiddlewareOption{})(tt.handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+			request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+			if request["handler"] != tt.want {
+				t.Errorf("handler = %v, want %v", request["handler"], tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareProtocol(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	server := httptest.NewUnstartedServer(logger.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.Header().Set("Content-Type", "application/grpc-web")
+		_, _ = w.Write([]byte("payload"))
+		w.Header().Set("Grpc-Status", "14")
+		w.Header().Set("Grpc-Message", "unavailable")
+		w.Header().Set(http.TrailerPrefix+"X-Checksum", "abc")
+	})))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	server.Close()
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	request := data["http_request"].(map[string]interface{})
+	response := data["http_response"].(map[string]interface{})
+
+	if request["protocol"] != "HTTP/2.0" || request["tls_version"] != "TLS 1.3" || request["cipher_suite"] == nil {
+		t.Errorf("protocol, tls_version, cipher_suite = %v, %v, %v, want HTTP/2.0 over TLS 1.3", request["protocol"], request["tls_version"], request["cipher_suite"])
+	}
+
+	wantTrailers := map[string]interface{}{"Grpc-Status": "14", "Grpc-Message": "unavailable", "X-Checksum": "abc"}
+	if !reflect.DeepEqual(response["trailers"], wantTrailers) {
+		t.Errorf("trailers = %v, want %v", response["trailers"], wantTrailers)
+	}
+	if name := response["error"].(map[string]interface{})["name"]; name != "grpc_status_14" {
+		t.Errorf("error.name = %v, want grpc_status_14", name)
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *http.Request
+		want    string
+	}{
+		{
+			name:    "HTTP/1.1",
+			request: &http.Request{Proto: "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1},
+			want:    "HTTP/1.1",
+		},
+		{
+			name:    "HTTP/2 without TLS",
+			request: &http.Request{Proto: "HTTP/2.0", ProtoMajor: 2},
+			want:    "h2c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protocol(tt.request); got != tt.want {
+				t.Errorf("protocol() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareChunkedBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxBodySize int
+		body        string
+		wantBody    string
+	}{
+		{
+			name:        "Within MaxBodySize",
+			maxBodySize: 1024,
+			body:        strings.Repeat("chunk", 100),
+			wantBody:    strings.Repeat("chunk", 100),
+		},
+		{
+			name:        "Over MaxBodySize",
+			maxBodySize: 64,
+			body:        strings.Repeat("chunk", 100),
+			wantBody:    "body is too large",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MaxBodySize = tt.maxBodySize
+			logger, buf := newTestLogger(c)
+
+			var transferEncoding []string
+			var received string
+			server := httptest.NewServer(logger.HTTPMiddleware(MiddlewareOption{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				transferEncoding = r.TransferEncoding
+				b, _ := io.ReadAll(r.Body)
+				received = string(b)
+			})))
+
+			// A reader of unknown length is sent with chunked transfer encoding.
+			resp, err := http.Post(server.URL, "text/plain", io.MultiReader(strings.NewReader(tt.body)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			server.Close()
+
+			if !reflect.DeepEqual(transferEncoding, []string{"chunked"}) {
+				t.Fatalf("transfer encoding = %v, want chunked", transferEncoding)
+			}
+			if received != tt.body {
+				t.Errorf("handler read %d bytes, want the whole body of %d bytes", len(received), len(tt.body))
+			}
+
+			request := decodeLines(t, buf)[0]["data"].(map[string]interface{})["http_request"].(map[string]interface{})
+			if request["body"] != tt.wantBody {
+				t.Errorf("body = %v, want %v", request["body"], tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHTTPMiddlewareDebugHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		level        LogLevel
+		header       string
+		untrusted    bool
+		debugMax     int
+		wantLines    int
+		wantLevel    string
+		wantDebug    interface{}
+		wantReqBody  string
+		wantRespBody string
+	}{
+		{
+			name:         "Debug request logged below the configured level",
+			level:        LevelWarn,
+			header:       "true",
+			wantLines:    1,
+			wantLevel:    "debug",
+			wantDebug:    true,
+			wantReqBody:  `{"order_id":"1234"}`,
+			wantRespBody: `{"status":"created"}`,
+		},
+		{
+			name:         "Header is case-insensitive",
+			level:        LevelWarn,
+			header:       "TRUE",
+			wantLines:    1,
+			wantLevel:    "debug",
+			wantDebug:    true,
+			wantReqBody:  `{"order_id":"1234"}`,
+			wantRespBody: `{"status":"created"}`,
+		},
+		{
+			name:      "Other request filtered by the configured level",
+			level:     LevelWarn,
+			wantLines: 0,
+		},
+		{
+			name:      "Header of an untrusted request ignored",
+			level:     LevelWarn,
+			header:    "true",
+			untrusted: true,
+			wantLines: 0,
+		},
+		{
+			name:         "Debug bodies bounded by DebugMaxBodySize",
+			level:        LevelWarn,
+			header:       "true",
+			debugMax:     10,
+			wantLines:    1,
+			wantLevel:    "debug",
+			wantDebug:    true,
+			wantReqBody:  "body is too large",
+			wantRespBody: "body is too large",
+		},
+		{
+			name:      "Header not true",
+			level:     LevelWarn,
+			header:    "1",
+			wantLines: 0,
+		},
+		{
+			name:         "Other request keeps MaxBodySize",
+			level:        LevelInfo,
+			wantLines:    1,
+			wantLevel:    "info",
+			wantDebug:    nil,
+			wantReqBody:  "body is too large",
+			wantRespBody: "body is too large",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.LogLevel = tt.level
+			c.MaxBodySize = 8
+			logger, buf := newTestLogger(c)
+			handler := logger.HTTPMiddleware(MiddlewareOption{
+				DebugHeader:      "X-Debug",
+				DebugTrusted:     func(r *http.Request) bool { return !tt.untrusted },
+				DebugMaxBodySize: tt.debugMax,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.ReadAll(r.Body)
+				_, _ = w.Write([]byte(`{"status":"created"}`))
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"order_id":"1234"}`))
+			if tt.header != "" {
+				r.Header.Set("X-Debug", tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if buf.Len() == 0 {
+				if tt.wantLines != 0 {
+					t.Fatalf("got no line, want %d", tt.wantLines)
+				}
+				return
+			}
+			lines := decodeLines(t, buf)
+			if len(lines) != tt.wantLines {
+				t.Fatalf("got %d lines, want %d", len(lines), tt.wantLines)
+			}
+
+			data := lines[0]["data"].(map[string]interface{})
+			if lines[0]["level"] != tt.wantLevel || data["debug"] != tt.wantDebug {
+				t.Errorf("level, debug = %v, %v, want %v, %v", lines[0]["level"], data["debug"], tt.wantLevel, tt.wantDebug)
+			}
+			if body := data["http_request"].(map[string]interface{})["body"]; body != tt.wantReqBody {
+				t.Errorf("request body = %v, want %v", body, tt.wantReqBody)
+			}
+			if body := data["http_response"].(map[string]interface{})["body"]; body != tt.wantRespBody {
+				t.Errorf("response body = %v, want %v", body, tt.wantRespBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package slog
+
+import "go.uber.org/zap/zapcore"
+
+type MigrationDirection string
+
+const (
+	MigrationUp   MigrationDirection = "up"
+	MigrationDown MigrationDirection = "down"
+)
+
+type MigrationStatus string
+
+const (
+	MigrationSucceeded MigrationStatus = "succeeded"
+	MigrationFailed    MigrationStatus = "failed"
+)
+
+// MigrationInfo is the data.migration of a migration log, a run of a schema
+// migration. Duration is in milliseconds.
+type MigrationInfo struct {
+	Version         string             `json:"version"`
+	Direction       MigrationDirection `json:"direction"`
+	Duration        float64            `json:"duration"`
+	DurationAnomaly string             `json:"duration_anomaly,omitempty"`
+	Status          MigrationStatus    `json:"status"`
+	Error           ErrorInfo          `json:"error"`
+}
+
+func WithMigration(version string, direction MigrationDirection, duration float64, status MigrationStatus, error ...ErrorInfo) MigrationInfo {
+	var e ErrorInfo
+	if len(error) > 0 {
+		e = error[0]
+	}
+	return MigrationInfo{
+		Version:   version,
+		Direction: direction,
+		Duration:  duration,
+		Status:    status,
+		Error:     e,
+	}
+}
+
+// Migration writes a migration log of a schema migration run, at error level
+// when it failed.
+func (s SukiLogger) Migration(info MigrationInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	info.Error = s.formatError(info.Error)
+	info.DurationAnomaly = s.checkDuration(&info.Duration)
+	data["migration"] = info
+
+	level := zapcore.InfoLevel
+	if info.Status == MigrationFailed {
+		level = zapcore.ErrorLevel
+	}
+
+	if ce := s.sampledLogger("migration", alertLevel, level, "migration "+info.Version+" "+string(info.Direction), data).Check(level, "migration "+info.Version+" "+string(info.Direction)); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,71 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigration(t *testing.T) {
+	tests := []struct {
+		name        string
+		info        MigrationInfo
+		wantLevel   string
+		wantMessage string
+		want        map[string]interface{}
+	}{
+		{
+			name:        "Up",
+			info:        WithMigration("20240301120000", MigrationUp, 1250, MigrationSucceeded),
+			wantLevel:   "info",
+			wantMessage: "migration 20240301120000 up",
+			want: map[string]interface{}{
+				"version":   "20240301120000",
+				"direction": "up",
+				"duration":  float64(1250),
+				"status":    "succeeded",
+				"error":     map[string]interface{}{"name": "", "stack_trace": ""},
+			},
+		},
+		{
+			name:        "Down",
+			info:        WithMigration("20240301120000", MigrationDown, 300, MigrationSucceeded),
+			wantLevel:   "info",
+			wantMessage: "migration 20240301120000 down",
+			want: map[string]interface{}{
+				"version":   "20240301120000",
+				"direction": "down",
+				"duration":  float64(300),
+				"status":    "succeeded",
+				"error":     map[string]interface{}{"name": "", "stack_trace": ""},
+			},
+		},
+		{
+			name:        "Failed",
+			info:        WithMigration("20240302090000", MigrationUp, 80, MigrationFailed, WithError(`relation "orders" already exists`)),
+			wantLevel:   "error",
+			wantMessage: "migration 20240302090000 up",
+			want: map[string]interface{}{
+				"version":   "20240302090000",
+				"direction": "up",
+				"duration":  float64(80),
+				"status":    "failed",
+				"error":     map[string]interface{}{"name": `relation "orders" already exists`, "stack_trace": ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.Migration(tt.info, WithTracing("trace", "span"))
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "migration" || line["level"] != tt.wantLevel || line["message"] != tt.wantMessage {
+				t.Errorf("log_type, level, message = %v, %v, %v, want migration, %s, %s", line["log_type"], line["level"], line["message"], tt.wantLevel, tt.wantMessage)
+			}
+			if got := line["data"].(map[string]interface{})["migration"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("migration = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// monotonicEncoder appends to every line the milliseconds elapsed since the
+// logger was configured. The elapsed time is read from the monotonic clock, a
+// gap between it and the timestamps of two lines reveals a wall-clock step.
+type monotonicEncoder struct {
+	zapcore.Encoder
+	key   string
+	start time.Time
+}
+
+func (e monotonicEncoder) Clone() zapcore.Encoder {
+	return monotonicEncoder{Encoder: e.Encoder.Clone(), key: e.key, start: e.start}
+}
+
+func (e monotonicEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fields = append(fields[:len(fields):len(fields)], zap.Float64(e.key, toMillis(ent.Time.Sub(e.start))))
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+// uptimeEncoder appends to every line the seconds elapsed since the logger was first configured.
+type uptimeEncoder struct {
+	zapcore.Encoder
+	key   string
+	start time.Time
+}
+
+func (e uptimeEncoder) Clone() zapcore.Encoder {
+	return uptimeEncoder{Encoder: e.Encoder.Clone(), key: e.key, start: e.start}
+}
+
+func (e uptimeEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	fields = append(fields[:len(fields):len(fields)], zap.Float64(e.key, ent.Time.Sub(e.start).Seconds()))
+	return e.Encoder.EncodeEntry(ent, fields)
+}
@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"testing"
+	"time"
+)
+
+func TestIncludeMonotonic(t *testing.T) {
+	tests := []struct {
+		name            string
+		includeLineSize bool
+		prefix          string
+		key             string
+	}{
+		{
+			name: "Monotonic milliseconds",
+			key:  "monotonic_ms",
+		},
+		{
+			name:            "With line size",
+			includeLineSize: true,
+			key:             "monotonic_ms",
+		},
+		{
+			name:   "Prefixed key",
+			prefix: "slog_",
+			key:    "slog_monotonic_ms",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			c.IncludeMonotonic = true
+			c.IncludeLineSize = tt.includeLineSize
+			c.FieldPrefix = tt.prefix
+			logger, buf := newTestLogger(c)
+
+			clock.Advance(1500 * time.Millisecond)
+			logger.Info("first")
+			clock.Advance(250 * time.Millisecond)
+			logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, -5, ""))
+
+			lines := decodeLines(t, buf)
+			if lines[0][tt.key] != float64(1500) || lines[1][tt.key] != float64(1750) {
+				t.Errorf("%s = %v, %v, want 1500, 1750", tt.key, lines[0][tt.key], lines[1][tt.key])
+			}
+			if tt.includeLineSize && lines[0]["log_size_bytes"] == nil {
+				t.Error("log_size_bytes missing")
+			}
+
+			response := lines[1][tt.prefix+"data"].(map[string]interface{})["http_response"].(map[string]interface{})
+			if response["duration"] != float64(0) || response["duration_anomaly"] != DurationNegative {
+				t.Errorf("duration, duration_anomaly = %v, %v, want 0, %v", response["duration"], response["duration_anomaly"], DurationNegative)
+			}
+		})
+	}
+}
+
+func TestIncludeMonotonicDisabled(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.Info("message")
+
+	if _, ok := decodeLines(t, buf)[0]["monotonic_ms"]; ok {
+		t.Error("monotonic_ms written without IncludeMonotonic")
+	}
+}
+
+func TestIncludeUptime(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		key    string
+	}{
+		{
+			name: "Uptime seconds",
+			key:  "uptime_seconds",
+		},
+		{
+			name:   "Prefixed key",
+			prefix: "slog_",
+			key:    "slog_uptime_seconds",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			c.IncludeUptime = true
+			c.FieldPrefix = tt.prefix
+			logger, buf := newTestLogger(c)
+
+			clock.Advance(1500 * time.Millisecond)
+			logger.Info("first")
+			clock.Advance(time.Minute)
+			logger.Event("second", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"))
+
+			// Configuring again keeps the start of the logger.
+			c.Output = buf
+			if err := logger.Configure(c); err != nil {
+				t.Fatal(err)
+			}
+			clock.Advance(time.Second)
+			logger.Info("third")
+
+			lines := decodeLines(t, buf)
+			if lines[0][tt.key] != 1.5 || lines[1][tt.key] != 61.5 || lines[2][tt.key] != 62.5 {
+				t.Errorf("%s = %v, %v, %v, want 1.5, 61.5, 62.5", tt.key, lines[0][tt.key], lines[1][tt.key], lines[2][tt.key])
+			}
+		})
+	}
+}
+
+func TestUptimeDisabled(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.Info("first")
+
+	if line := decodeLines(t, buf)[0]; line["uptime_seconds"] != nil {
+		t.Errorf("uptime_seconds = %v, want none", line["uptime_seconds"])
+	}
+}
@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationQueued    NotificationStatus = "queued"
+	NotificationSent      NotificationStatus = "sent"
+	NotificationDelivered NotificationStatus = "delivered"
+	NotificationFailed    NotificationStatus = "failed"
+)
+
+// NotificationInfo describes the dispatch of a notification, Recipient is
+// written hashed unless Config.RawNotificationRecipients is set.
+type NotificationInfo struct {
+	Channel   string             `json:"channel"`
+	Recipient string             `json:"recipient"`
+	Template  string             `json:"template"`
+	Status    NotificationStatus `json:"status"`
+}
+
+func WithNotification(channel string, recipient string, template string, status NotificationStatus) NotificationInfo {
+	return NotificationInfo{
+		Channel:   channel,
+		Recipient: recipient,
+		Template:  template,
+		Status:    status,
+	}
+}
+
+// Notification writes a notification log, at warn level when the dispatch failed.
+func (s SukiLogger) Notification(info NotificationInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	if !s.config.RawNotificationRecipients {
+		info.Recipient = hashRecipient(info.Recipient)
+	}
+	data["notification"] = info
+
+	level := zapcore.InfoLevel
+	if info.Status == NotificationFailed {
+		level = zapcore.WarnLevel
+	}
+
+	if ce := s.sampledLogger("notification", alertLevel, level, "notification", data).Check(level, "notification"); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// hashRecipient hashes a recipient normalized so the same address always has the same hash.
+func hashRecipient(recipient string) string {
+	if recipient == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(recipient))))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
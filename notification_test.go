@@ -0,0 +1,69 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestNotification(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           bool
+		info          NotificationInfo
+		wantRecipient string
+		wantLevel     string
+	}{
+		{
+			name:          "Recipient hashed by default",
+			info:          WithNotification("email", " Jane@Example.com", "order_confirmed", NotificationSent),
+			wantRecipient: hashRecipient("jane@example.com"),
+			wantLevel:     "info",
+		},
+		{
+			name:          "Raw recipient",
+			raw:           true,
+			info:          WithNotification("sms", "+66812345678", "otp", NotificationDelivered),
+			wantRecipient: "+66812345678",
+			wantLevel:     "info",
+		},
+		{
+			name:          "Failed dispatch",
+			info:          WithNotification("line", "U1234", "shipment", NotificationFailed),
+			wantRecipient: hashRecipient("U1234"),
+			wantLevel:     "warn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.RawNotificationRecipients = tt.raw
+			logger, buf := newTestLogger(c)
+
+			logger.Notification(tt.info)
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "notification" || line["level"] != tt.wantLevel {
+				t.Errorf("log_type, level = %v, %v, want notification, %v", line["log_type"], line["level"], tt.wantLevel)
+			}
+
+			notification := line["data"].(map[string]interface{})["notification"].(map[string]interface{})
+			if notification["recipient"] != tt.wantRecipient {
+				t.Errorf("recipient = %v, want %v", notification["recipient"], tt.wantRecipient)
+			}
+			if notification["status"] != string(tt.info.Status) || notification["template"] != tt.info.Template {
+				t.Errorf("unexpected notification %v", notification)
+			}
+		})
+	}
+}
+
+func TestHashRecipient(t *testing.T) {
+	if hashRecipient("jane@example.com") != hashRecipient(" JANE@example.com ") {
+		t.Error("hashRecipient() differs for the same address")
+	}
+	if hashRecipient("jane@example.com") == hashRecipient("john@example.com") {
+		t.Error("hashRecipient() is the same for different addresses")
+	}
+	if hashRecipient("") != "" {
+		t.Error("hashRecipient() of an empty recipient should be empty")
+	}
+}
@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// omitEmpty returns data without the keys holding nil, an empty string, an empty
+// object or an empty array, at any depth. Structs are compared by their JSON form.
+func (s SukiLogger) omitEmpty(data map[string]interface{}) map[string]interface{} {
+	b, err := marshalNoEscape(data)
+	if err != nil {
+		s.internalError(err)
+		return data
+	}
+
+	var generic map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		s.internalError(err)
+		return data
+	}
+
+	pruneEmpty(generic)
+	return generic
+}
+
+func pruneEmpty(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			pruneEmpty(child)
+			if isEmpty(child) {
+				delete(v, k)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			pruneEmpty(child)
+		}
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOmitEmpty(t *testing.T) {
+	tests := []struct {
+		name      string
+		omitEmpty bool
+		want      map[string]interface{}
+	}{
+		{
+			name:      "Enabled",
+			omitEmpty: true,
+			want: map[string]interface{}{
+				"http_request": map[string]interface{}{
+					"method": "GET",
+					"path":   "/orders",
+				},
+				"http_response": map[string]interface{}{
+					"status":   float64(200),
+					"duration": float64(12),
+				},
+			},
+		},
+		{
+			name:      "Disabled",
+			omitEmpty: false,
+			want: map[string]interface{}{
+				"http_request": map[string]interface{}{
+					"method":    "GET",
+					"path":      "/orders",
+					"remote_ip": "",
+					"headers":   map[string]interface{}{},
+					"params":    map[string]interface{}{},
+					"query":     map[string]interface{}{},
+					"body":      "",
+				},
+				"http_response": map[string]interface{}{
+					"status":   float64(200),
+					"duration": float64(12),
+					"body":     "",
+					"error":    map[string]interface{}{"name": "", "stack_trace": ""},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewProductionConfig()
+			config.OmitEmpty = tt.omitEmpty
+			logger, buf := newTestLogger(config)
+
+			logger.RequestHTTP("http", WithHTTPRequest("GET", "/orders", "", nil, nil, nil, ""), WithHTTPResponse(200, 12, ""))
+
+			line := decodeLines(t, buf)[0]
+			if got := line["data"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("data = %v, want %v", got, tt.want)
+			}
+			if line["app_name"] != "application" || line["log_type"] != "handler.http" {
+				t.Errorf("reserved fields are missing from %v", line)
+			}
+		})
+	}
+}
+
+func TestOmitEmptyApplicationLog(t *testing.T) {
+	config := NewProductionConfig()
+	config.OmitEmpty = true
+	logger, buf := newTestLogger(config)
+
+	logger.Info("hello", Any("empty", ""), Any("nil", nil), Any("list", []string{}), Any("id", int64(9007199254740993)))
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	want := map[string]interface{}{"application": map[string]interface{}{"id": float64(9007199254740993)}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	if !strings.Contains(buf.String(), `"id":9007199254740993`) {
+		t.Errorf("large integer lost precision: %s", buf.String())
+	}
+}
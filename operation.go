@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"context"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+type operationKey struct{}
+
+// Operation tallies the sub-events of an operation to be written as a single
+// operation_summary log by End.
+type Operation struct {
+	logger *SukiLogger
+	name   string
+	start  time.Time
+
+	mu       sync.Mutex
+	errors   int
+	warnings int
+	retries  int
+	counts   map[string]int
+}
+
+// OperationSummary is the data.operation_summary of an operation_summary log, Duration is in milliseconds.
+type OperationSummary struct {
+	Name            string         `json:"name"`
+	Duration        float64        `json:"duration"`
+	DurationAnomaly string         `json:"duration_anomaly,omitempty"`
+	Errors          int            `json:"errors"`
+	Warnings        int            `json:"warnings"`
+	Retries         int            `json:"retries"`
+	Counts          map[string]int `json:"counts,omitempty"`
+}
+
+// StartOperation starts an Operation named name, e.g.
+//
+//	op := slog.L().StartOperation("import-orders")
+//	defer op.End(slog.WithTracing(traceID, spanID))
+func (s *SukiLogger) StartOperation(name string) *Operation {
+	return &Operation{logger: s, name: name, start: s.clock().Now()}
+}
+
+// ContextWithOperation returns a copy of ctx carrying op.
+func ContextWithOperation(ctx context.Context, op *Operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFromContext returns the Operation carried by ctx, if any.
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(*Operation)
+	return op, ok
+}
+
+func (o *Operation) Error() {
+	o.mu.Lock()
+	o.errors++
+	o.mu.Unlock()
+}
+
+func (o *Operation) Warning() {
+	o.mu.Lock()
+	o.warnings++
+	o.mu.Unlock()
+}
+
+func (o *Operation) Retry() {
+	o.mu.Lock()
+	o.retries++
+	o.mu.Unlock()
+}
+
+// Count adds n to the count of the sub-events named name, e.g. "skipped".
+func (o *Operation) Count(name string, n int) {
+	o.mu.Lock()
+	if o.counts == nil {
+		o.counts = make(map[string]int)
+	}
+	o.counts[name] += n
+	o.mu.Unlock()
+}
+
+// Summary returns the tallies recorded so far.
+func (o *Operation) Summary() OperationSummary {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var counts map[string]int
+	if len(o.counts) > 0 {
+		counts = make(map[string]int, len(o.counts))
+		for k, v := range o.counts {
+			counts[k] = v
+		}
+	}
+
+	return OperationSummary{
+		Name:     o.name,
+		Duration: toMillis(o.logger.clock().Now().Sub(o.start)),
+		Errors:   o.errors,
+		Warnings: o.warnings,
+		Retries:  o.retries,
+		Counts:   counts,
+	}
+}
+
+// End writes the operation_summary log, at warn level when an error or a warning was recorded.
+func (o *Operation) End(args ...interface{}) {
+	summary := o.Summary()
+
+	data := make(map[string]interface{})
+	alertLevel := o.logger.requestArgs(data, args)
+	summary.DurationAnomaly = o.logger.checkDuration(&summary.Duration)
+	data["operation_summary"] = summary
+
+	level := zapcore.InfoLevel
+	if summary.Errors > 0 || summary.Warnings > 0 {
+		level = zapcore.WarnLevel
+	}
+
+	if ce := o.logger.sampledLogger("operation_summary", alertLevel, level, o.name, data).Check(level, o.name); ce != nil {
+		withCaller(ce, args)
+		ce.Write(o.logger.envelope(alertLevel, data)...)
+	}
+}
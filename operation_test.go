@@ -0,0 +1,115 @@
+package slog
+
+import (
+	"context"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		record    func(op *Operation)
+		wantLevel string
+		want      map[string]interface{}
+	}{
+		{
+			name:      "Clean operation",
+			record:    func(op *Operation) {},
+			wantLevel: "info",
+			want: map[string]interface{}{
+				"name":     "import",
+				"duration": float64(1500),
+				"errors":   float64(0),
+				"warnings": float64(0),
+				"retries":  float64(0),
+			},
+		},
+		{
+			name: "Retries only",
+			record: func(op *Operation) {
+				op.Retry()
+				op.Retry()
+				op.Count("skipped", 3)
+				op.Count("skipped", 1)
+			},
+			wantLevel: "info",
+			want: map[string]interface{}{
+				"name":     "import",
+				"duration": float64(1500),
+				"errors":   float64(0),
+				"warnings": float64(0),
+				"retries":  float64(2),
+				"counts":   map[string]interface{}{"skipped": float64(4)},
+			},
+		},
+		{
+			name: "Errors and warnings",
+			record: func(op *Operation) {
+				op.Error()
+				op.Warning()
+				op.Warning()
+				op.Retry()
+			},
+			wantLevel: "warn",
+			want: map[string]interface{}{
+				"name":     "import",
+				"duration": float64(1500),
+				"errors":   float64(1),
+				"warnings": float64(2),
+				"retries":  float64(1),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			logger, buf := newTestLogger(c)
+
+			op := logger.StartOperation("import")
+			tt.record(op)
+			clock.Advance(1500 * time.Millisecond)
+			op.End()
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "operation_summary" || line["level"] != tt.wantLevel || line["message"] != "import" {
+				t.Errorf("log_type, level, message = %v, %v, %v", line["log_type"], line["level"], line["message"])
+			}
+			if got := line["data"].(map[string]interface{})["operation_summary"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("operation_summary = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOperationFromContext(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	op := logger.StartOperation("import")
+	ctx := ContextWithOperation(context.Background(), op)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if op, ok := OperationFromContext(ctx); ok {
+				op.Error()
+				op.Count("rows", 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary := op.Summary()
+	if summary.Errors != 10 || summary.Counts["rows"] != 100 {
+		t.Errorf("errors, rows = %d, %d, want 10, 100", summary.Errors, summary.Counts["rows"])
+	}
+	if _, ok := OperationFromContext(context.Background()); ok {
+		t.Error("OperationFromContext() found an operation in an empty context")
+	}
+}
@@ -0,0 +1,98 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+type PaymentEvent string
+
+const (
+	PaymentAuthorized PaymentEvent = "authorized"
+	PaymentCaptured   PaymentEvent = "captured"
+	PaymentRefunded   PaymentEvent = "refunded"
+	PaymentVoided     PaymentEvent = "voided"
+	PaymentFailed     PaymentEvent = "failed"
+)
+
+// PaymentInfo is the data.payment of a payment log. Amount is a decimal string,
+// e.g. "199.90", so no precision is lost to floats.
+type PaymentInfo struct {
+	TransactionID string `json:"transaction_id"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	Provider      string `json:"provider"`
+	// Card is the card number, only its last four digits are written.
+	Card string `json:"card,omitempty"`
+	// Metadata are the details given by the provider, card numbers found in the values are redacted.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func WithPayment(transactionID string, amount string, currency string, status string, provider string) PaymentInfo {
+	return PaymentInfo{
+		TransactionID: transactionID,
+		Amount:        amount,
+		Currency:      currency,
+		Status:        status,
+		Provider:      provider,
+	}
+}
+
+// Payment writes a payment log of a transaction lifecycle event, at warn level
+// when the payment failed. Card numbers are never written in full.
+func (s SukiLogger) Payment(event PaymentEvent, info PaymentInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	data["payment_event"] = event
+	data["payment"] = redactPayment(info)
+
+	level := zapcore.InfoLevel
+	if event == PaymentFailed {
+		level = zapcore.WarnLevel
+	}
+
+	if ce := s.sampledLogger("payment", alertLevel, level, "payment "+string(event), data).Check(level, "payment "+string(event)); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// redactPayment masks the card number of info to its last four digits and the
+// card numbers found in its other fields.
+func redactPayment(info PaymentInfo) PaymentInfo {
+	cards := RedactPatterns{Card: true}
+
+	info.TransactionID = cards.redactString(info.TransactionID)
+	info.Status = cards.redactString(info.Status)
+	info.Provider = cards.redactString(info.Provider)
+	info.Card = lastFour(info.Card)
+
+	if len(info.Metadata) > 0 {
+		metadata := make(map[string]string, len(info.Metadata))
+		for k, v := range info.Metadata {
+			metadata[k] = cards.redactString(v)
+		}
+		info.Metadata = metadata
+	}
+	return info
+}
+
+// lastFour returns card masked but for its last four digits, e.g. "****4242".
+func lastFour(card string) string {
+	var digits strings.Builder
+	for _, r := range card {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	if digits.Len() == 0 {
+		return ""
+	}
+	d := digits.String()
+	if len(d) <= 4 {
+		return strings.Repeat("*", len(d))
+	}
+	return "****" + d[len(d)-4:]
+}
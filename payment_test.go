@@ -0,0 +1,97 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPayment(t *testing.T) {
+	const pan = "4242 4242 4242 4242"
+
+	info := WithPayment("txn-1", "199.90", "THB", "approved", "omise")
+	info.Card = pan
+	info.Metadata = map[string]string{"description": "charge of card " + pan, "order_id": "42"}
+
+	tests := []struct {
+		name      string
+		event     PaymentEvent
+		status    string
+		wantLevel string
+	}{
+		{
+			name:      "Authorized",
+			event:     PaymentAuthorized,
+			status:    "approved",
+			wantLevel: "info",
+		},
+		{
+			name:      "Captured",
+			event:     PaymentCaptured,
+			status:    "settled",
+			wantLevel: "info",
+		},
+		{
+			name:      "Failed",
+			event:     PaymentFailed,
+			status:    "declined card " + pan,
+			wantLevel: "warn",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			info := info
+			info.Status = tt.status
+
+			logger.Payment(tt.event, info, WithTracing("trace-1", "span-1"))
+
+			if strings.Contains(buf.String(), "4242 4242") || strings.Contains(buf.String(), "4242424242424242") {
+				t.Fatalf("card number logged: %s", buf)
+			}
+
+			line := decodeLines(t, buf)[0]
+			if line["log_type"] != "payment" || line["level"] != tt.wantLevel || line["message"] != "payment "+string(tt.event) {
+				t.Errorf("log_type, level, message = %v, %v, %v, want payment, %v, payment %v", line["log_type"], line["level"], line["message"], tt.wantLevel, tt.event)
+			}
+
+			data := line["data"].(map[string]interface{})
+			if data["payment_event"] != string(tt.event) {
+				t.Errorf("payment_event = %v, want %v", data["payment_event"], tt.event)
+			}
+			payment := data["payment"].(map[string]interface{})
+			want := map[string]interface{}{
+				"transaction_id": "txn-1",
+				"amount":         "199.90",
+				"currency":       "THB",
+				"status":         strings.Replace(tt.status, pan, redactedValue, 1),
+				"provider":       "omise",
+				"card":           "****4242",
+				"metadata":       map[string]interface{}{"description": "charge of card " + redactedValue, "order_id": "42"},
+			}
+			if !reflect.DeepEqual(payment, want) {
+				t.Errorf("payment = %v, want %v", payment, want)
+			}
+			if info.Metadata["description"] != "charge of card "+pan {
+				t.Errorf("metadata of the caller modified: %v", info.Metadata)
+			}
+		})
+	}
+}
+
+func TestLastFour(t *testing.T) {
+	tests := []struct {
+		card string
+		want string
+	}{
+		{card: "4242-4242-4242-4242", want: "****4242"},
+		{card: "378282246310005", want: "****0005"},
+		{card: "123", want: "***"},
+		{card: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := lastFour(tt.card); got != tt.want {
+			t.Errorf("lastFour(%q) = %q, want %q", tt.card, got, tt.want)
+		}
+	}
+}
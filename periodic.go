@@ -0,0 +1,75 @@
+package slog
+
+import (
+	"fmt"
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"sync"
+	"time"
+)
+
+// periodicEmitter calls emit every interval until stopped, it backs the
+// periodic logs such as heartbeats and cache or pool stats. A panic of emit,
+// e.g. of a stats callback of the application, is reported on the error output
+// and the next emission is still scheduled.
+type periodicEmitter struct {
+	logger   *SukiLogger
+	name     string
+	interval time.Duration
+	emit     func()
+
+	mu      sync.Mutex
+	timer   clock.Timer
+	stopped bool
+}
+
+// startPeriodic schedules the first emission of a periodicEmitter named name.
+func (s *SukiLogger) startPeriodic(name string, interval time.Duration, emit func()) *periodicEmitter {
+	e := &periodicEmitter{logger: s, name: name, interval: interval, emit: emit}
+	e.mu.Lock()
+	e.schedule()
+	e.mu.Unlock()
+	return e
+}
+
+// schedule arms the next emission, e.mu must be held.
+func (e *periodicEmitter) schedule() {
+	if e.stopped || e.interval <= 0 {
+		return
+	}
+	e.timer = e.logger.clock().AfterFunc(e.interval, e.tick)
+}
+
+func (e *periodicEmitter) tick() {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return
+	}
+	e.mu.Unlock()
+
+	e.safeEmit()
+
+	e.mu.Lock()
+	e.schedule()
+	e.mu.Unlock()
+}
+
+func (e *periodicEmitter) safeEmit() {
+	defer func() {
+		if r := recover(); r != nil {
+			e.logger.internalError(fmt.Errorf("%s panicked: %v", e.name, r))
+		}
+	}()
+
+	e.emit()
+}
+
+func (e *periodicEmitter) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopped = true
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+}
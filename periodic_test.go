@@ -0,0 +1,67 @@
+package slog
+
+import (
+	"bytes"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodicEmitterRecoversPanic(t *testing.T) {
+	tests := []struct {
+		name  string
+		start func(s *SukiLogger, panics *bool) func()
+		want  string
+	}{
+		{
+			name: "Cache stats",
+			start: func(s *SukiLogger, panics *bool) func() {
+				return s.CacheStatsLog(time.Minute, func() CacheStats {
+					if *panics {
+						panic("cache gone")
+					}
+					return CacheStats{Name: "products"}
+				})
+			},
+			want: "cache stats panicked: cache gone",
+		},
+		{
+			name: "Pool stats",
+			start: func(s *SukiLogger, panics *bool) func() {
+				return s.PoolStatsLog(time.Minute, func() PoolStats {
+					if *panics {
+						panic("db closed")
+					}
+					return PoolStats{Name: "orders"}
+				})
+			},
+			want: "pool stats panicked: db closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			logger, buf := newTestLogger(c)
+			errorOutput := &bytes.Buffer{}
+			logger.errorOutput = errorOutput
+
+			panics := true
+			stop := tt.start(logger, &panics)
+			defer stop()
+
+			clock.Advance(time.Minute)
+			if !strings.Contains(errorOutput.String(), tt.want) {
+				t.Errorf("error output = %q, want %q", errorOutput.String(), tt.want)
+			}
+
+			panics = false
+			clock.Advance(time.Minute)
+			if lines := decodeLines(t, buf); len(lines) != 1 {
+				t.Errorf("%d logs, want the emission after the panic still written", len(lines))
+			}
+		})
+	}
+}
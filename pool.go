@@ -0,0 +1,54 @@
+package slog
+
+import (
+	"database/sql"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// PoolStats is the data.pool of a pool_stats log, the health of a DB or HTTP connection pool.
+type PoolStats struct {
+	Name  string `json:"name"`
+	Open  int    `json:"open"`
+	Idle  int    `json:"idle"`
+	InUse int    `json:"in_use"`
+	// WaitCount is the number of connections waited for since the pool was created.
+	WaitCount int64 `json:"wait_count"`
+	// WaitDurationMs is the total time spent waiting for a connection since the pool was created.
+	WaitDurationMs float64 `json:"wait_duration_ms"`
+}
+
+// PoolStatsFromDB returns the PoolStats of the sql.DBStats of a database, e.g. db.Stats().
+func PoolStatsFromDB(name string, stats sql.DBStats) PoolStats {
+	return PoolStats{
+		Name:           name,
+		Open:           stats.OpenConnections,
+		Idle:           stats.Idle,
+		InUse:          stats.InUse,
+		WaitCount:      stats.WaitCount,
+		WaitDurationMs: toMillis(stats.WaitDuration),
+	}
+}
+
+// PoolStatsLog writes a pool_stats log of the stats returned by stats every
+// interval until the returned stop func is called, e.g.
+//
+//	stop := slog.L().PoolStatsLog(time.Minute, func() slog.PoolStats {
+//		return slog.PoolStatsFromDB("orders", db.Stats())
+//	})
+//	defer stop()
+func (s *SukiLogger) PoolStatsLog(interval time.Duration, stats func() PoolStats, args ...interface{}) (stop func()) {
+	e := s.startPeriodic("pool stats", interval, func() { s.writePoolStats(stats(), args) })
+	return e.stop
+}
+
+func (s SukiLogger) writePoolStats(stats PoolStats, args []interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["pool"] = stats
+
+	if ce := s.sampledLogger("pool_stats", alertLevel, zapcore.InfoLevel, "pool stats", data).Check(zapcore.InfoLevel, "pool stats"); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
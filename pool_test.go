@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"database/sql"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPoolStatsLog(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	var waits int64
+	stop := logger.PoolStatsLog(time.Minute, func() PoolStats {
+		waits += 2
+		return PoolStats{Name: "orders", Open: 10, Idle: 3, InUse: 7, WaitCount: waits, WaitDurationMs: 12.5}
+	}, WithTracing("trace-1", "span-1"))
+
+	clock.Advance(59 * time.Second)
+	if lines := decodeLines(t, buf); len(lines) != 0 {
+		t.Fatalf("%d logs before the first interval, want 0", len(lines))
+	}
+
+	clock.Advance(time.Minute + time.Second)
+	stop()
+	clock.Advance(time.Hour)
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("%d logs, want 2", len(lines))
+	}
+	for i, line := range lines {
+		if line["log_type"] != "pool_stats" || line["level"] != "info" || line["message"] != "pool stats" {
+			t.Errorf("log_type, level, message = %v, %v, %v, want pool_stats, info, pool stats", line["log_type"], line["level"], line["message"])
+		}
+
+		data := line["data"].(map[string]interface{})
+		want := map[string]interface{}{
+			"name":             "orders",
+			"open":             float64(10),
+			"idle":             float64(3),
+			"in_use":           float64(7),
+			"wait_count":       float64(2 * (i + 1)),
+			"wait_duration_ms": 12.5,
+		}
+		if !reflect.DeepEqual(data["pool"], want) {
+			t.Errorf("pool = %v, want %v", data["pool"], want)
+		}
+		if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+			t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+		}
+	}
+}
+
+func TestPoolStatsLogStopBeforeFirst(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	calls := 0
+	stop := logger.PoolStatsLog(time.Minute, func() PoolStats {
+		calls++
+		return PoolStats{}
+	})
+	stop()
+	clock.Advance(time.Hour)
+
+	if calls != 0 || buf.Len() != 0 || len(clock.Pending()) != 0 {
+		t.Errorf("stats called %d times and %q written after stop, want none", calls, buf)
+	}
+}
+
+func TestPoolStatsFromDB(t *testing.T) {
+	got := PoolStatsFromDB("orders", sql.DBStats{OpenConnections: 5, Idle: 2, InUse: 3, WaitCount: 4, WaitDuration: 1500 * time.Microsecond})
+	want := PoolStats{Name: "orders", Open: 5, Idle: 2, InUse: 3, WaitCount: 4, WaitDurationMs: 1.5}
+	if got != want {
+		t.Errorf("PoolStatsFromDB() = %+v, want %+v", got, want)
+	}
+}
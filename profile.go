@@ -0,0 +1,36 @@
+package slog
+
+const (
+	ProfileLocal      = "local"
+	ProfileStaging    = "staging"
+	ProfileProduction = "production"
+)
+
+// NewConfigForProfile returns the config of a deployment profile, built on
+// NewProductionConfig, whose fields can be overridden afterward:
+//
+//   - local: debug level, no sampling, bodies up to 1 MiB, no pattern redaction.
+//   - staging: debug level, sampling, bodies up to 1 MiB, emails, phone numbers
+//     and card numbers redacted.
+//   - production: info level, sampling, bodies up to 64 KiB only written for
+//     warn logs and above, emails, phone numbers and card numbers redacted.
+//
+// An unknown profile gets the production config.
+func NewConfigForProfile(profile string) Config {
+	config := NewProductionConfig()
+
+	switch profile {
+	case ProfileLocal:
+		config.LogLevel = LevelDebug
+		config.DisableSampling = true
+	case ProfileStaging:
+		config.LogLevel = LevelDebug
+		config.RedactPatterns = RedactPatterns{Email: true, Phone: true, Card: true}
+	default:
+		config.LogLevel = LevelInfo
+		config.MaxBodySize = 65536
+		config.BodyLevel = LevelWarn
+		config.RedactPatterns = RedactPatterns{Email: true, Phone: true, Card: true}
+	}
+	return config
+}
@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestNewConfigForProfile(t *testing.T) {
+	redactAll := RedactPatterns{Email: true, Phone: true, Card: true}
+	tests := []struct {
+		name            string
+		profile         string
+		level           LogLevel
+		disableSampling bool
+		maxBodySize     int
+		bodyLevel       LogLevel
+		redact          RedactPatterns
+	}{
+		{name: "local", profile: ProfileLocal, level: LevelDebug, disableSampling: true, maxBodySize: 1048576, bodyLevel: LevelInfo},
+		{name: "staging", profile: ProfileStaging, level: LevelDebug, maxBodySize: 1048576, bodyLevel: LevelInfo, redact: redactAll},
+		{name: "production", profile: ProfileProduction, level: LevelInfo, maxBodySize: 65536, bodyLevel: LevelWarn, redact: redactAll},
+		{name: "unknown falls back to production", profile: "qa", level: LevelInfo, maxBodySize: 65536, bodyLevel: LevelWarn, redact: redactAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigForProfile(tt.profile)
+			if c.LogLevel != tt.level {
+				t.Errorf("LogLevel = %v, want %v", c.LogLevel, tt.level)
+			}
+			if c.DisableSampling != tt.disableSampling {
+				t.Errorf("DisableSampling = %v, want %v", c.DisableSampling, tt.disableSampling)
+			}
+			if c.MaxBodySize != tt.maxBodySize {
+				t.Errorf("MaxBodySize = %v, want %v", c.MaxBodySize, tt.maxBodySize)
+			}
+			if c.BodyLevel != tt.bodyLevel {
+				t.Errorf("BodyLevel = %v, want %v", c.BodyLevel, tt.bodyLevel)
+			}
+			if c.RedactPatterns != tt.redact {
+				t.Errorf("RedactPatterns = %+v, want %+v", c.RedactPatterns, tt.redact)
+			}
+			if c.AppName != "application" {
+				t.Errorf("AppName = %q, want the NewProductionConfig default", c.AppName)
+			}
+		})
+	}
+}
+
+func TestNewConfigForProfileOverride(t *testing.T) {
+	c := NewConfigForProfile(ProfileProduction)
+	c.LogLevel = LevelDebug
+	c.AppName = "order-service"
+
+	logger, buf := newTestLogger(c)
+	logger.Debug("hello")
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want the debug log of the overridden level", len(lines))
+	}
+	if lines[0]["app_name"] != "order-service" {
+		t.Errorf("app_name = %v, want order-service", lines[0]["app_name"])
+	}
+}
@@ -0,0 +1,17 @@
+package slog
+
+import (
+	"net/url"
+)
+
+// parseQueryValues returns every value of the params of rawQuery, in the order
+// they were given, with the credentials of their URLs masked.
+func parseQueryValues(rawQuery string) map[string][]string {
+	values, _ := url.ParseQuery(rawQuery)
+	for _, v := range values {
+		for i := range v {
+			v[i] = redactUserinfo(v[i])
+		}
+	}
+	return values
+}
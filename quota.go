@@ -0,0 +1,173 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuotaHeaders names the response headers the quota of an API is read from.
+type QuotaHeaders struct {
+	Limit      string
+	Remaining  string
+	Reset      string
+	RetryAfter string
+}
+
+// DefaultQuotaHeaders are the rate limit headers used by most APIs.
+var DefaultQuotaHeaders = QuotaHeaders{
+	Limit:      "X-RateLimit-Limit",
+	Remaining:  "X-RateLimit-Remaining",
+	Reset:      "X-RateLimit-Reset",
+	RetryAfter: "Retry-After",
+}
+
+// QuotaThreshold is the remaining quota below which a client.http log is written
+// at warn level, with Alert when set. Remaining is an absolute number of calls
+// and Percent a percentage of the limit, zero disables either.
+type QuotaThreshold struct {
+	Remaining int64
+	Percent   float64
+	Alert     AlertLevel
+}
+
+// QuotaOption configures the parsing of quota headers by RoundTripper.
+type QuotaOption struct {
+	// Headers defaults to DefaultQuotaHeaders, an empty header name is not read.
+	Headers *QuotaHeaders
+	// Thresholds maps a host to its threshold, the "" entry applies to any other host.
+	Thresholds map[string]QuotaThreshold
+}
+
+// QuotaInfo is the quota of an API as reported by the headers of its response,
+// fields whose header is missing or malformed are omitted.
+type QuotaInfo struct {
+	Limit          *int64     `json:"limit,omitempty"`
+	Remaining      *int64     `json:"remaining,omitempty"`
+	ResetAt        *time.Time `json:"reset_at,omitempty"`
+	RetryAfterMs   *float64   `json:"retry_after_ms,omitempty"`
+	BelowThreshold bool       `json:"below_threshold,omitempty"`
+}
+
+// quota parses the quota headers of a response from host received at now and
+// returns it along with the threshold applied to host.
+func (o QuotaOption) quota(host string, header http.Header, now time.Time) (QuotaInfo, QuotaThreshold) {
+	headers := DefaultQuotaHeaders
+	if o.Headers != nil {
+		headers = *o.Headers
+	}
+
+	var info QuotaInfo
+	if v, ok := parseQuotaCount(headerValue(header, headers.Limit)); ok {
+		info.Limit = &v
+	}
+	if v, ok := parseQuotaCount(headerValue(header, headers.Remaining)); ok {
+		info.Remaining = &v
+	}
+	if v, ok := parseQuotaReset(headerValue(header, headers.Reset), now); ok {
+		info.ResetAt = &v
+	}
+	if v, ok := parseRetryAfter(headerValue(header, headers.RetryAfter), now); ok {
+		info.RetryAfterMs = &v
+	}
+
+	threshold, ok := o.Thresholds[host]
+	if !ok {
+		threshold = o.Thresholds[""]
+	}
+	info.BelowThreshold = info.below(threshold)
+
+	return info, threshold
+}
+
+func (q QuotaInfo) below(threshold QuotaThreshold) bool {
+	if q.Remaining == nil {
+		return false
+	}
+	if threshold.Remaining > 0 && *q.Remaining < threshold.Remaining {
+		return true
+	}
+	if threshold.Percent > 0 && q.Limit != nil && *q.Limit > 0 {
+		return float64(*q.Remaining)*100/float64(*q.Limit) < threshold.Percent
+	}
+	return false
+}
+
+func headerValue(header http.Header, name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSpace(header.Get(name))
+}
+
+// parseQuotaCount reads the leading count of a header, tolerating the policy
+// suffixes of some APIs such as "100, 100;w=60".
+func parseQuotaCount(value string) (int64, bool) {
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || v < 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+// parseQuotaReset reads a reset time given as a unix timestamp in seconds or
+// milliseconds, as seconds from now or as an HTTP-date.
+func parseQuotaReset(value string, now time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if v, ok := parseQuotaCount(value); ok {
+		switch {
+		case v >= 1e12:
+			return time.UnixMilli(v).UTC(), true
+		case v >= 1e9:
+			return time.Unix(v, 0).UTC(), true
+		default:
+			return now.Add(time.Duration(v) * time.Second).UTC(), true
+		}
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// parseRetryAfter reads a Retry-After header given as delta-seconds or as an
+// HTTP-date, a date in the past is a retry after 0ms.
+func parseRetryAfter(value string, now time.Time) (float64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		if v < 0 {
+			return 0, false
+		}
+		return v * 1000, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return toMillis(d), true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// quotaArgs adds the QuotaInfo in args to data and raises level to warn when
+// it is below its threshold.
+func quotaArgs(level zapcore.Level, data map[string]interface{}, args []interface{}) zapcore.Level {
+	for i := range args {
+		if quota, ok := args[i].(QuotaInfo); ok {
+			data["quota"] = quota
+			if quota.BelowThreshold && level < zapcore.WarnLevel {
+				level = zapcore.WarnLevel
+			}
+		}
+	}
+	return level
+}
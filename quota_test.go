@@ -0,0 +1,136 @@
+package slog
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func timePtr(v time.Time) *time.Time {
+	return &v
+}
+
+func TestQuotaOptionQuota(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		option QuotaOption
+		host   string
+		header http.Header
+		want   QuotaInfo
+	}{
+		{
+			name:   "Missing headers",
+			header: http.Header{},
+			want:   QuotaInfo{},
+		},
+		{
+			name: "Limit and remaining",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"100"},
+				"X-Ratelimit-Remaining": {"42"},
+			},
+			want: QuotaInfo{Limit: int64Ptr(100), Remaining: int64Ptr(42)},
+		},
+		{
+			name: "Policy suffix",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"100, 100;w=60"},
+				"X-Ratelimit-Remaining": {"7;w=60"},
+			},
+			want: QuotaInfo{Limit: int64Ptr(100), Remaining: int64Ptr(7)},
+		},
+		{
+			name:   "Malformed remaining",
+			header: http.Header{"X-Ratelimit-Remaining": {"many"}},
+			want:   QuotaInfo{},
+		},
+		{
+			name:   "Reset as unix seconds",
+			header: http.Header{"X-Ratelimit-Reset": {"1709294460"}},
+			want:   QuotaInfo{ResetAt: timePtr(time.Unix(1709294460, 0).UTC())},
+		},
+		{
+			name:   "Reset as unix milliseconds",
+			header: http.Header{"X-Ratelimit-Reset": {"1709294460000"}},
+			want:   QuotaInfo{ResetAt: timePtr(time.Unix(1709294460, 0).UTC())},
+		},
+		{
+			name:   "Reset as delta seconds",
+			header: http.Header{"X-Ratelimit-Reset": {"30"}},
+			want:   QuotaInfo{ResetAt: timePtr(now.Add(30 * time.Second))},
+		},
+		{
+			name:   "Retry-After as delta seconds",
+			header: http.Header{"Retry-After": {"120"}},
+			want:   QuotaInfo{RetryAfterMs: float64Ptr(120000)},
+		},
+		{
+			name:   "Retry-After as HTTP-date",
+			header: http.Header{"Retry-After": {"Fri, 01 Mar 2024 12:00:05 GMT"}},
+			want:   QuotaInfo{RetryAfterMs: float64Ptr(5000)},
+		},
+		{
+			name:   "Retry-After in the past",
+			header: http.Header{"Retry-After": {"Fri, 01 Mar 2024 11:00:00 GMT"}},
+			want:   QuotaInfo{RetryAfterMs: float64Ptr(0)},
+		},
+		{
+			name:   "Custom headers",
+			option: QuotaOption{Headers: &QuotaHeaders{Remaining: "X-Shopee-Quota-Left"}},
+			header: http.Header{
+				"X-Shopee-Quota-Left":   {"3"},
+				"X-Ratelimit-Remaining": {"42"},
+			},
+			want: QuotaInfo{Remaining: int64Ptr(3)},
+		},
+		{
+			name:   "Below absolute threshold",
+			option: QuotaOption{Thresholds: map[string]QuotaThreshold{"": {Remaining: 10}}},
+			header: http.Header{"X-Ratelimit-Remaining": {"9"}},
+			want:   QuotaInfo{Remaining: int64Ptr(9), BelowThreshold: true},
+		},
+		{
+			name:   "Above absolute threshold",
+			option: QuotaOption{Thresholds: map[string]QuotaThreshold{"": {Remaining: 10}}},
+			header: http.Header{"X-Ratelimit-Remaining": {"10"}},
+			want:   QuotaInfo{Remaining: int64Ptr(10)},
+		},
+		{
+			name: "Below percentage threshold of host",
+			option: QuotaOption{Thresholds: map[string]QuotaThreshold{
+				"":                         {Remaining: 1},
+				"partner.shopeemobile.com": {Percent: 10},
+			}},
+			host: "partner.shopeemobile.com",
+			header: http.Header{
+				"X-Ratelimit-Limit":     {"1000"},
+				"X-Ratelimit-Remaining": {"50"},
+			},
+			want: QuotaInfo{Limit: int64Ptr(1000), Remaining: int64Ptr(50), BelowThreshold: true},
+		},
+		{
+			name:   "Percentage threshold without limit",
+			option: QuotaOption{Thresholds: map[string]QuotaThreshold{"": {Percent: 10}}},
+			header: http.Header{"X-Ratelimit-Remaining": {"1"}},
+			want:   QuotaInfo{Remaining: int64Ptr(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := tt.option.quota(tt.host, tt.header, now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("quota() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
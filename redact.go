@@ -0,0 +1,168 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactPatterns enables masking of personal data found in logged bodies and
+// payloads regardless of the field holding it. Every pattern is off by default
+// as scanning bodies has a cost.
+type RedactPatterns struct {
+	Email bool
+	Phone bool
+	// Card masks digit sequences passing the Luhn check, such as credit card numbers.
+	Card bool
+}
+
+const redactedValue = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\d(?:[ -]?\d){12,18}`)
+	// phonePattern only matches numbers shaped like phone numbers, with a leading
+	// + and country code or with separators between the groups of digits, so a
+	// long numeric ID such as an order number is not taken for one.
+	phonePattern = regexp.MustCompile(`\+\d(?:[ ().-]{0,2}\d){7,14}|(?:\(\d{2,4}\)[ .-]?|\d{2,4}[ .-])\d{3,4}[ .-]\d{3,4}`)
+)
+
+func (p RedactPatterns) enabled() bool {
+	return p.Email || p.Phone || p.Card
+}
+
+// redactBody masks the enabled patterns and the RedactionRules of route in body,
+// a JSON body is masked value by value.
+func (s SukiLogger) redactBody(body string, route string) string {
+	p := s.config.RedactPatterns
+	rules := s.config.RedactionRules.active(route)
+	if (!p.enabled() && rules.empty()) || body == "" {
+		return body
+	}
+
+	redactString := func(value string) string {
+		return rules.redactString(p.redactString(value))
+	}
+
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil && !dec.More() {
+			if b, err := marshalNoEscape(redactValue(v, redactString, rules.keys)); err == nil {
+				return string(b)
+			}
+		}
+	}
+
+	return redactString(body)
+}
+
+// redactValue masks the values of the sensitive keys of a JSON value and applies redactString to the others.
+func redactValue(v interface{}, redactString func(string) string, keys map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if keys[strings.ToLower(k)] {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = redactValue(child, redactString, keys)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, redactString, keys)
+		}
+	case string:
+		return redactString(v)
+	case json.Number:
+		if redacted := redactString(v.String()); redacted != v.String() {
+			return redacted
+		}
+	}
+	return v
+}
+
+func (p RedactPatterns) redactString(s string) string {
+	if p.Email {
+		s = emailPattern.ReplaceAllString(s, redactedValue)
+	}
+	if p.Card {
+		s = cardPattern.ReplaceAllStringFunc(s, func(match string) string {
+			if luhnValid(match) {
+				return redactedValue
+			}
+			return match
+		})
+	}
+	if p.Phone {
+		s = redactPhones(s)
+	}
+	return s
+}
+
+// redactPhones masks the phone numbers of s, leaving the matches of phonePattern
+// which are only part of a longer token, e.g. the first groups of 5555-5555-5555-4444.
+func redactPhones(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range phonePattern.FindAllStringIndex(s, -1) {
+		if inToken(s, m[0], m[1]) {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(redactedValue)
+		last = m[1]
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// inToken reports whether s[start:end] continues the word or the group of digits around it.
+func inToken(s string, start, end int) bool {
+	// Only ASCII letters continue a token, a phone number often directly follows Thai text.
+	tokenChar := func(i int) bool {
+		c := s[i]
+		return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+	separator := func(i int) bool {
+		return strings.IndexByte("-./", s[i]) >= 0
+	}
+	digit := func(i int) bool {
+		return s[i] >= '0' && s[i] <= '9'
+	}
+
+	if start > 0 && (tokenChar(start-1) || separator(start-1) && start > 1 && digit(start-2)) {
+		return true
+	}
+	return end < len(s) && (tokenChar(end) || separator(end) && end+1 < len(s) && digit(end+1))
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	var digits bytes.Buffer
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	sum := 0
+	b := digits.Bytes()
+	for i := len(b) - 1; i >= 0; i-- {
+		d := int(b[i] - '0')
+		if (len(b)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return len(b) > 0 && sum%10 == 0
+}
@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// redactPaths returns data with the values at RedactPaths masked. A path goes
+// through objects, the elements of arrays and the JSON documents held in
+// strings such as the HTTP bodies, e.g. "http_request.body.card.number".
+func (c Config) redactPaths(data map[string]interface{}) map[string]interface{} {
+	if len(c.RedactPaths) == 0 {
+		return data
+	}
+
+	redacted := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		redacted[k] = v
+	}
+	for _, path := range c.RedactPaths {
+		keys := strings.Split(path, ".")
+		value, ok := redacted[keys[0]]
+		if !ok {
+			continue
+		}
+		if len(keys) == 1 {
+			redacted[keys[0]] = redactedValue
+			continue
+		}
+
+		// Structs are walked as the JSON they are written as.
+		b, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		if doc, ok := decodeJSONValue(b); ok {
+			// Values without the path are kept as they are.
+			if doc, ok = redactPath(doc, keys[1:]); ok {
+				redacted[keys[0]] = doc
+			}
+		}
+	}
+	return redacted
+}
+
+// redactPath returns value with the value at the path of keys masked, and whether it was found.
+func redactPath(value interface{}, keys []string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		child, ok := v[keys[0]]
+		if !ok {
+			return v, false
+		}
+		if len(keys) == 1 {
+			v[keys[0]] = redactedValue
+			return v, true
+		}
+		v[keys[0]], ok = redactPath(child, keys[1:])
+		return v, ok
+	case []interface{}:
+		found := false
+		for i := range v {
+			var ok bool
+			v[i], ok = redactPath(v[i], keys)
+			found = found || ok
+		}
+		return v, found
+	case string:
+		doc, ok := decodeJSONValue([]byte(v))
+		if !ok {
+			return v, false
+		}
+		if doc, ok = redactPath(doc, keys); !ok {
+			return v, false
+		}
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return v, false
+		}
+		return string(b), true
+	}
+	return value, false
+}
+
+// decodeJSONValue decodes a JSON object or array keeping the numbers as written.
+func decodeJSONValue(b []byte) (interface{}, bool) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+	return doc, true
+}
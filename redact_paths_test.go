@@ -0,0 +1,88 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactPaths(t *testing.T) {
+	body := `{"card":{"number":"4242424242424242","brand":"visa"},"order":{"number":"SO-1"},"items":[{"sku":"A","price":10.50},{"sku":"B","price":3}]}`
+
+	tests := []struct {
+		name    string
+		paths   []string
+		request map[string]interface{}
+		body    string
+	}{
+		{
+			name:  "Nested path in a JSON body",
+			paths: []string{"http_request.body.card.number"},
+			body:  `{"card":{"brand":"visa","number":"[REDACTED]"},"items":[{"price":10.50,"sku":"A"},{"price":3,"sku":"B"}],"order":{"number":"SO-1"}}`,
+		},
+		{
+			name:  "Path through an array",
+			paths: []string{"http_request.body.items.price"},
+			body:  `{"card":{"brand":"visa","number":"4242424242424242"},"items":[{"price":"[REDACTED]","sku":"A"},{"price":"[REDACTED]","sku":"B"}],"order":{"number":"SO-1"}}`,
+		},
+		{
+			name:  "Path of a struct field",
+			paths: []string{"http_request.remote_ip"},
+			body:  body,
+		},
+		{
+			name:  "Missing path",
+			paths: []string{"http_request.body.customer.email", "kafka_message.payload"},
+			body:  body,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.RedactPaths = tt.paths
+			logger, buf := newTestLogger(c)
+
+			logger.RequestHTTP("http", WithHTTPRequest("POST", "/orders", "127.0.0.1", nil, nil, nil, body), WithHTTPResponse(200, 1, body))
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			request := data["http_request"].(map[string]interface{})
+			if request["body"] != tt.body {
+				t.Errorf("request body = %v, want %v", request["body"], tt.body)
+			}
+			wantIP := "127.0.0.1"
+			if tt.name == "Path of a struct field" {
+				wantIP = redactedValue
+			}
+			if request["remote_ip"] != wantIP {
+				t.Errorf("remote_ip = %v, want %v", request["remote_ip"], wantIP)
+			}
+			if response := data["http_response"].(map[string]interface{}); response["body"] != body {
+				t.Errorf("response body = %v, want it untouched", response["body"])
+			}
+		})
+	}
+}
+
+func TestRedactPathsApplicationFields(t *testing.T) {
+	c := NewProductionConfig()
+	c.AppName = "shop"
+	c.RedactPaths = []string{"shop.customer.password", "tracing"}
+	logger, buf := newTestLogger(c)
+
+	logger.Info("signup",
+		Any("customer", map[string]interface{}{"name": "Somchai", "password": "hunter2"}),
+		Any("password", "kept"),
+		WithTracing("trace", "span"),
+	)
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	want := map[string]interface{}{
+		"customer": map[string]interface{}{"name": "Somchai", "password": "[REDACTED]"},
+		"password": "kept",
+	}
+	if !reflect.DeepEqual(data["shop"], want) {
+		t.Errorf("shop = %v, want %v", data["shop"], want)
+	}
+	if data["tracing"] != redactedValue {
+		t.Errorf("tracing = %v, want %v", data["tracing"], redactedValue)
+	}
+}
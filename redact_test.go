@@ -0,0 +1,149 @@
+package slog
+
+import (
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	all := RedactPatterns{Email: true, Phone: true, Card: true}
+
+	tests := []struct {
+		name     string
+		patterns RedactPatterns
+		body     string
+		want     string
+	}{
+		{
+			name:     "Disabled by default",
+			patterns: RedactPatterns{},
+			body:     `{"email":"john@example.com"}`,
+			want:     `{"email":"john@example.com"}`,
+		},
+		{
+			name:     "Email in JSON value",
+			patterns: RedactPatterns{Email: true},
+			body:     `{"contact":"mail john.doe+shop@example.co.th please","phone":"0812345678"}`,
+			want:     `{"contact":"mail [REDACTED] please","phone":"0812345678"}`,
+		},
+		{
+			name:     "Phone in nested JSON",
+			patterns: RedactPatterns{Phone: true},
+			body:     `{"customer":{"phones":["+66 81 234 5678","081-234-5678"]},"qty":2}`,
+			want:     `{"customer":{"phones":["[REDACTED]","[REDACTED]"]},"qty":2}`,
+		},
+		{
+			name:     "Card number passing Luhn",
+			patterns: RedactPatterns{Card: true},
+			body:     `{"card":"4111 1111 1111 1111","ref":"4111 1111 1111 1112"}`,
+			want:     `{"card":"[REDACTED]","ref":"4111 1111 1111 1112"}`,
+		},
+		{
+			name:     "Card number as JSON number",
+			patterns: RedactPatterns{Card: true},
+			body:     `{"card":4242424242424242,"amount":1500.50}`,
+			want:     `{"amount":1500.50,"card":"[REDACTED]"}`,
+		},
+		{
+			name:     "Plain text body",
+			patterns: all,
+			body:     "from jane@example.com card 5555-5555-5555-4444 tel 081-234-5678.",
+			want:     "from [REDACTED] card [REDACTED] tel [REDACTED].",
+		},
+		{
+			name:     "Invalid JSON falls back to text",
+			patterns: all,
+			body:     `{"email":"jane@example.com"`,
+			want:     `{"email":"[REDACTED]"`,
+		},
+		{
+			name:     "Short numbers are kept",
+			patterns: all,
+			body:     `{"order_id":12345,"zip":"10110"}`,
+			want:     `{"order_id":12345,"zip":"10110"}`,
+		},
+		{
+			name:     "Phone formats",
+			patterns: RedactPatterns{Phone: true},
+			body:     "+66812345678, (02) 123 4567, +1 (555) 123-4567, 02.123.4567, โทร081-234-5678",
+			want:     "[REDACTED], [REDACTED], [REDACTED], [REDACTED], โทร[REDACTED]",
+		},
+		{
+			name:     "Long numeric IDs are not phones",
+			patterns: RedactPatterns{Phone: true},
+			body:     `{"order_id":98765432101,"ref":"20240301123456","tracking":"TH1234567890123","invoice":"INV-123456789"}`,
+			want:     `{"invoice":"INV-123456789","order_id":98765432101,"ref":"20240301123456","tracking":"TH1234567890123"}`,
+		},
+		{
+			name:     "Grouped digits longer than a phone are kept",
+			patterns: RedactPatterns{Phone: true},
+			body:     "card 5555-5555-5555-4444 serial 1234-5678-9012-3456 id 123456789012",
+			want:     "card 5555-5555-5555-4444 serial 1234-5678-9012-3456 id 123456789012",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := SukiLogger{config: Config{RedactPatterns: tt.patterns}}
+			if got := s.redactBody(tt.body, ""); got != tt.want {
+				t.Errorf("redactBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"4111111111111111", true},
+		{"4111-1111-1111-1111", true},
+		{"4111111111111112", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			if got := luhnValid(tt.digits); got != tt.want {
+				t.Errorf("luhnValid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestHTTPRedactsBodies(t *testing.T) {
+	c := NewProductionConfig()
+	c.RedactPatterns = RedactPatterns{Email: true, Card: true}
+	logger, buf := newTestLogger(c)
+
+	logger.RequestHTTP(
+		"checkout",
+		WithHTTPRequest("POST", "/checkout", "", nil, nil, nil, `{"email":"jane@example.com","card":"4111111111111111"}`),
+		WithHTTPResponse(200, 10, `{"receipt_to":"jane@example.com"}`),
+	)
+
+	data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+	request := data["http_request"].(map[string]interface{})
+	response := data["http_response"].(map[string]interface{})
+	if request["body"] != `{"card":"[REDACTED]","email":"[REDACTED]"}` {
+		t.Errorf("request body = %v", request["body"])
+	}
+	if response["body"] != `{"receipt_to":"[REDACTED]"}` {
+		t.Errorf("response body = %v", response["body"])
+	}
+}
+
+func TestRequestKafkaRedactsPayload(t *testing.T) {
+	c := NewProductionConfig()
+	c.RedactPatterns = RedactPatterns{Phone: true}
+	logger, buf := newTestLogger(c)
+
+	logger.RequestKafka(
+		"consume",
+		KafkaMessage{Topic: "customers", Payload: `{"phone":"081-234-5678"}`},
+		KafkaResult{},
+	)
+
+	message := decodeLines(t, buf)[0]["data"].(map[string]interface{})["kafka_message"].(map[string]interface{})
+	if message["payload"] != `{"phone":"[REDACTED]"}` {
+		t.Errorf("payload = %v", message["payload"])
+	}
+}
@@ -0,0 +1,479 @@
+package slog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RedactionRulesVersion is the version of the redaction rules document read by LoadRedactionRules.
+const RedactionRulesVersion = 1
+
+var ErrRedactionRuleRemoval = errors.New("slog: redaction rules do not allow removing rules")
+
+// RedactionRules is a set of redaction rules maintained centrally and loaded
+// with LoadRedactionRules, see Config.RedactionRules.
+type RedactionRules struct {
+	Version int `json:"version"`
+	// SensitiveKeys are the JSON keys whose values are masked in bodies, case-insensitively.
+	SensitiveKeys []string              `json:"sensitive_keys,omitempty"`
+	Headers       []HeaderRule          `json:"headers,omitempty"`
+	Patterns      []PatternRule         `json:"patterns,omitempty"`
+	Routes        map[string]RouteRules `json:"routes,omitempty"`
+	// AllowRemoval lets Merge apply the Remove of an override.
+	AllowRemoval bool `json:"allow_removal,omitempty"`
+	// Remove lists the sensitive keys, header names and pattern names an
+	// override removes from the rules it is merged into.
+	Remove []string `json:"remove,omitempty"`
+
+	compiled *compiledRules
+}
+
+// RouteRules are the rules added for the requests of a single route.
+type RouteRules struct {
+	SensitiveKeys []string      `json:"sensitive_keys,omitempty"`
+	Headers       []HeaderRule  `json:"headers,omitempty"`
+	Patterns      []PatternRule `json:"patterns,omitempty"`
+}
+
+type HeaderAction string
+
+const (
+	HeaderMask HeaderAction = "mask"
+	HeaderDrop HeaderAction = "drop"
+)
+
+// HeaderRule masks or drops a request header or response trailer.
+type HeaderRule struct {
+	Name   string       `json:"name"`
+	Action HeaderAction `json:"action"`
+}
+
+// PatternRule masks the matches of a regular expression in bodies, Flags are
+// the flags of the expression among i, m, s and U.
+type PatternRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Flags   string `json:"flags,omitempty"`
+}
+
+// RedactionRuleError is an invalid rule, Line and Column locate it in the
+// document it was loaded from.
+type RedactionRuleError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *RedactionRuleError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("slog: redaction rules: line %d, column %d: %s: %v", e.Line, e.Column, e.Path, e.Err)
+	}
+	return fmt.Sprintf("slog: redaction rules: %s: %v", e.Path, e.Err)
+}
+
+func (e *RedactionRuleError) Unwrap() error {
+	return e.Err
+}
+
+// LoadRedactionRules reads and validates a JSON redaction rules document.
+func LoadRedactionRules(r io.Reader) (RedactionRules, error) {
+	doc, err := io.ReadAll(r)
+	if err != nil {
+		return RedactionRules{}, err
+	}
+
+	var rules RedactionRules
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		e := &RedactionRuleError{Path: "document", Err: err}
+		var syntaxError *json.SyntaxError
+		var typeError *json.UnmarshalTypeError
+		if errors.As(err, &syntaxError) {
+			// Offset is past the invalid character.
+			e.Line, e.Column = position(doc, syntaxError.Offset-1)
+		} else if errors.As(err, &typeError) {
+			e.Path = typeError.Field
+			e.Line, e.Column = position(doc, typeError.Offset)
+		}
+		return RedactionRules{}, e
+	}
+
+	if rules.Version != RedactionRulesVersion {
+		return RedactionRules{}, &RedactionRuleError{Path: "version", Err: fmt.Errorf("unsupported version %d", rules.Version)}
+	}
+
+	if err := rules.compile(); err != nil {
+		var e *RedactionRuleError
+		if errors.As(err, &e) {
+			if offset, ok := stringOffsets(doc)[e.Path]; ok {
+				e.Line, e.Column = position(doc, offset)
+			}
+		}
+		return RedactionRules{}, err
+	}
+	return rules, nil
+}
+
+// Merge returns the rules with the sensitive keys, headers, patterns and routes
+// of override added. The entries listed in the Remove of override are removed
+// when the rules AllowRemoval, Merge returns ErrRedactionRuleRemoval otherwise.
+// A header or pattern of override replaces the one of the same name only when
+// the rules AllowRemoval too, as it could weaken it: otherwise a header may only
+// go from mask to drop, and a pattern of an existing name must be the same,
+// Merge returns ErrRedactionRuleRemoval for any other change.
+func (r RedactionRules) Merge(override RedactionRules) (RedactionRules, error) {
+	if len(override.Remove) > 0 && !r.AllowRemoval {
+		return RedactionRules{}, ErrRedactionRuleRemoval
+	}
+
+	merged := RedactionRules{
+		Version:      r.Version,
+		AllowRemoval: r.AllowRemoval,
+		Routes:       make(map[string]RouteRules),
+	}
+
+	base := RouteRules{SensitiveKeys: r.SensitiveKeys, Headers: r.Headers, Patterns: r.Patterns}
+	add := RouteRules{SensitiveKeys: override.SensitiveKeys, Headers: override.Headers, Patterns: override.Patterns}
+	global, err := base.merge(add, r.AllowRemoval)
+	if err != nil {
+		return RedactionRules{}, err
+	}
+	global = global.without(override.Remove)
+	merged.SensitiveKeys, merged.Headers, merged.Patterns = global.SensitiveKeys, global.Headers, global.Patterns
+
+	for route, rules := range r.Routes {
+		merged.Routes[route] = rules.without(override.Remove)
+	}
+	for route, rules := range override.Routes {
+		if merged.Routes[route], err = merged.Routes[route].merge(rules, r.AllowRemoval); err != nil {
+			return RedactionRules{}, err
+		}
+	}
+
+	if err := merged.compile(); err != nil {
+		return RedactionRules{}, err
+	}
+	return merged, nil
+}
+
+// merge adds the rules of override to r, a rule of override replacing the one of
+// the same name. Unless replace is set, a replacement weakening the rule of r
+// returns ErrRedactionRuleRemoval.
+func (r RouteRules) merge(override RouteRules, replace bool) (RouteRules, error) {
+	merged := RouteRules{
+		SensitiveKeys: append(append([]string{}, r.SensitiveKeys...), override.SensitiveKeys...),
+	}
+
+	headers := make(map[string]int)
+	for _, h := range append(append([]HeaderRule{}, r.Headers...), override.Headers...) {
+		name := http.CanonicalHeaderKey(h.Name)
+		if i, ok := headers[name]; ok {
+			if !replace && merged.Headers[i].Action == HeaderDrop && h.Action != HeaderDrop {
+				return RouteRules{}, ErrRedactionRuleRemoval
+			}
+			merged.Headers[i] = h
+			continue
+		}
+		headers[name] = len(merged.Headers)
+		merged.Headers = append(merged.Headers, h)
+	}
+
+	patterns := make(map[string]int)
+	for _, p := range append(append([]PatternRule{}, r.Patterns...), override.Patterns...) {
+		if i, ok := patterns[p.Name]; ok {
+			if !replace && merged.Patterns[i] != p {
+				return RouteRules{}, ErrRedactionRuleRemoval
+			}
+			merged.Patterns[i] = p
+			continue
+		}
+		patterns[p.Name] = len(merged.Patterns)
+		merged.Patterns = append(merged.Patterns, p)
+	}
+
+	return merged, nil
+}
+
+func (r RouteRules) without(names []string) RouteRules {
+	if len(names) == 0 {
+		return r
+	}
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[strings.ToLower(name)] = true
+	}
+
+	result := RouteRules{}
+	for _, key := range r.SensitiveKeys {
+		if !remove[strings.ToLower(key)] {
+			result.SensitiveKeys = append(result.SensitiveKeys, key)
+		}
+	}
+	for _, h := range r.Headers {
+		if !remove[strings.ToLower(h.Name)] {
+			result.Headers = append(result.Headers, h)
+		}
+	}
+	for _, p := range r.Patterns {
+		if !remove[strings.ToLower(p.Name)] {
+			result.Patterns = append(result.Patterns, p)
+		}
+	}
+	return result
+}
+
+// Fingerprint identifies the rules regardless of the order and formatting of
+// the document they were loaded from.
+func (r RedactionRules) Fingerprint() string {
+	canonical := struct {
+		Version      int                   `json:"version"`
+		Rules        RouteRules            `json:"rules"`
+		Routes       map[string]RouteRules `json:"routes"`
+		AllowRemoval bool                  `json:"allow_removal"`
+	}{
+		Version:      r.Version,
+		Rules:        RouteRules{SensitiveKeys: r.SensitiveKeys, Headers: r.Headers, Patterns: r.Patterns}.canonical(),
+		Routes:       make(map[string]RouteRules, len(r.Routes)),
+		AllowRemoval: r.AllowRemoval,
+	}
+	for route, rules := range r.Routes {
+		canonical.Routes[route] = rules.canonical()
+	}
+
+	b, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func (r RouteRules) canonical() RouteRules {
+	result := RouteRules{}
+	for _, key := range r.SensitiveKeys {
+		result.SensitiveKeys = append(result.SensitiveKeys, strings.ToLower(key))
+	}
+	sort.Strings(result.SensitiveKeys)
+
+	for _, h := range r.Headers {
+		result.Headers = append(result.Headers, HeaderRule{Name: http.CanonicalHeaderKey(h.Name), Action: h.Action})
+	}
+	sort.Slice(result.Headers, func(i, j int) bool {
+		return result.Headers[i].Name < result.Headers[j].Name
+	})
+
+	result.Patterns = append(result.Patterns, r.Patterns...)
+	sort.Slice(result.Patterns, func(i, j int) bool {
+		return result.Patterns[i].Name < result.Patterns[j].Name
+	})
+	return result
+}
+
+// compiledRules are the rules applying to requests of any route, and to each route.
+type compiledRules struct {
+	global activeRules
+	routes map[string]activeRules
+}
+
+type activeRules struct {
+	keys     map[string]bool
+	headers  map[string]HeaderAction
+	patterns []*regexp.Regexp
+}
+
+// compile validates the rules and prepares them to be applied.
+func (r *RedactionRules) compile() error {
+	global, err := RouteRules{SensitiveKeys: r.SensitiveKeys, Headers: r.Headers, Patterns: r.Patterns}.compile("", activeRules{})
+	if err != nil {
+		return err
+	}
+
+	compiled := &compiledRules{global: global, routes: make(map[string]activeRules, len(r.Routes))}
+
+	routes := make([]string, 0, len(r.Routes))
+	for route := range r.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		active, err := r.Routes[route].compile("routes["+strconv.Quote(route)+"].", global)
+		if err != nil {
+			return err
+		}
+		compiled.routes[route] = active
+	}
+
+	r.compiled = compiled
+	return nil
+}
+
+// compile returns base with the rules of r added, path prefixes the paths of errors.
+func (r RouteRules) compile(path string, base activeRules) (activeRules, error) {
+	active := activeRules{
+		keys:     make(map[string]bool, len(base.keys)+len(r.SensitiveKeys)),
+		headers:  make(map[string]HeaderAction, len(base.headers)+len(r.Headers)),
+		patterns: append([]*regexp.Regexp{}, base.patterns...),
+	}
+	for k := range base.keys {
+		active.keys[k] = true
+	}
+	for k, v := range base.headers {
+		active.headers[k] = v
+	}
+
+	for _, key := range r.SensitiveKeys {
+		active.keys[strings.ToLower(key)] = true
+	}
+
+	for i, h := range r.Headers {
+		if h.Action != HeaderMask && h.Action != HeaderDrop {
+			return activeRules{}, &RedactionRuleError{
+				Path: fmt.Sprintf("%sheaders[%d].action", path, i),
+				Err:  fmt.Errorf("unknown action %q, want %q or %q", h.Action, HeaderMask, HeaderDrop),
+			}
+		}
+		active.headers[http.CanonicalHeaderKey(h.Name)] = h.Action
+	}
+
+	for i, p := range r.Patterns {
+		for _, flag := range p.Flags {
+			if !strings.ContainsRune("imsU", flag) {
+				return activeRules{}, &RedactionRuleError{
+					Path: fmt.Sprintf("%spatterns[%d].flags", path, i),
+					Err:  fmt.Errorf("unknown flag %q", flag),
+				}
+			}
+		}
+
+		expr := p.Pattern
+		if p.Flags != "" {
+			expr = "(?" + p.Flags + ")" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return activeRules{}, &RedactionRuleError{Path: fmt.Sprintf("%spatterns[%d].pattern", path, i), Err: err}
+		}
+		active.patterns = append(active.patterns, re)
+	}
+
+	return active, nil
+}
+
+// active returns the rules applying to route.
+func (r RedactionRules) active(route string) activeRules {
+	if r.compiled == nil {
+		return activeRules{}
+	}
+	if active, ok := r.compiled.routes[route]; ok {
+		return active
+	}
+	return r.compiled.global
+}
+
+func (a activeRules) empty() bool {
+	return len(a.keys) == 0 && len(a.headers) == 0 && len(a.patterns) == 0
+}
+
+func (a activeRules) redactString(s string) string {
+	for _, re := range a.patterns {
+		s = re.ReplaceAllString(s, redactedValue)
+	}
+	return s
+}
+
+// redactHeaders returns a copy of headers with the header rules applied.
+func (a activeRules) redactHeaders(headers map[string]string) map[string]string {
+	if len(a.headers) == 0 || len(headers) == 0 {
+		return headers
+	}
+
+	result := make(map[string]string, len(headers))
+	for k, v := range headers {
+		switch a.headers[http.CanonicalHeaderKey(k)] {
+		case HeaderDrop:
+		case HeaderMask:
+			result[k] = redactedValue
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// stringOffsets returns the offset of every string value of a JSON document by
+// its path, written as patterns[0].pattern or routes["/orders"].headers[1].name.
+func stringOffsets(doc []byte) map[string]int64 {
+	offsets := make(map[string]int64)
+	dec := json.NewDecoder(bytes.NewReader(doc))
+
+	var walk func(path string) bool
+	walk = func(path string) bool {
+		start := dec.InputOffset()
+		token, err := dec.Token()
+		if err != nil {
+			return false
+		}
+
+		switch t := token.(type) {
+		case json.Delim:
+			switch t {
+			case '{':
+				for dec.More() {
+					key, err := dec.Token()
+					if err != nil {
+						return false
+					}
+					name := key.(string)
+					child := path + "." + name
+					if path == "" {
+						child = name
+					} else if strings.HasSuffix(path, "routes") {
+						child = path + "[" + strconv.Quote(name) + "]"
+					}
+					if !walk(child) {
+						return false
+					}
+				}
+			case '[':
+				for i := 0; dec.More(); i++ {
+					if !walk(fmt.Sprintf("%s[%d]", path, i)) {
+						return false
+					}
+				}
+			}
+			_, err := dec.Token()
+			return err == nil
+		case string:
+			// start may precede the separator and spaces before the value.
+			offset := start + int64(bytes.IndexByte(doc[start:], '"'))
+			offsets[path] = offset
+		}
+		return true
+	}
+	walk("")
+
+	return offsets
+}
+
+// position returns the 1-based line and column of offset in doc.
+func position(doc []byte, offset int64) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(doc)) {
+		offset = int64(len(doc))
+	}
+	before := doc[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
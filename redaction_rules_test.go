@@ -0,0 +1,257 @@
+package slog
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const centralRules = `{
+  "version": 1,
+  "sensitive_keys": ["password", "citizen_id"],
+  "headers": [
+    {"name": "Authorization", "action": "mask"},
+    {"name": "Cookie", "action": "drop"}
+  ],
+  "patterns": [
+    {"name": "api_key", "pattern": "sk_live_[a-z0-9]+", "flags": "i"}
+  ],
+  "routes": {
+    "/payments": {"sensitive_keys": ["cvv"]}
+  }
+}`
+
+func mustLoadRules(t *testing.T, doc string) RedactionRules {
+	t.Helper()
+	rules, err := LoadRedactionRules(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+func TestLoadRedactionRules(t *testing.T) {
+	rules := mustLoadRules(t, centralRules)
+
+	want := RedactionRules{
+		Version:       1,
+		SensitiveKeys: []string{"password", "citizen_id"},
+		Headers:       []HeaderRule{{Name: "Authorization", Action: HeaderMask}, {Name: "Cookie", Action: HeaderDrop}},
+		Patterns:      []PatternRule{{Name: "api_key", Pattern: "sk_live_[a-z0-9]+", Flags: "i"}},
+		Routes:        map[string]RouteRules{"/payments": {SensitiveKeys: []string{"cvv"}}},
+	}
+	rules.compiled = nil
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("LoadRedactionRules() = %+v, want %+v", rules, want)
+	}
+}
+
+func TestLoadRedactionRulesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		wantPath   string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "Invalid regex",
+			doc:        "{\n  \"version\": 1,\n  \"patterns\": [\n    {\"name\": \"ok\", \"pattern\": \"a+\"},\n    {\"name\": \"bad\", \"pattern\": \"(\\\\d+\"}\n  ]\n}",
+			wantPath:   "patterns[1].pattern",
+			wantLine:   5,
+			wantColumn: 32,
+		},
+		{
+			name:       "Invalid regex of a route",
+			doc:        "{\"version\": 1, \"routes\": {\"/orders\": {\"patterns\": [{\"name\": \"bad\", \"pattern\": \"[a-\"}]}}}",
+			wantPath:   `routes["/orders"].patterns[0].pattern`,
+			wantLine:   1,
+			wantColumn: 79,
+		},
+		{
+			name:       "Unknown flag",
+			doc:        "{\"version\": 1, \"patterns\": [{\"name\": \"x\", \"pattern\": \"x\", \"flags\": \"q\"}]}",
+			wantPath:   "patterns[0].flags",
+			wantLine:   1,
+			wantColumn: 68,
+		},
+		{
+			name:       "Unknown header action",
+			doc:        `{"version": 1, "headers": [{"name": "Cookie", "action": "hide"}]}`,
+			wantPath:   "headers[0].action",
+			wantLine:   1,
+			wantColumn: 57,
+		},
+		{
+			name:     "Unsupported version",
+			doc:      `{"version": 2}`,
+			wantPath: "version",
+		},
+		{
+			name:       "Syntax error",
+			doc:        "{\n  \"version\": 1,,\n}",
+			wantPath:   "document",
+			wantLine:   2,
+			wantColumn: 16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRedactionRules(strings.NewReader(tt.doc))
+
+			var e *RedactionRuleError
+			if !errors.As(err, &e) {
+				t.Fatalf("LoadRedactionRules() error = %v, want a RedactionRuleError", err)
+			}
+			if e.Path != tt.wantPath || e.Line != tt.wantLine || e.Column != tt.wantColumn {
+				t.Errorf("error at %v line %d column %d, want %v line %d column %d (%v)", e.Path, e.Line, e.Column, tt.wantPath, tt.wantLine, tt.wantColumn, err)
+			}
+		})
+	}
+}
+
+func TestRedactionRulesMerge(t *testing.T) {
+	central := mustLoadRules(t, centralRules)
+
+	t.Run("Add rules", func(t *testing.T) {
+		merged, err := central.Merge(RedactionRules{
+			SensitiveKeys: []string{"pin"},
+			Headers:       []HeaderRule{{Name: "authorization", Action: HeaderDrop}, {Name: "X-Api-Key", Action: HeaderMask}},
+			Routes:        map[string]RouteRules{"/payments": {SensitiveKeys: []string{"card_number"}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := []string{"password", "citizen_id", "pin"}; !reflect.DeepEqual(merged.SensitiveKeys, want) {
+			t.Errorf("SensitiveKeys = %v, want %v", merged.SensitiveKeys, want)
+		}
+		wantHeaders := []HeaderRule{{Name: "authorization", Action: HeaderDrop}, {Name: "Cookie", Action: HeaderDrop}, {Name: "X-Api-Key", Action: HeaderMask}}
+		if !reflect.DeepEqual(merged.Headers, wantHeaders) {
+			t.Errorf("Headers = %v, want %v", merged.Headers, wantHeaders)
+		}
+		if want := []string{"cvv", "card_number"}; !reflect.DeepEqual(merged.Routes["/payments"].SensitiveKeys, want) {
+			t.Errorf("route SensitiveKeys = %v, want %v", merged.Routes["/payments"].SensitiveKeys, want)
+		}
+	})
+
+	t.Run("Removal denied", func(t *testing.T) {
+		if _, err := central.Merge(RedactionRules{Remove: []string{"citizen_id"}}); !errors.Is(err, ErrRedactionRuleRemoval) {
+			t.Errorf("Merge() error = %v, want ErrRedactionRuleRemoval", err)
+		}
+	})
+
+	t.Run("Weakening replacements denied", func(t *testing.T) {
+		overrides := map[string]RedactionRules{
+			"pattern": {Patterns: []PatternRule{{Name: "api_key", Pattern: "x^"}}},
+			"header":  {Headers: []HeaderRule{{Name: "cookie", Action: HeaderMask}}},
+		}
+		for name, override := range overrides {
+			if _, err := central.Merge(override); !errors.Is(err, ErrRedactionRuleRemoval) {
+				t.Errorf("%s: Merge() error = %v, want ErrRedactionRuleRemoval", name, err)
+			}
+		}
+
+		withRoute, err := central.Merge(RedactionRules{Routes: map[string]RouteRules{"/payments": {Patterns: []PatternRule{{Name: "pan", Pattern: `\d{16}`}}}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := withRoute.Merge(RedactionRules{Routes: map[string]RouteRules{"/payments": {Patterns: []PatternRule{{Name: "pan", Pattern: "x^"}}}}}); !errors.Is(err, ErrRedactionRuleRemoval) {
+			t.Errorf("route pattern: Merge() error = %v, want ErrRedactionRuleRemoval", err)
+		}
+
+		if _, err := central.Merge(RedactionRules{Patterns: central.Patterns, Headers: []HeaderRule{{Name: "authorization", Action: HeaderDrop}}}); err != nil {
+			t.Errorf("Merge() of the same pattern and a stronger header = %v", err)
+		}
+	})
+
+	t.Run("Removal allowed", func(t *testing.T) {
+		permissive := central
+		permissive.AllowRemoval = true
+
+		merged, err := permissive.Merge(RedactionRules{Remove: []string{"citizen_id", "cookie", "api_key"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(merged.SensitiveKeys, []string{"password"}) || len(merged.Headers) != 1 || len(merged.Patterns) != 0 {
+			t.Errorf("Merge() = %+v, want the listed rules removed", merged)
+		}
+	})
+}
+
+func TestRedactionRulesFingerprint(t *testing.T) {
+	reordered := `{"routes": {"/payments": {"sensitive_keys": ["CVV"]}},
+		"patterns": [{"flags": "i", "pattern": "sk_live_[a-z0-9]+", "name": "api_key"}],
+		"headers": [{"name": "cookie", "action": "drop"}, {"name": "authorization", "action": "mask"}],
+		"sensitive_keys": ["citizen_id", "password"], "version": 1}`
+
+	central := mustLoadRules(t, centralRules)
+	if got, want := mustLoadRules(t, reordered).Fingerprint(), central.Fingerprint(); got != want {
+		t.Errorf("Fingerprint() = %v, want %v for the same rules in another order", got, want)
+	}
+
+	changed, err := central.Merge(RedactionRules{SensitiveKeys: []string{"pin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Fingerprint() == central.Fingerprint() {
+		t.Error("Fingerprint() did not change with the rules")
+	}
+	if !strings.HasPrefix(central.Fingerprint(), "sha256:") {
+		t.Errorf("Fingerprint() = %v, want a sha256 digest", central.Fingerprint())
+	}
+}
+
+func TestRequestHTTPRedactionRules(t *testing.T) {
+	c := NewProductionConfig()
+	c.RedactionRules = mustLoadRules(t, centralRules)
+	logger, buf := newTestLogger(c)
+
+	logger.RequestHTTP(
+		"payment",
+		WithHTTPRequest(
+			"POST",
+			"/payments",
+			"",
+			map[string]string{"Authorization": "Bearer secret", "Cookie": "session=1", "Accept": "*/*"},
+			nil,
+			nil,
+			`{"card":{"cvv":"123"},"note":"key SK_LIVE_abc123","Password":"p"}`,
+		),
+		WithHTTPResponse(200, 1, `{"cvv":"123"}`),
+	)
+
+	lines := decodeLines(t, buf)
+	if lines[0]["message"] != "redaction rules loaded" {
+		t.Errorf("first log = %v, want the redaction rules startup log", lines[0]["message"])
+	}
+	if fingerprint := lines[0]["data"].(map[string]interface{})["application"].(map[string]interface{})["redaction_rules_fingerprint"]; fingerprint != c.RedactionRules.Fingerprint() {
+		t.Errorf("fingerprint = %v, want %v", fingerprint, c.RedactionRules.Fingerprint())
+	}
+
+	data := lines[1]["data"].(map[string]interface{})
+	request := data["http_request"].(map[string]interface{})
+	response := data["http_response"].(map[string]interface{})
+
+	wantHeaders := map[string]interface{}{"Authorization": "[REDACTED]", "Accept": "*/*"}
+	if !reflect.DeepEqual(request["headers"], wantHeaders) {
+		t.Errorf("headers = %v, want %v", request["headers"], wantHeaders)
+	}
+	if want := `{"Password":"[REDACTED]","card":{"cvv":"[REDACTED]"},"note":"key [REDACTED]"}`; request["body"] != want {
+		t.Errorf("request body = %v, want %v", request["body"], want)
+	}
+	if want := `{"cvv":"[REDACTED]"}`; response["body"] != want {
+		t.Errorf("response body = %v, want %v", response["body"], want)
+	}
+}
+
+func TestConfigureInvalidRedactionRules(t *testing.T) {
+	c := NewProductionConfig()
+	c.RedactionRules = RedactionRules{Version: 1, Patterns: []PatternRule{{Name: "bad", Pattern: "("}}}
+
+	var e *RedactionRuleError
+	if err := (&SukiLogger{}).Configure(c); !errors.As(err, &e) || e.Path != "patterns[0].pattern" {
+		t.Errorf("Configure() error = %v, want the invalid pattern", err)
+	}
+}
@@ -0,0 +1,36 @@
+package slog
+
+import "go.uber.org/zap/zapcore"
+
+// RolloutInfo is a rollout decision: a feature is enabled for the buckets
+// below its rollout percentage.
+type RolloutInfo struct {
+	Feature    string  `json:"feature"`
+	Percentage float64 `json:"percentage"`
+	// Bucket is the bucket of the user or request, from 0 to 99.
+	Bucket  int  `json:"bucket"`
+	Enabled bool `json:"enabled"`
+}
+
+// WithRollout returns the decision of rolling feature out to percentage
+// percent of the buckets for bucket.
+func WithRollout(feature string, percentage float64, bucket int) RolloutInfo {
+	return RolloutInfo{
+		Feature:    feature,
+		Percentage: percentage,
+		Bucket:     bucket,
+		Enabled:    float64(bucket) < percentage,
+	}
+}
+
+// Rollout writes a rollout log of a feature rollout decision.
+func (s SukiLogger) Rollout(info RolloutInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["rollout"] = info
+
+	if ce := s.sampledLogger("rollout", alertLevel, zapcore.InfoLevel, "rollout "+info.Feature, data).Check(zapcore.InfoLevel, "rollout "+info.Feature); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
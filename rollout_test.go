@@ -0,0 +1,73 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithRollout(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentage float64
+		bucket     int
+		want       bool
+	}{
+		{
+			name:       "Bucket below percentage",
+			percentage: 25,
+			bucket:     24,
+			want:       true,
+		},
+		{
+			name:       "Bucket at percentage",
+			percentage: 25,
+			bucket:     25,
+			want:       false,
+		},
+		{
+			name:       "Fractional percentage",
+			percentage: 0.5,
+			bucket:     0,
+			want:       true,
+		},
+		{
+			name:       "No rollout",
+			percentage: 0,
+			bucket:     0,
+			want:       false,
+		},
+		{
+			name:       "Full rollout",
+			percentage: 100,
+			bucket:     99,
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithRollout("new-checkout", tt.percentage, tt.bucket).Enabled; got != tt.want {
+				t.Errorf("Enabled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollout(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.Rollout(WithRollout("new-checkout", 25, 10), WithTracing("trace", "span"))
+
+	line := decodeLines(t, buf)[0]
+	if line["log_type"] != "rollout" || line["message"] != "rollout new-checkout" {
+		t.Errorf("log_type = %v, message = %v", line["log_type"], line["message"])
+	}
+	want := map[string]interface{}{
+		"feature":    "new-checkout",
+		"percentage": float64(25),
+		"bucket":     float64(10),
+		"enabled":    true,
+	}
+	if got := line["data"].(map[string]interface{})["rollout"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("rollout = %v, want %v", got, want)
+	}
+}
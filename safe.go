@@ -0,0 +1,74 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// safeValue encodes a user supplied field value, replacing it with a marker
+// when its Error, MarshalJSON or MarshalText implementation panics so the
+// rest of the entry is still written.
+type safeValue struct {
+	key    string
+	value  interface{}
+	report func(error)
+}
+
+func (v safeValue) MarshalJSON() (b []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = json.Marshal(v.panicField(r))
+		}
+	}()
+
+	value := v.value
+	if val, ok := value.(error); ok {
+		value = val.Error()
+	}
+	return marshalNoEscape(value)
+}
+
+// marshalNoEscape encodes like json.Marshal without HTML escaping, matching zap's reflected encoder.
+func marshalNoEscape(value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func (v safeValue) panicField(r interface{}) map[string]string {
+	v.report(fmt.Errorf("field %q panicked while encoding: %v", v.key, r))
+
+	return map[string]string{
+		"_field_panic": v.key,
+		"error":        fmt.Sprint(r),
+	}
+}
+
+// safeReadBody reads a body like readBody, replacing it with the marker of a
+// panicking field when the reader panics.
+func (s SukiLogger) safeReadBody(key string, r io.Reader, maxBodySize int) (body string, truncated bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, _ := json.Marshal(safeValue{key: key, report: s.internalError}.panicField(r))
+			body, truncated = string(b), false
+		}
+	}()
+
+	return readBody(r, maxBodySize)
+}
+
+// internalError reports failures of the logger itself to the zap error output.
+func (s SukiLogger) internalError(err error) {
+	out := s.errorOutput
+	if out == nil {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "%v slog: %v\n", s.clock().Now().UTC(), err)
+}
@@ -0,0 +1,185 @@
+package slog
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// panickingStringer only implements fmt.Stringer, which the JSON encoding never calls.
+type panickingStringer struct {
+	Values map[string]string `json:"values"`
+}
+
+func (p panickingStringer) String() string {
+	panic("nil map stringer")
+}
+
+// panickingTextMarshaler is a Stringer whose MarshalText calls its String.
+type panickingTextMarshaler struct {
+	panickingStringer
+}
+
+func (p panickingTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// panickingReader panics on Read, as a body reader of a closed stream may.
+type panickingReader struct{}
+
+func (panickingReader) Read(p []byte) (int, error) {
+	panic("read on closed body")
+}
+
+type panickingMarshaler struct{}
+
+func (p panickingMarshaler) MarshalJSON() ([]byte, error) {
+	panic("marshal json")
+}
+
+type panickingError struct{}
+
+func (p *panickingError) Error() string {
+	panic("error method")
+}
+
+func TestPanicDuringFieldEncoding(t *testing.T) {
+	var nilErr *panickingError
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "MarshalText", value: panickingTextMarshaler{}, want: "nil map stringer"},
+		{name: "MarshalJSON", value: panickingMarshaler{}, want: "marshal json"},
+		{name: "Error", value: nilErr, want: "error method"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			errorOutput := &bytes.Buffer{}
+			logger.errorOutput = errorOutput
+
+			logger.Info("hello", Any("bad", tt.value), Any("good", "<ok>"))
+
+			lines := decodeLines(t, buf)
+			if len(lines) != 1 {
+				t.Fatalf("got %d log lines, want 1", len(lines))
+			}
+			payload := lines[0]["data"].(map[string]interface{})["application"].(map[string]interface{})
+
+			want := map[string]interface{}{"_field_panic": "bad", "error": tt.want}
+			if !reflect.DeepEqual(payload["bad"], want) {
+				t.Errorf("bad = %v, want %v", payload["bad"], want)
+			}
+			if payload["good"] != "<ok>" {
+				t.Errorf("good = %v, want <ok>", payload["good"])
+			}
+			if !strings.Contains(errorOutput.String(), `field "bad" panicked`) {
+				t.Errorf("panic was not reported, error output = %q", errorOutput.String())
+			}
+		})
+	}
+}
+
+func TestStringerNotCalledByEncoding(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	errorOutput := &bytes.Buffer{}
+	logger.errorOutput = errorOutput
+
+	logger.Info("hello", Any("stringer", panickingStringer{}))
+
+	payload := decodeLines(t, buf)[0]["data"].(map[string]interface{})["application"].(map[string]interface{})
+	if want := map[string]interface{}{"values": nil}; !reflect.DeepEqual(payload["stringer"], want) {
+		t.Errorf("stringer = %v, want %v", payload["stringer"], want)
+	}
+	if errorOutput.Len() != 0 {
+		t.Errorf("error output = %q, want no panic", errorOutput.String())
+	}
+}
+
+func TestPanicInUserValuePaths(t *testing.T) {
+	type order struct {
+		ID     string             `log:"id"`
+		Status panickingMarshaler `log:"status"`
+	}
+
+	tests := []struct {
+		name  string
+		log   func(s *SukiLogger)
+		path  []string
+		field string
+	}{
+		{
+			name: "Request body reader",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}.WithBodyReader(panickingReader{}), HTTPResponseInfo{})
+			},
+			path:  []string{"http_request", "body"},
+			field: "http_request.body",
+		},
+		{
+			name: "Audit before",
+			log: func(s *SukiLogger) {
+				s.Audit(WithAudit("alice", "update", "order/1", panickingMarshaler{}, map[string]string{"status": "paid"}))
+			},
+			path:  []string{"audit", "before"},
+			field: "before",
+		},
+		{
+			name: "Audit diff",
+			log: func(s *SukiLogger) {
+				s.Audit(WithAudit("alice", "update", "order/1", map[string]string{"status": "new"}, panickingMarshaler{}).Diff())
+			},
+			path:  []string{"audit", "after"},
+			field: "after",
+		},
+		{
+			name: "StructFields",
+			log: func(s *SukiLogger) {
+				s.Info("order", StructFields(order{ID: "1"}))
+			},
+			path:  []string{"application", "status"},
+			field: "status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			errorOutput := &bytes.Buffer{}
+			logger.errorOutput = errorOutput
+
+			tt.log(logger)
+
+			lines := decodeLines(t, buf)
+			if len(lines) != 1 {
+				t.Fatalf("got %d log lines, want 1", len(lines))
+			}
+			value := interface{}(lines[0]["data"])
+			for _, key := range tt.path {
+				value = value.(map[string]interface{})[key]
+			}
+			if body, ok := value.(string); ok {
+				value, _ = decodeJSONValue([]byte(body))
+			}
+			if marker, ok := value.(map[string]interface{}); !ok || marker["_field_panic"] != tt.field {
+				t.Errorf("%v = %v, want the marker of %q", tt.path, value, tt.field)
+			}
+			if !strings.Contains(errorOutput.String(), "panicked") {
+				t.Errorf("panic was not reported, error output = %q", errorOutput.String())
+			}
+		})
+	}
+}
+
+func TestSafeValueEncoding(t *testing.T) {
+	b, err := marshalNoEscape(safeValue{key: "k", value: "a<b>&c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"a<b>&c"` {
+		t.Errorf("MarshalJSON() = %s, want unescaped string", b)
+	}
+}
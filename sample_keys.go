@@ -0,0 +1,170 @@
+package slog
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keySampler samples the logs like the zap sampler, but grouping them by the
+// values of the SampleKeys instead of their level and message. The key of a log
+// is resolved from its data before it is checked, so a dropped log never reads
+// its bodies nor encodes its data, see sampledLogger.
+type keySampler struct {
+	keys       [][]string
+	first      uint64
+	thereafter uint64
+	counts     keyCounts
+}
+
+// newKeySampler returns the sampler of the SampleKeys of c, nil when they are
+// not set or the logs are not sampled.
+func newKeySampler(c Config, sampling *zap.SamplingConfig) *keySampler {
+	if sampling == nil || len(c.SampleKeys) == 0 {
+		return nil
+	}
+
+	keys := make([][]string, len(c.SampleKeys))
+	for i, key := range c.SampleKeys {
+		keys[i] = strings.Split(key, ".")
+	}
+	return &keySampler{
+		keys:       keys,
+		first:      uint64(sampling.Initial),
+		thereafter: uint64(sampling.Thereafter),
+	}
+}
+
+// keyCounts counts the logs of every key written during the current second.
+type keyCounts struct {
+	mu    sync.Mutex
+	tick  int64
+	count map[string]uint64
+}
+
+func (k *keyCounts) inc(key string, t time.Time) uint64 {
+	tick := t.UnixNano() / int64(time.Second)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.count == nil || tick != k.tick {
+		k.tick = tick
+		k.count = make(map[string]uint64)
+	}
+	k.count[key]++
+	return k.count[key]
+}
+
+// admit counts a log of key and tells whether it is kept.
+func (k *keySampler) admit(key string, t time.Time) bool {
+	n := k.counts.inc(key, t)
+	return n <= k.first || (k.thereafter != 0 && (n-k.first)%k.thereafter == 0)
+}
+
+// nopLogger stands for the logger of the logs dropped by the sampling of the SampleKeys.
+var nopLogger = zap.NewNop()
+
+// sampledLogger returns the envelopeLogger of a logType log holding data, or a
+// logger writing nothing when the log is dropped by the sampling of the SampleKeys.
+// The caller checks the log on the returned logger so its caller stays the caller of the log.
+func (s SukiLogger) sampledLogger(logType string, alertLevel AlertLevel, level zapcore.Level, message string, data map[string]interface{}) *zap.Logger {
+	return s.sample(s.envelopeLogger(logType, alertLevel), logType, alertLevel, level, message, data)
+}
+
+// sample returns logger, or a logger writing nothing when the log is dropped by
+// the sampling of the SampleKeys.
+func (s SukiLogger) sample(logger *zap.Logger, logType string, alertLevel AlertLevel, level zapcore.Level, message string, data map[string]interface{}) *zap.Logger {
+	// The alerting logs are never sampled, nor the panics which must still panic.
+	if s.sampler == nil || alertLevel >= LevelAlert || level >= zapcore.DPanicLevel || !logger.Core().Enabled(level) {
+		return logger
+	}
+	if !s.sampler.admit(s.sampleKey(logType, alertLevel, level, message, data), s.clock().Now()) {
+		return nopLogger
+	}
+	return logger
+}
+
+// sampleKey returns the composite key of a log, the values of the SampleKeys.
+func (s SukiLogger) sampleKey(logType string, alertLevel AlertLevel, level zapcore.Level, message string, data map[string]interface{}) string {
+	var b strings.Builder
+	for i, path := range s.sampler.keys {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+
+		var value interface{}
+		switch path[0] {
+		case "level":
+			value = level.String()
+		case "message":
+			value = message
+		case "app_name":
+			value = s.config.AppName
+		case "version":
+			value = s.config.Version
+		case "log_type":
+			value = logType
+		case "alert":
+			value = int(alertLevel)
+		case "data":
+			value = valuePath(data, path[1:])
+		}
+		fmt.Fprint(&b, value)
+	}
+	return b.String()
+}
+
+// valuePath returns the value at path of v, going through maps and the JSON
+// names of struct fields, nil when missing.
+func valuePath(v interface{}, path []string) interface{} {
+	for _, key := range path {
+		if safe, ok := v.(safeValue); ok {
+			v = safe.value
+		}
+		v = child(reflect.ValueOf(v), key)
+		if v == nil {
+			return nil
+		}
+	}
+	if safe, ok := v.(safeValue); ok {
+		return safe.value
+	}
+	return v
+}
+
+// child returns the value of the key of the map or the struct v.
+func child(v reflect.Value, key string) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		if value := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())); value.IsValid() {
+			return value.Interface()
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" {
+				name = field.Name
+			}
+			if name == key && field.PkgPath == "" {
+				return v.Field(i).Interface()
+			}
+		}
+	}
+	return nil
+}
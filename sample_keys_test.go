@@ -0,0 +1,128 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"testing"
+	"time"
+)
+
+func TestSampleKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		log       func(s *SukiLogger)
+		wantExtra int
+	}{
+		{
+			name: "Same composite key with another message",
+			log: func(s *SukiLogger) {
+				s.Warn("checkout failed again", Any("error_code", "E1"), Any("endpoint", "/checkout"))
+			},
+			wantExtra: 0,
+		},
+		{
+			name: "Other level",
+			log: func(s *SukiLogger) {
+				s.Error("checkout failed", Any("error_code", "E1"), Any("endpoint", "/checkout"))
+			},
+			wantExtra: 1,
+		},
+		{
+			name: "Other error code",
+			log: func(s *SukiLogger) {
+				s.Warn("checkout failed", Any("error_code", "E2"), Any("endpoint", "/checkout"))
+			},
+			wantExtra: 1,
+		},
+		{
+			name: "Other endpoint",
+			log: func(s *SukiLogger) {
+				s.Warn("checkout failed", Any("error_code", "E1"), Any("endpoint", "/cart"))
+			},
+			wantExtra: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.Clock = slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c.SampleKeys = []string{"level", "data.application.error_code", "data.application.endpoint"}
+			logger, buf := newTestLogger(c)
+
+			// The production sampling keeps the first 100 logs of a group every second.
+			for i := 0; i < 150; i++ {
+				logger.Warn("checkout failed", Any("error_code", "E1"), Any("endpoint", "/checkout"))
+			}
+			tt.log(logger)
+
+			if got := len(decodeLines(t, buf)); got != 100+tt.wantExtra {
+				t.Errorf("%d logs written, want %d", got, 100+tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestSampleKeysNewSecond(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.SampleKeys = []string{"log_type", "data.http_request.path"}
+	logger, buf := newTestLogger(c)
+
+	request := WithHTTPRequest("GET", "/orders", "", nil, nil, nil, "")
+	for i := 0; i < 101; i++ {
+		logger.RequestHTTP("http request", request, WithHTTPResponse(200, 1, ""))
+	}
+	clock.Advance(time.Second)
+	logger.RequestHTTP("http request", request, WithHTTPResponse(200, 1, ""))
+
+	if got := len(decodeLines(t, buf)); got != 101 {
+		t.Errorf("%d logs written, want 100 then 1 in the next second", got)
+	}
+}
+
+func TestSampleKeysDroppedLogNotRead(t *testing.T) {
+	c := NewProductionConfig()
+	c.Clock = slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c.MaxBodySize = 16
+	c.SampleKeys = []string{"log_type", "data.http_request.path"}
+	logger, buf := newTestLogger(c)
+
+	request := WithHTTPRequest("GET", "/orders", "", nil, nil, nil, "")
+	for i := 0; i < 100; i++ {
+		logger.RequestHTTP("http request", request, WithHTTPResponse(200, 1, ""))
+	}
+	body := &endlessReader{}
+	logger.RequestHTTP("http request", request.WithBodyReader(body), WithHTTPResponse(200, 1, ""))
+
+	if body.read != 0 {
+		t.Errorf("read %d bytes from a sampled out log", body.read)
+	}
+	if got := len(decodeLines(t, buf)); got != 100 {
+		t.Errorf("%d logs written, want 100", got)
+	}
+}
+
+func TestValuePath(t *testing.T) {
+	data := map[string]interface{}{
+		"http_request": WithHTTPRequest("GET", "/orders", "", map[string]string{"X-Tenant": "shop-42"}, nil, nil, ""),
+		"application":  map[string]interface{}{"error_code": safeValue{key: "error_code", value: "E1"}},
+	}
+	tests := []struct {
+		name string
+		path []string
+		want interface{}
+	}{
+		{name: "Struct field by JSON name", path: []string{"http_request", "path"}, want: "/orders"},
+		{name: "Map of a struct", path: []string{"http_request", "headers", "X-Tenant"}, want: "shop-42"},
+		{name: "Application field", path: []string{"application", "error_code"}, want: "E1"},
+		{name: "Missing key", path: []string{"http_request", "nope"}, want: nil},
+		{name: "Through a scalar", path: []string{"http_request", "path", "nope"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := valuePath(data, tt.path); got != tt.want {
+				t.Errorf("valuePath(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// fallbackSink writes to sink until a write fails, the failure is reported once
+// and the failed write along with every later one go to fallback instead.
+type fallbackSink struct {
+	mu       sync.Mutex
+	sink     zapcore.WriteSyncer
+	fallback zapcore.WriteSyncer
+	failed   bool
+	report   func(error)
+}
+
+func newFallbackSink(sink zapcore.WriteSyncer, fallback zapcore.WriteSyncer, report func(error)) *fallbackSink {
+	return &fallbackSink{sink: sink, fallback: fallback, report: report}
+}
+
+func (f *fallbackSink) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.failed {
+		n, err := f.sink.Write(p)
+		if err == nil {
+			return n, nil
+		}
+		f.failed = true
+		f.report(err)
+	}
+	return f.fallback.Write(p)
+}
+
+func (f *fallbackSink) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failed {
+		return f.fallback.Sync()
+	}
+	return f.sink.Sync()
+}
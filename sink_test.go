@@ -0,0 +1,347 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"go.uber.org/zap/zapcore"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errClosed = errors.New("file already closed")
+
+// failingWriter fails every write once failAfter writes succeeded.
+type failingWriter struct {
+	bytes.Buffer
+	writes    int
+	failAfter int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes > w.failAfter {
+		return 0, errClosed
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestFallbackSink(t *testing.T) {
+	sink := &failingWriter{failAfter: 1}
+	fallback := &bytes.Buffer{}
+	var reported []error
+
+	f := newFallbackSink(zapcore.AddSync(sink), zapcore.AddSync(fallback), func(err error) {
+		reported = append(reported, err)
+	})
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if sink.String() != "first\n" {
+		t.Errorf("sink = %q, want the lines written before the failure", sink.String())
+	}
+	if fallback.String() != "second\nthird\n" {
+		t.Errorf("fallback = %q, want the failed line and every later one", fallback.String())
+	}
+	if sink.writes != 2 {
+		t.Errorf("sink written %d times, want no write after the failure", sink.writes)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], errClosed) {
+		t.Errorf("reported = %v, want the first error only", reported)
+	}
+}
+
+func TestConfigureOnWriteError(t *testing.T) {
+	var reported []error
+	c := NewProductionConfig()
+	c.Output = &failingWriter{}
+	c.OnWriteError = func(err error) {
+		reported = append(reported, err)
+	}
+
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		t.Fatal(err)
+	}
+	logger.Warn("lost")
+	logger.Warn("lost again")
+
+	if len(reported) != 1 || !errors.Is(reported[0], errClosed) {
+		t.Errorf("reported = %v, want the first error only", reported)
+	}
+}
+
+// newFaultyLogger returns a logger writing to a FaultySink scripted by faults
+// and timed by a FakeClock, along with the errors reported to OnWriteError.
+func newFaultyLogger(faults slogtest.Faults) (*SukiLogger, *slogtest.FaultySink, *slogtest.FakeClock, *[]error) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sink := slogtest.NewFaultySink(clock, faults)
+	reported := &[]error{}
+
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.Output = sink
+	c.OnWriteError = func(err error) {
+		*reported = append(*reported, err)
+	}
+
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		panic(err)
+	}
+	return logger, sink, clock, reported
+}
+
+func TestOutputFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		faults       slogtest.Faults
+		logs         int
+		wantLines    int
+		wantWrites   int
+		wantReported error
+	}{
+		{
+			name:       "Healthy output",
+			logs:       3,
+			wantLines:  3,
+			wantWrites: 3,
+		},
+		{
+			name:         "Output failing after 2 lines is not written again",
+			faults:       slogtest.Faults{FailAfter: 2},
+			logs:         5,
+			wantLines:    2,
+			wantWrites:   3,
+			wantReported: slogtest.ErrInjected,
+		},
+		{
+			name:         "Output failing from the start",
+			faults:       slogtest.Faults{FailureRate: 1},
+			logs:         3,
+			wantLines:    0,
+			wantWrites:   1,
+			wantReported: slogtest.ErrInjected,
+		},
+		{
+			name:         "Intermittent failure abandons the output",
+			faults:       slogtest.Faults{FailureRate: 0.5, Rand: sequence(0.9, 0.1, 0.9, 0.9)},
+			logs:         4,
+			wantLines:    1,
+			wantWrites:   2,
+			wantReported: slogtest.ErrInjected,
+		},
+		{
+			name:         "Partial write abandons the output",
+			faults:       slogtest.Faults{PartialWrite: 10},
+			logs:         2,
+			wantLines:    1,
+			wantWrites:   1,
+			wantReported: io.ErrShortWrite,
+		},
+		{
+			name:         "Custom error is reported as is",
+			faults:       slogtest.Faults{FailAfter: 1, Err: errClosed},
+			logs:         2,
+			wantLines:    1,
+			wantWrites:   2,
+			wantReported: errClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, sink, _, reported := newFaultyLogger(tt.faults)
+
+			for i := 0; i < tt.logs; i++ {
+				logger.Warn("message")
+			}
+
+			if len(sink.Lines()) != tt.wantLines || sink.Writes() != tt.wantWrites {
+				t.Errorf("lines, writes = %d, %d, want %d, %d", len(sink.Lines()), sink.Writes(), tt.wantLines, tt.wantWrites)
+			}
+			if tt.wantReported == nil {
+				if len(*reported) != 0 {
+					t.Errorf("reported = %v, want none", *reported)
+				}
+				return
+			}
+			if len(*reported) != 1 || !errors.Is((*reported)[0], tt.wantReported) {
+				t.Errorf("reported = %v, want %v once", *reported, tt.wantReported)
+			}
+		})
+	}
+}
+
+func TestOutputRecoveryIsNotPickedUp(t *testing.T) {
+	logger, sink, clock, reported := newFaultyLogger(slogtest.Faults{FailAfter: 1, RecoverAfter: time.Second})
+
+	logger.Warn("written")
+	logger.Warn("failed")
+	clock.Advance(time.Minute)
+	logger.Warn("still on the fallback")
+
+	if len(sink.Lines()) != 1 || sink.Writes() != 2 {
+		t.Errorf("lines, writes = %d, %d, want the output abandoned after its failure", len(sink.Lines()), sink.Writes())
+	}
+	if len(*reported) != 1 {
+		t.Errorf("reported = %v, want the failure once", *reported)
+	}
+}
+
+func TestOutputFailureReportedOnceConcurrently(t *testing.T) {
+	logger, sink, _, reported := newFaultyLogger(slogtest.Faults{FailAfter: 10})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				logger.Warn("message", LogOption{Alert: LevelAlert})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(sink.Lines()) != 10 || sink.Writes() != 11 {
+		t.Errorf("lines, writes = %d, %d, want 10, 11", len(sink.Lines()), sink.Writes())
+	}
+	if len(*reported) != 1 {
+		t.Errorf("reported %d errors, want 1", len(*reported))
+	}
+}
+
+func TestAlertAndSampledLogsShareTheFallback(t *testing.T) {
+	logger, sink, _, reported := newFaultyLogger(slogtest.Faults{FailAfter: 1})
+
+	logger.Warn("sampled")
+	logger.Warn("alert", LogOption{Alert: LevelAlert})
+	logger.Warn("sampled again")
+
+	if sink.Writes() != 2 || len(*reported) != 1 {
+		t.Errorf("writes, reported = %d, %d, want 2, 1", sink.Writes(), len(*reported))
+	}
+}
+
+func TestSubscribersIsolatedFromOutputFailure(t *testing.T) {
+	logger, _, _, _ := newFaultyLogger(slogtest.Faults{FailureRate: 1})
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	logger.Warn("first")
+	logger.Warn("second")
+
+	for _, want := range []string{"first", "second"} {
+		if entry := <-entries; entry.Message != want {
+			t.Errorf("entry = %q, want %q", entry.Message, want)
+		}
+	}
+}
+
+func TestRequestBufferFlushToFailingOutput(t *testing.T) {
+	logger, sink, _, reported := newFaultyLogger(slogtest.Faults{FailureRate: 1})
+	buffer := logger.NewRequestBuffer()
+
+	buffer.Info("step 1")
+	buffer.Warn("step 2")
+	if sink.Writes() != 0 {
+		t.Errorf("writes = %d before Flush, want 0", sink.Writes())
+	}
+
+	buffer.Flush("request")
+	buffer.Flush("empty")
+
+	if sink.Writes() != 1 || len(*reported) != 1 {
+		t.Errorf("writes, reported = %d, %d, want the flushed trace written once", sink.Writes(), len(*reported))
+	}
+}
+
+func TestSlowOutputBlocksTheCaller(t *testing.T) {
+	logger, sink, clock, _ := newFaultyLogger(slogtest.Faults{Latency: 200 * time.Millisecond})
+	start := clock.Now()
+
+	logger.Info("first")
+	logger.Info("second")
+
+	if elapsed := clock.Now().Sub(start); elapsed != 400*time.Millisecond {
+		t.Errorf("elapsed = %v, want the latency of both writes", elapsed)
+	}
+
+	// The timestamp is taken before writing, the second line waited for the first one.
+	var timestamps []string
+	for _, line := range sink.Lines() {
+		timestamps = append(timestamps, decodeLines(t, bytes.NewBufferString(line))[0]["timestamp"].(string))
+	}
+	if want := []string{"2024-03-01T12:00:00.000Z", "2024-03-01T12:00:00.200Z"}; len(timestamps) != 2 || timestamps[0] != want[0] || timestamps[1] != want[1] {
+		t.Errorf("timestamps = %v, want %v", timestamps, want)
+	}
+}
+
+func TestSlowOutputInflatesTimers(t *testing.T) {
+	logger, sink, _, _ := newFaultyLogger(slogtest.Faults{Latency: 50 * time.Millisecond})
+
+	timer := logger.StartTimer("import")
+	logger.Info("progress")
+	timer.Stop()
+
+	line := decodeLines(t, bytes.NewBufferString(sink.Lines()[1]))[0]
+	if duration := line["data"].(map[string]interface{})["timer"].(map[string]interface{})["duration"]; duration != float64(50) {
+		t.Errorf("duration = %v, want the latency of the log written while timing", duration)
+	}
+}
+
+func TestSlowOutputInflatesHandlerTiming(t *testing.T) {
+	logger, sink, _, _ := newFaultyLogger(slogtest.Faults{Latency: 20 * time.Millisecond})
+	handler := logger.HTTPMiddleware(MiddlewareOption{DetailedTiming: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.Info("handling")
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	lines := sink.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	// The request log itself is timed before it is written.
+	timing := timingOf(t, decodeLines(t, bytes.NewBufferString(lines[1]))[0])
+	if timing["duration_handler_ms"] != float64(20) || timing["duration_total_ms"] != float64(20) {
+		t.Errorf("timing = %v, want the latency of the handler's log only", timing)
+	}
+}
+
+func TestConfigureIgnoresSyncError(t *testing.T) {
+	sink := slogtest.NewFaultySink(nil, slogtest.Faults{SyncErr: errClosed})
+	c := NewProductionConfig()
+	c.Output = sink
+
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		t.Errorf("Configure() error = %v, want the sync error ignored", err)
+	}
+	if sink.Syncs() != 1 {
+		t.Errorf("Syncs() = %d, want 1", sink.Syncs())
+	}
+
+	logger.Info("written")
+	if len(sink.Lines()) != 1 {
+		t.Errorf("lines = %d, want 1 after the failed sync", len(sink.Lines()))
+	}
+}
+
+// sequence returns a Faults.Rand returning values in turn.
+func sequence(values ...float64) func() float64 {
+	return func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+}
@@ -1,9 +1,14 @@
 package slog
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"io"
+	"os"
 	"time"
 )
 
@@ -25,11 +30,151 @@ type Config struct {
 	AppName     string
 	Version     string
 	MaxBodySize int
+	// MaxRequestBodySize and MaxResponseBodySize limit the request and response
+	// bodies of HTTP logs independently, MaxBodySize when 0. No body limit goes
+	// above MaxBodyCeiling, a limit of 0 included.
+	MaxRequestBodySize  int
+	MaxResponseBodySize int
+	// MaxHeaders caps the number of request headers of HTTP logs (0 = Unlimited), the
+	// PriorityHeaders are kept first then the others in alphabetical order.
+	MaxHeaders      int
+	PriorityHeaders []string
+	// PromoteContentHeaders copies the Content-Type and Accept request headers of
+	// HTTP logs to http_request.content_type and accept, they stay in the headers too.
+	PromoteContentHeaders bool
+	// BodyLevel is the minimum level of the HTTP logs written with their bodies, e.g.
+	// LevelWarn omits the bodies of the requests logged at info level. Debug requests keep them.
+	BodyLevel LogLevel
+	// SlowThresholdMs maps a log_type (e.g. "handler.http") to the duration in milliseconds
+	// above which the log is written at warn level with data.slow set to true.
+	SlowThresholdMs map[string]float64
+	// SlowQueryThresholdMs is the duration in milliseconds above which RequestDB
+	// writes an extra slow_query log at warn level with alert set. 0 disables it.
+	SlowQueryThresholdMs float64
+	// StackTraceMode controls whether error stack traces are written as a string, frames or both.
+	StackTraceMode StackTraceMode
+	// StackTraceSeparator, when set, replaces the line breaks of the stack traces
+	// with it, e.g. " | ", for consumers expecting single-line values.
+	StackTraceSeparator string
+	// Output is where logs are written instead of stderr, e.g. a *lumberjack.Logger for file output.
+	Output io.Writer
+	// MaxPlausibleDurationMs flags the durations of logs above it with duration_anomaly "implausible",
+	// typically a duration given in the wrong unit. 0 disables the check.
+	MaxPlausibleDurationMs float64
+	// OmitEmpty drops the data fields holding null, an empty string, an empty object or an empty array.
+	OmitEmpty bool
+	// BatchErrorRatio is the ratio of failed items from which a batch is logged at error level,
+	// batches with fewer failures are logged at warn level. 0 treats any failure as an error.
+	BatchErrorRatio float64
+	// AnomalyDeviation is the deviation from its baseline, either way, above which an anomaly
+	// is logged at warn level. 0 treats any deviation as an anomaly.
+	AnomalyDeviation float64
+	// CloudEvents writes the event logs as CloudEvents instead of the logger envelope.
+	CloudEvents CloudEventsConfig
+	// Async buffers the writes to Output, see AsyncConfig.
+	Async AsyncConfig
+	// SampleSeed, when set, seeds the sampling of the audited request bodies so it is
+	// reproducible, and is hashed with the request IDs of MiddlewareOption.BodyAuditRequestIDHeader.
+	SampleSeed int64
+	// MeasureEncoding adds data._encode_ms, the time spent marshaling the data of a log.
+	// The data is marshaled once more to be timed, only enable it in development.
+	MeasureEncoding bool
+	// RequestBodyOnError only writes the request body of the HTTP logs whose response
+	// status is 400 or above, it is omitted on success.
+	RequestBodyOnError bool
+	// MultiValueQuery writes every value of the query params of the HTTP logs, parsed
+	// from their RawQuery, as query_values.
+	MultiValueQuery bool
+	// SampleKeys are the fields whose combined values group the repeated logs to sample,
+	// level and message when empty. A key is level, message, app_name, version, log_type,
+	// alert or a dot-path into data such as data.http_request.path.
+	SampleKeys []string
+	// DisableSampling writes every log, the repeated logs are otherwise sampled.
+	DisableSampling bool
+	// FieldAllowlist, when set, drops every data field whose key is not on it, e.g.
+	// []string{"tracing", "http_response"}. The application fields are under the AppName key.
+	FieldAllowlist []string
+	// RedactPaths masks the values at dot-paths of the data, whatever their key
+	// elsewhere, e.g. "http_request.body.card.number".
+	RedactPaths []string
+	// RedactPatterns masks emails, phone numbers and card numbers found in bodies and payloads.
+	RedactPatterns RedactPatterns
+	// FieldPrefix is prepended to every top-level field name, e.g. "suki_" writes suki_message and suki_data.
+	FieldPrefix string
+	// MessagePrefix is prepended to the message text of every line, e.g. "[checkout] "
+	// to tell apart the components sharing a stream. Unlike a field it is read at a glance.
+	MessagePrefix string
+	// IncludeLineSize adds log_size_bytes, the size in bytes of the written line including the field itself.
+	IncludeLineSize bool
+	// OnWriteError is called with the first error writing to Output, logs are written to stderr from then on.
+	OnWriteError func(error)
+	// CallerKey, FunctionKey and StacktraceKey rename the caller, function and
+	// stacktrace fields, the function is only written when FunctionKey is set.
+	CallerKey     string
+	FunctionKey   string
+	StacktraceKey string
+	// LineEnding ends every line, "\n" (the default) or "\r\n" for Windows collectors.
+	LineEnding string
+	// LevelNames renames the written levels, e.g. LevelWarn to WARNING, the
+	// levels it does not map keep their lowercase names.
+	LevelNames map[LogLevel]string
+	// RedactionRules are the sensitive keys, header rules and patterns redacted
+	// in HTTP logs and Kafka payloads, usually read with LoadRedactionRules.
+	RedactionRules RedactionRules
+	// IncludeMonotonic adds monotonic_ms, the milliseconds since Configure read from
+	// the monotonic clock, to every line to detect wall-clock steps between lines.
+	IncludeMonotonic bool
+	// IncludeUptime adds uptime_seconds, the seconds since the logger was first configured,
+	// to tell a restarted process from a long running one.
+	IncludeUptime bool
+	// AlertOnAuthzDeny sets alert on the authz logs of denied decisions.
+	AlertOnAuthzDeny bool
+	// RawNotificationRecipients writes the recipients of notification logs as is instead of hashed.
+	RawNotificationRecipients bool
+	// SuppressConfigWarnings stops Configure from writing a config log about the
+	// soft problems of the configuration, such as a very large MaxBodySize.
+	SuppressConfigWarnings bool
+	// Clock is the time source of the logger, the real clock when nil.
+	Clock Clock
 }
 
+// Rotator is implemented by file outputs that can be rotated on demand.
+type Rotator interface {
+	Rotate() error
+}
+
+var ErrNotRotatable = errors.New("slog: output does not support rotation")
+
+// ErrInvalidLineEnding is returned by Configure when Config.LineEnding is neither "\n" nor "\r\n".
+var ErrInvalidLineEnding = errors.New(`slog: line ending must be "\n" or "\r\n"`)
+
 type SukiLogger struct {
 	config      Config
 	zapInstance *zap.Logger
+	// alertInstance writes alerting logs without sampling.
+	alertInstance *zap.Logger
+	// debugInstance writes the logs of debug requests whatever the configured level.
+	debugInstance *zap.Logger
+	// cloudEventsInstance writes the event logs when CloudEvents is enabled,
+	// cloudEventsAlertInstance the alerting ones without sampling.
+	cloudEventsInstance      *zap.Logger
+	cloudEventsAlertInstance *zap.Logger
+	errorOutput              io.Writer
+	subscribers              *subscribers
+	captures                 *captures
+	heartbeats               *heartbeats
+	deprecations             *deprecations
+	durationStats            *durationStats
+	// started is when the logger was first configured, see IncludeUptime.
+	started time.Time
+	// defaultOption applies to the logs without a LogOption, see WithDefaultOption.
+	defaultOption LogOption
+	// async buffers the output when Async is enabled.
+	async *asyncBuffer
+	// envelopes holds a child logger per log type, see buildEnvelopes.
+	envelopes map[envelopeKey]*zap.Logger
+	// sampler samples the logs by their SampleKeys when set.
+	sampler *keySampler
 }
 
 type LogField struct {
@@ -52,6 +197,8 @@ type TraceInfo struct {
 	TraceID   string `json:"trace_id"`
 	SpanID    string `json:"span_id"`
 	RequestID string `json:"request_id"`
+	// ParentRequestID is the request ID of the request a child call was fanned out from.
+	ParentRequestID string `json:"parent_request_id,omitempty"`
 }
 
 type HTTPRequestInfo struct {
@@ -62,18 +209,76 @@ type HTTPRequestInfo struct {
 	Params   map[string]string `json:"params"`
 	Query    map[string]string `json:"query"`
 	Body     string            `json:"body"`
+	Handler  string            `json:"handler,omitempty"`
+	// RawQuery is the original query string, Query only keeps the first value of a param.
+	RawQuery string `json:"raw_query,omitempty"`
+	// QueryValues holds every value of the params of RawQuery when MultiValueQuery is set.
+	QueryValues map[string][]string `json:"query_values,omitempty"`
+	// ContentType and Accept are the headers promoted by PromoteContentHeaders.
+	ContentType string `json:"content_type,omitempty"`
+	Accept      string `json:"accept,omitempty"`
+	// HeadersDropped is the number of headers dropped above MaxHeaders.
+	HeadersDropped int `json:"headers_dropped,omitempty"`
+	// Host is the host an outbound request was sent to.
+	Host string `json:"host,omitempty"`
+	// Protocol is HTTP/1.1, HTTP/2.0 or h2c for HTTP/2 without TLS.
+	Protocol    string `json:"protocol,omitempty"`
+	TLSVersion  string `json:"tls_version,omitempty"`
+	CipherSuite string `json:"cipher_suite,omitempty"`
+	// BodyTruncated is set when the body read from a body reader exceeded MaxBodySize.
+	BodyTruncated bool `json:"body_truncated,omitempty"`
+	// BodyOmitted is set when the body was dropped as the log is below BodyLevel.
+	BodyOmitted bool `json:"body_omitted,omitempty"`
+
+	bodyReader io.Reader
 }
 
 type HTTPResponseInfo struct {
-	Status   int64     `json:"status"`
-	Duration float64   `json:"duration"`
-	Body     string    `json:"body"`
-	Error    ErrorInfo `json:"error"`
+	Status          int64       `json:"status"`
+	Duration        float64     `json:"duration"`
+	DurationAnomaly string      `json:"duration_anomaly,omitempty"`
+	Body            string      `json:"body"`
+	Error           ErrorInfo   `json:"error"`
+	Timing          *HTTPTiming `json:"timing,omitempty"`
+	// Trailers are the trailers sent after the body, a non-zero grpc-status sets Error when unset.
+	Trailers map[string]string `json:"trailers,omitempty"`
+	// BodyTruncated is set when the body read from a body reader exceeded MaxBodySize.
+	BodyTruncated bool `json:"body_truncated,omitempty"`
+	// BodyOmitted is set when the body was dropped as the log is below BodyLevel.
+	BodyOmitted bool `json:"body_omitted,omitempty"`
+
+	bodyReader io.Reader
+}
+
+// HTTPTiming is the latency breakdown of a request handled by HTTPMiddleware,
+// all values are in milliseconds.
+type HTTPTiming struct {
+	TotalMs   float64 `json:"duration_total_ms"`
+	HandlerMs float64 `json:"duration_handler_ms"`
+	WriteMs   float64 `json:"duration_write_ms"`
+	TTFBMs    float64 `json:"ttfb_ms"`
 }
 
 type ErrorInfo struct {
-	Name       string `json:"name"`
-	StackTrace string `json:"stack_trace"`
+	Name       string       `json:"name"`
+	StackTrace string       `json:"stack_trace"`
+	Frames     []StackFrame `json:"frames,omitempty"`
+
+	// framesOnly omits stack_trace, the stack trace is written as Frames, see StackTraceFrames.
+	framesOnly bool
+}
+
+// MarshalJSON writes e, without stack_trace when its stack trace is only written as frames.
+func (e ErrorInfo) MarshalJSON() ([]byte, error) {
+	if e.framesOnly {
+		return marshalNoEscape(struct {
+			Name   string       `json:"name"`
+			Frames []StackFrame `json:"frames,omitempty"`
+		}{e.Name, e.Frames})
+	}
+
+	type errorInfo ErrorInfo
+	return marshalNoEscape(errorInfo(e))
 }
 
 type KafkaMessage struct {
@@ -87,8 +292,17 @@ type KafkaMessage struct {
 }
 
 type KafkaResult struct {
-	Duration float64   `json:"duration"`
-	Error    ErrorInfo `json:"error"`
+	Duration        float64   `json:"duration"`
+	DurationAnomaly string    `json:"duration_anomaly,omitempty"`
+	Error           ErrorInfo `json:"error"`
+}
+
+// DeserializationError describes a message payload that could not be decoded into TargetType.
+type DeserializationError struct {
+	Message          string `json:"message"`
+	TargetType       string `json:"target_type"`
+	Payload          string `json:"payload"`
+	PayloadTruncated bool   `json:"payload_truncated,omitempty"`
 }
 
 const (
@@ -140,6 +354,37 @@ func WithTracing(traceID string, spanID string, requestID ...string) TraceInfo {
 	}
 }
 
+// WithParentRequestID sets the request ID of the parent request of a child call,
+// written as tracing.parent_request_id.
+func (t TraceInfo) WithParentRequestID(parentRequestID string) TraceInfo {
+	t.ParentRequestID = parentRequestID
+	return t
+}
+
+type Experiment struct {
+	ID      string `json:"id"`
+	Variant string `json:"variant"`
+}
+
+// WithExperiment tags the log with the experiment variant the request was bucketed into,
+// it can be passed several times for a request in multiple experiments.
+func WithExperiment(id string, variant string) Experiment {
+	return Experiment{
+		ID:      id,
+		Variant: variant,
+	}
+}
+
+// WithDefaultOption returns a logger sharing the output of s whose logs get opts
+// unless a LogOption is passed to the call, e.g. alert on every log of a
+// critical service:
+//
+//	logger := slog.L().WithDefaultOption(slog.LogOption{Alert: slog.LevelAlert})
+func (s SukiLogger) WithDefaultOption(opts LogOption) *SukiLogger {
+	s.defaultOption = opts
+	return &s
+}
+
 func WithOption(opts LogOption) LogOption {
 	return opts
 }
@@ -236,6 +481,16 @@ func WithHTTPResponse(
 	}
 }
 
+// WithHTTPResponseDuration is WithHTTPResponse taking the duration as a time.Duration.
+func WithHTTPResponseDuration(
+	status int64,
+	duration time.Duration,
+	body string,
+	error ...ErrorInfo,
+) HTTPResponseInfo {
+	return WithHTTPResponse(status, toMillis(duration), body, error...)
+}
+
 func WithKafkaMessage(
 	topic string,
 	partition int64,
@@ -276,41 +531,85 @@ func WithKafkaResult(
 	}
 }
 
+// WithKafkaResultDuration is WithKafkaResult taking the duration as a time.Duration.
+func WithKafkaResultDuration(
+	duration time.Duration,
+	error ...ErrorInfo,
+) KafkaResult {
+	return WithKafkaResult(toMillis(duration), error...)
+}
+
+func (c Config) maxRequestBodySize() int {
+	if c.MaxRequestBodySize != 0 {
+		return c.MaxRequestBodySize
+	}
+	return c.MaxBodySize
+}
+
+func (c Config) maxResponseBodySize() int {
+	if c.MaxResponseBodySize != 0 {
+		return c.MaxResponseBodySize
+	}
+	return c.MaxBodySize
+}
+
+// WithDeserializationError describes the failure to decode payload into target,
+// passing it to RequestKafka writes the log at error level with the payload capped to MaxRequestBodySize.
+func WithDeserializationError(err error, payload string, target interface{}) DeserializationError {
+	message := ""
+	if err != nil {
+		message = err.Error()
+	}
+
+	return DeserializationError{
+		Message:    message,
+		TargetType: fmt.Sprintf("%T", target),
+		Payload:    payload,
+	}
+}
+
 func (s SukiLogger) RequestKafka(
 	message string,
 	kafkaMessage KafkaMessage,
 	kafkaResult KafkaResult,
 	args ...interface{},
 ) {
-	appName := zap.String("app_name", s.config.AppName)
-	version := zap.String("version", s.config.Version)
-	logType := zap.String("log_type", "handler.kafka")
 	data := make(map[string]interface{})
-	alertLevel := LevelNone
+	alertLevel := s.requestArgs(data, args)
 
-	for i, _ := range args {
-		if tracing, ok := args[i].(TraceInfo); ok {
-			data["tracing"] = TraceInfo{
-				TraceID: tracing.TraceID,
-				SpanID:  tracing.SpanID,
+	kafkaResult.Error = s.formatError(kafkaResult.Error)
+	kafkaResult.DurationAnomaly = s.checkDuration(&kafkaResult.Duration)
+
+	data["kafka_message"] = kafkaMessage
+	data["kafka_result"] = kafkaResult
+	level := s.durationLevel("handler.kafka", kafkaResult.Duration, data)
+	level = s.batchArgs(level, data, args)
+
+	for i := range args {
+		if e, ok := args[i].(DeserializationError); ok {
+			e.Payload, e.PayloadTruncated = truncateBody(e.Payload, s.config.maxRequestBodySize())
+			if kafkaResult.Error.Name == "" {
+				kafkaResult.Error.Name = "deserialization_error"
+				data["kafka_result"] = kafkaResult
 			}
-		} else if opts, ok := args[i].(LogOption); ok {
-			alertLevel = opts.Alert
+			data["deserialization_error"] = e
+			level = zapcore.ErrorLevel
 		}
 	}
 
-	data["kafka_message"] = kafkaMessage
-	data["kafka_result"] = kafkaResult
-	dataField := zap.Any("data", data)
-
-	s.zapInstance.Info(
-		message,
-		appName,
-		version,
-		logType,
-		zap.Int("alert", int(alertLevel)),
-		dataField,
-	)
+	if ce := s.sampledLogger("handler.kafka", alertLevel, level, message, data).Check(level, message); ce != nil {
+		withCaller(ce, args)
+		if s.config.RedactPatterns.enabled() || !s.config.RedactionRules.active("").empty() {
+			kafkaMessage.Payload = s.redactBody(kafkaMessage.Payload, "")
+			data["kafka_message"] = kafkaMessage
+			if e, ok := data["deserialization_error"].(DeserializationError); ok {
+				e.Payload = s.redactBody(e.Payload, "")
+				data["deserialization_error"] = e
+			}
+		}
+
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) RequestHTTP(
@@ -319,88 +618,281 @@ func (s SukiLogger) RequestHTTP(
 	response HTTPResponseInfo,
 	args ...interface{},
 ) {
-	appName := zap.String("app_name", s.config.AppName)
-	version := zap.String("version", s.config.Version)
-	logType := zap.String("log_type", "handler.http")
 	data := make(map[string]interface{})
-	alertLevel := LevelNone
-
-	for i, _ := range args {
-		if tracing, ok := args[i].(TraceInfo); ok {
-			data["tracing"] = TraceInfo{
-				TraceID: tracing.TraceID,
-				SpanID:  tracing.SpanID,
-			}
-		} else if opts, ok := args[i].(LogOption); ok {
-			alertLevel = opts.Alert
+	alertLevel := s.requestArgs(data, args)
+	level := s.httpData("handler.http", &request, &response, data, args)
+
+	var ce *zapcore.CheckedEntry
+	if _, debug := debugArgs(args); debug && s.debugInstance != nil {
+		data["debug"] = true
+		if level == zapcore.InfoLevel {
+			level = zapcore.DebugLevel
 		}
+		ce = s.debugInstance.With(s.staticFields("handler.http")...).Check(level, message)
+	} else {
+		ce = s.sampledLogger("handler.http", alertLevel, level, message, data).Check(level, message)
 	}
 
-	if s.config.MaxBodySize > 0 {
-		if len(request.Body) > s.config.MaxBodySize {
-			request.Body = "body is too large"
-		}
+	if ce != nil {
+		withCaller(ce, args)
+		s.writeHTTP(ce, alertLevel, data, request, response)
+	}
+}
+
+// httpData adds request and response to the data of a logType log and returns its level.
+func (s SukiLogger) httpData(
+	logType string,
+	request *HTTPRequestInfo,
+	response *HTTPResponseInfo,
+	data map[string]interface{},
+	args []interface{},
+) zapcore.Level {
+	requestLimit, responseLimit := s.config.maxRequestBodySize(), s.config.maxResponseBodySize()
+	if debug, ok := debugArgs(args); ok {
+		requestLimit, responseLimit = debug.maxBodySize, debug.maxBodySize
+	}
+	if len(request.Body) > bodyLimit(requestLimit) {
+		request.Body = "body is too large"
+	}
+	if len(response.Body) > bodyLimit(responseLimit) {
+		response.Body = "body is too large"
+	}
 
-		if len(response.Body) > s.config.MaxBodySize {
-			response.Body = "body is too large"
+	if status, ok := response.Trailers["Grpc-Status"]; ok && status != "0" && response.Error.Name == "" {
+		response.Error.Name = "grpc_status_" + status
+	}
+	response.Error = s.formatError(response.Error)
+	response.DurationAnomaly = s.checkDuration(&response.Duration)
+
+	for i := range args {
+		if scopes, ok := args[i].(ScopeInfo); ok {
+			data["authz"] = httpAuthz{Scopes: scopes}
+		} else if replay, ok := args[i].(IdempotencyReplay); ok {
+			data["idempotency"] = replay
 		}
 	}
 
+	data["http_request"] = *request
+	data["http_response"] = *response
+	level := s.durationLevel(logType, response.Duration, data)
+	return s.batchArgs(level, data, args)
+}
+
+// writeHTTP writes ce once the bodies of request and response are read and redacted,
+// they are only read and scanned once the entry is known to be written at BodyLevel or above.
+func (s SukiLogger) writeHTTP(
+	ce *zapcore.CheckedEntry,
+	alertLevel AlertLevel,
+	data map[string]interface{},
+	request HTTPRequestInfo,
+	response HTTPResponseInfo,
+) {
+	if ce.Entry.Level < zapcore.Level(s.config.BodyLevel) && data["debug"] != true {
+		request.omitBody()
+		response.omitBody()
+	}
+	if s.config.RequestBodyOnError && response.Status < 400 {
+		request.omitBody()
+	}
+	if request.bodyReader != nil {
+		request.Body, request.BodyTruncated = s.safeReadBody("http_request.body", request.bodyReader, s.config.maxRequestBodySize())
+	}
+	if response.bodyReader != nil {
+		response.Body, response.BodyTruncated = s.safeReadBody("http_response.body", response.bodyReader, s.config.maxResponseBodySize())
+	}
+	redactURLs(&request, &response)
+	rules := s.config.RedactionRules.active(request.Path)
+	request.Headers = rules.redactHeaders(request.Headers)
+	if s.config.PromoteContentHeaders {
+		request.ContentType = lookupHeader(request.Headers, "Content-Type")
+		request.Accept = lookupHeader(request.Headers, "Accept")
+	}
+	request.Headers, request.HeadersDropped = s.config.capHeaders(request.Headers)
+	if s.config.MultiValueQuery && request.QueryValues == nil && request.RawQuery != "" {
+		request.QueryValues = parseQueryValues(request.RawQuery)
+	}
+	response.Trailers = rules.redactHeaders(response.Trailers)
+	request.Body = s.redactBody(request.Body, request.Path)
+	response.Body = s.redactBody(response.Body, request.Path)
 	data["http_request"] = request
 	data["http_response"] = response
-	dataField := zap.Any("data", data)
-
-	s.zapInstance.Info(
-		message,
-		appName,
-		version,
-		logType,
-		zap.Int("alert", int(alertLevel)),
-		dataField,
-	)
 
+	ce.Write(s.envelope(alertLevel, data)...)
+}
+
+func (r *HTTPRequestInfo) omitBody() {
+	r.BodyOmitted = r.Body != "" || r.bodyReader != nil
+	r.Body, r.bodyReader = "", nil
+}
+
+func (r *HTTPResponseInfo) omitBody() {
+	r.BodyOmitted = r.Body != "" || r.bodyReader != nil
+	r.Body, r.bodyReader = "", nil
+}
+
+// durationLevel returns the level of a log_type whose operation took durationMs,
+// escalating to warn and flagging data as slow once the configured threshold is exceeded.
+func (s SukiLogger) durationLevel(logType string, durationMs float64, data map[string]interface{}) zapcore.Level {
+	threshold, ok := s.config.SlowThresholdMs[logType]
+	if !ok || durationMs <= threshold {
+		return zapcore.InfoLevel
+	}
+
+	data["slow"] = true
+	return zapcore.WarnLevel
 }
 
 func (s SukiLogger) Event(message string, event EventLog, args ...interface{}) {
-	appName := zap.String("app_name", s.config.AppName)
-	version := zap.String("version", s.config.Version)
-	logType := zap.String("log_type", "event")
 	data := make(map[string]interface{})
-	alertLevel := LevelNone
+	alertLevel := s.requestArgs(data, args)
 
-	for i, _ := range args {
+	data["event"] = event
+
+	if s.config.CloudEvents.Enabled && s.cloudEventsInstance != nil {
+		if ce := s.sample(s.cloudEventLogger(alertLevel), "event", alertLevel, zapcore.InfoLevel, message, data).Check(zapcore.InfoLevel, message); ce != nil {
+			s.cloudEvent(ce, alertLevel, event, data)
+		}
+		return
+	}
+
+	if ce := s.sampledLogger("event", alertLevel, zapcore.InfoLevel, message, data).Check(zapcore.InfoLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// requestArgs adds the tracing found in args to data and returns the alert level of the log.
+func (s SukiLogger) requestArgs(data map[string]interface{}, args []interface{}) AlertLevel {
+	alertLevel := s.defaultOption.Alert
+
+	for i := range args {
 		if tracing, ok := args[i].(TraceInfo); ok {
 			data["tracing"] = TraceInfo{
-				TraceID: tracing.TraceID,
-				SpanID:  tracing.SpanID,
+				TraceID:         tracing.TraceID,
+				SpanID:          tracing.SpanID,
+				ParentRequestID: tracing.ParentRequestID,
 			}
 		} else if opts, ok := args[i].(LogOption); ok {
 			alertLevel = opts.Alert
+		} else if experiment, ok := args[i].(Experiment); ok {
+			addExperiment(data, experiment)
+		} else if audit, ok := args[i].(BodyAudit); ok {
+			data["body_audit"] = audit
+		} else if user, ok := args[i].(UserInfo); ok {
+			data["user"] = user
+		} else if ctx, ok := args[i].(context.Context); ok {
+			contextArgs(data, ctx)
+		} else if field, ok := args[i].(LogField); ok {
+			if cause, ok := field.Value.(CauseInfo); ok {
+				data["cause"] = cause
+			}
 		}
 	}
 
-	data["event"] = event
-	dataField := zap.Any("data", data)
-
-	s.zapInstance.Info(
-		message,
-		appName,
-		version,
-		logType,
-		zap.Int("alert", int(alertLevel)),
-		dataField,
-	)
+	return alertLevel
+}
 
+func addExperiment(data map[string]interface{}, experiment Experiment) {
+	experiments, _ := data["experiment"].([]Experiment)
+	data["experiment"] = append(experiments, experiment)
 }
 
-func (s SukiLogger) Audit() {
+// zapLogger returns the logger writing logs of alertLevel, alerting logs bypass
+// sampling so they are never dropped.
+func (s SukiLogger) zapLogger(alertLevel AlertLevel) *zap.Logger {
+	if alertLevel >= LevelAlert && s.alertInstance != nil {
+		return s.alertInstance
+	}
+	return s.zapInstance
+}
 
+// logTypes are the log types written by the logger, their static envelope
+// fields are encoded once by buildEnvelopes rather than on every log.
+var logTypes = []string{
+	"application",
+	"event",
+	"event_batch",
+	"handler.http",
+	"handler.kafka",
+	"client.http",
+	"client.db",
+	"slow_query",
+	"timer",
+	"operation_summary",
+	"drift",
+	"deprecation",
+	"notification",
+	"authz",
+	"batch",
+	"usage",
+	"cache_stats",
+	"request_trace",
+	"config",
+	"payment",
+	"rollout",
+	"heartbeat",
+	"audit",
+	"webhook",
+	"lock",
+	"summary",
+	"migration",
+	"anomaly",
+	"pool_stats",
+	"consumer.job",
 }
 
-func (s SukiLogger) appLogBuilder(args ...interface{}) []zap.Field {
-	appName := zap.String("app_name", s.config.AppName)
-	version := zap.String("version", s.config.Version)
-	logType := zap.String("log_type", "application")
+type envelopeKey struct {
+	logType string
+	alert   bool
+}
+
+// buildEnvelopes derives a child logger per log type carrying the fields that
+// never change after Configure.
+func (s *SukiLogger) buildEnvelopes() {
+	s.envelopes = make(map[envelopeKey]*zap.Logger, 2*len(logTypes))
+	for _, logType := range logTypes {
+		s.envelopes[envelopeKey{logType, false}] = s.zapInstance.With(s.staticFields(logType)...)
+		s.envelopes[envelopeKey{logType, true}] = s.alertInstance.With(s.staticFields(logType)...)
+	}
+}
+
+func (s SukiLogger) staticFields(logType string) []zap.Field {
+	return []zap.Field{
+		zap.String(s.config.FieldPrefix+"app_name", s.config.AppName),
+		zap.String(s.config.FieldPrefix+"version", s.config.Version),
+		zap.String(s.config.FieldPrefix+"log_type", logType),
+	}
+}
+
+// envelopeLogger returns the logger writing logs of logType, unsampled for alerting logs.
+func (s SukiLogger) envelopeLogger(logType string, alertLevel AlertLevel) *zap.Logger {
+	if logger, ok := s.envelopes[envelopeKey{logType, alertLevel >= LevelAlert}]; ok {
+		return logger
+	}
+	return s.zapLogger(alertLevel).With(s.staticFields(logType)...)
+}
+
+// envelope returns the fields written on every log following the static fields
+// of the envelopeLogger.
+func (s SukiLogger) envelope(alertLevel AlertLevel, data map[string]interface{}) []zap.Field {
+	if s.config.OmitEmpty {
+		data = s.omitEmpty(data)
+	}
+	data = s.config.redactPaths(data)
+	data = s.config.allowFields(data)
+	if s.config.MeasureEncoding {
+		data = measureEncoding(data)
+	}
+
+	return []zap.Field{
+		zap.Int(s.config.FieldPrefix+"alert", int(alertLevel)),
+		zap.Any(s.config.FieldPrefix+"data", data),
+	}
+}
+
+// appLogBuilder returns the logger of an application log along with its alert
+// level and data, the envelope is only built once the log is checked.
+func (s SukiLogger) appLogBuilder(level zapcore.Level, message string, args ...interface{}) (*zap.Logger, AlertLevel, map[string]interface{}) {
 	data := make(map[string]interface{})
 
 	appKey := s.config.AppName
@@ -409,128 +901,351 @@ func (s SukiLogger) appLogBuilder(args ...interface{}) []zap.Field {
 	}
 
 	appData := make(map[string]interface{})
-	alertLevel := LevelNone
+	alertLevel := s.defaultOption.Alert
 
-	for i, _ := range args {
+	for i := range args {
 		if field, ok := args[i].(TraceInfo); ok {
 			data["tracing"] = field
 		} else if field, ok := args[i].(LogField); ok {
-			if val, ok := field.Value.(error); ok {
-				appData[field.Key] = val.Error()
-			} else {
-				appData[field.Key] = field.Value
+			addAppField(appData, field)
+		} else if fields, ok := args[i].([]LogField); ok {
+			for _, field := range fields {
+				addAppField(appData, field)
 			}
 		} else if opts, ok := args[i].(LogOption); ok {
 			alertLevel = opts.Alert
+		} else if experiment, ok := args[i].(Experiment); ok {
+			addExperiment(data, experiment)
+		} else if user, ok := args[i].(UserInfo); ok {
+			data["user"] = user
+		} else if ctx, ok := args[i].(context.Context); ok {
+			contextArgs(data, ctx)
 		}
 	}
 
+	for k, v := range appData {
+		appData[k] = safeValue{key: k, value: v, report: s.internalError}
+	}
+
 	if len(appData) > 0 {
 		data[appKey] = appData
 	}
 
-	dataField := zap.Any("data", data)
-
-	return []zap.Field{
-		appName,
-		version,
-		logType,
-		zap.Int("alert", int(alertLevel)),
-		dataField,
-	}
+	return s.sampledLogger("application", alertLevel, level, message, data), alertLevel, data
 }
 
 func (s SukiLogger) Info(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-
-	s.zapInstance.Info(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.InfoLevel, message, args...)
+	if ce := logger.Check(zapcore.InfoLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) Debug(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-	s.zapInstance.Debug(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.DebugLevel, message, args...)
+	if ce := logger.Check(zapcore.DebugLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) Error(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-	s.zapInstance.Error(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.ErrorLevel, message, args...)
+	if ce := logger.Check(zapcore.ErrorLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) Warn(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-	s.zapInstance.Warn(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.WarnLevel, message, args...)
+	if ce := logger.Check(zapcore.WarnLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) Panic(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-	s.zapInstance.Panic(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.PanicLevel, message, args...)
+	if ce := logger.Check(zapcore.PanicLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
 func (s SukiLogger) Fatal(message string, args ...interface{}) {
-	result := s.appLogBuilder(args...)
-	s.zapInstance.Fatal(
-		message,
-		result...,
-	)
+	logger, alertLevel, data := s.appLogBuilder(zapcore.FatalLevel, message, args...)
+	if ce := logger.Check(zapcore.FatalLevel, message); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
 }
 
-func (s *SukiLogger) Configure(c Config) error {
+func newZapConfig(level zapcore.Level, c Config) zap.Config {
 	config := zap.NewProductionConfig()
 	config.EncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
 	config.EncoderConfig.MessageKey = "message"
 	config.EncoderConfig.TimeKey = "timestamp"
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	config.Level = zap.NewAtomicLevelAt(zapcore.Level(c.LogLevel))
+	config.Level = zap.NewAtomicLevelAt(level)
+	if c.DisableSampling {
+		config.Sampling = nil
+	}
 
-	logger, err := config.Build(zap.AddCallerSkip(1))
+	if c.CallerKey != "" {
+		config.EncoderConfig.CallerKey = c.CallerKey
+	}
+	if c.FunctionKey != "" {
+		config.EncoderConfig.FunctionKey = c.FunctionKey
+	}
+	if c.StacktraceKey != "" {
+		config.EncoderConfig.StacktraceKey = c.StacktraceKey
+	}
+	if len(c.LevelNames) > 0 {
+		config.EncoderConfig.EncodeLevel = levelNameEncoder(c.LevelNames)
+	}
+	if c.LineEnding != "" {
+		config.EncoderConfig.LineEnding = c.LineEnding
+	}
+
+	for _, key := range []*string{
+		&config.EncoderConfig.MessageKey,
+		&config.EncoderConfig.LevelKey,
+		&config.EncoderConfig.TimeKey,
+		&config.EncoderConfig.NameKey,
+		&config.EncoderConfig.CallerKey,
+		&config.EncoderConfig.FunctionKey,
+		&config.EncoderConfig.StacktraceKey,
+	} {
+		if *key != "" {
+			*key = c.FieldPrefix + *key
+		}
+	}
+
+	return config
+}
+
+// levelNameEncoder writes the name of a level found in names, its lowercase name otherwise.
+func levelNameEncoder(levelNames map[LogLevel]string) zapcore.LevelEncoder {
+	names := make(map[LogLevel]string, len(levelNames))
+	for level, name := range levelNames {
+		names[level] = name
+	}
+
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if name, ok := names[LogLevel(l)]; ok {
+			enc.AppendString(name)
+			return
+		}
+		zapcore.LowercaseLevelEncoder(l, enc)
+	}
+}
+
+// newZapLogger builds the same logger as zap.Config.Build but writing to sink,
+// along with an unsampled logger sharing its output for alerting logs and an
+// unsampled one writing every level for debug requests.
+func newZapLogger(config zap.Config, c Config, sink zapcore.WriteSyncer, errorOutput zapcore.WriteSyncer, subs *subscribers, caps *captures, started time.Time) (*zap.Logger, *zap.Logger, *zap.Logger) {
+	encoder := zapcore.NewJSONEncoder(config.EncoderConfig)
+	if c.StackTraceSeparator != "" {
+		encoder = singleLineStackEncoder{Encoder: encoder, config: c}
+	}
+	if c.MessagePrefix != "" {
+		encoder = messagePrefixEncoder{Encoder: encoder, prefix: c.MessagePrefix}
+	}
+	if c.IncludeMonotonic {
+		encoder = monotonicEncoder{Encoder: encoder, key: c.FieldPrefix + "monotonic_ms", start: c.clock().Now()}
+	}
+	if c.IncludeUptime {
+		encoder = uptimeEncoder{Encoder: encoder, key: c.FieldPrefix + "uptime_seconds", start: started}
+	}
+	if c.IncludeLineSize {
+		encoder = lineSizeEncoder{Encoder: encoder, key: c.FieldPrefix + "log_size_bytes"}
+	}
+
+	core := zapcore.NewTee(
+		zapcore.NewCore(encoder, sink, config.Level),
+		newSubscriberCore(config.Level, subs, c.FieldPrefix),
+		newCaptureCore(encoder, caps, c.FieldPrefix),
+	)
+
+	// The logs sampled by SampleKeys are sampled by SukiLogger.check instead.
+	sampled := core
+	if config.Sampling != nil && len(c.SampleKeys) == 0 {
+		sampled = zapcore.NewSamplerWithOptions(core, time.Second, config.Sampling.Initial, config.Sampling.Thereafter)
+	}
+
+	opts := []zap.Option{
+		zap.ErrorOutput(errorOutput),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+		zap.AddCallerSkip(1),
+		zap.WithClock(zapClock{Clock: c.clock()}),
+	}
+	debug := zapcore.NewTee(
+		zapcore.NewCore(encoder, sink, zapcore.DebugLevel),
+		newSubscriberCore(zapcore.DebugLevel, subs, c.FieldPrefix),
+		newCaptureCore(encoder, caps, c.FieldPrefix),
+	)
+	return zap.New(sampled, opts...), zap.New(core, opts...), zap.New(debug, opts...)
+}
+
+func (s *SukiLogger) Configure(c Config) error {
+	c = withBuildInfo(c)
+	if err := c.RedactionRules.compile(); err != nil {
+		return err
+	}
+	if c.LineEnding != "" && c.LineEnding != "\n" && c.LineEnding != "\r\n" {
+		return ErrInvalidLineEnding
+	}
+	config := newZapConfig(zapcore.Level(c.LogLevel), c)
+
+	errorOutput, _, err := zap.Open(config.ErrorOutputPaths...)
 	if err != nil {
 		return err
 	}
+
+	var sink zapcore.WriteSyncer
+	if c.Output != nil {
+		sink = newFallbackSink(zapcore.AddSync(c.Output), zapcore.Lock(os.Stderr), func(err error) {
+			if c.OnWriteError != nil {
+				c.OnWriteError(err)
+				return
+			}
+			s.internalError(fmt.Errorf("writing to Output failed, falling back to stderr: %w", err))
+		})
+	} else if sink, _, err = zap.Open(config.OutputPaths...); err != nil {
+		return err
+	}
+
+	previousAsync := s.async
+	s.async = c.Async.buffer(sink, c.clock())
+	if s.async != nil {
+		sink = s.async
+	}
+
+	if s.subscribers == nil {
+		s.subscribers = &subscribers{}
+	}
+	if s.deprecations == nil {
+		s.deprecations = &deprecations{}
+	}
+	if s.captures == nil {
+		s.captures = &captures{}
+	}
+	if s.heartbeats == nil {
+		s.heartbeats = &heartbeats{}
+	}
+	if s.durationStats == nil {
+		s.durationStats = &durationStats{}
+	}
+	if s.started.IsZero() {
+		s.started = c.clock().Now()
+	}
+
+	logger, alertLogger, debugLogger := newZapLogger(config, c, sink, errorOutput, s.subscribers, s.captures, s.started)
 	defer logger.Sync()
 
 	s.zapInstance = logger
+	s.alertInstance = alertLogger
+	s.debugInstance = debugLogger
+	s.sampler = newKeySampler(c, config.Sampling)
+	s.cloudEventsInstance, s.cloudEventsAlertInstance = nil, nil
+	s.errorOutput = errorOutput
 	s.config = c
+	if c.CloudEvents.Enabled {
+		cloudEvents, cloudEventsAlert := newCloudEventsLogger(config, c, sink, errorOutput, s.subscribers, s.captures)
+		s.cloudEventsInstance = cloudEvents.With(s.staticFields("event")...)
+		s.cloudEventsAlertInstance = cloudEventsAlert.With(s.staticFields("event")...)
+	}
+	s.buildEnvelopes()
+
+	if c.RedactionRules.Version != 0 {
+		s.Info(
+			"redaction rules loaded",
+			Any("redaction_rules_version", c.RedactionRules.Version),
+			Any("redaction_rules_fingerprint", c.RedactionRules.Fingerprint()),
+		)
+	}
+	if !c.SuppressConfigWarnings {
+		s.warnConfigIssues()
+	}
+	if previousAsync != nil {
+		previousAsync.Stop()
+	}
 	return nil
 }
 
-func L() *SukiLogger {
-	if sukiLogger == nil {
-		config := zap.NewProductionConfig()
-		config.EncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
-		config.EncoderConfig.MessageKey = "message"
-		config.EncoderConfig.TimeKey = "timestamp"
-		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-		config.Level = zap.NewAtomicLevelAt(zapcore.FatalLevel)
+// Rotate syncs and rotates the configured Output, it returns ErrNotRotatable
+// when Output is not a Rotator such as *lumberjack.Logger.
+func (s *SukiLogger) Rotate() error {
+	r, ok := s.config.Output.(Rotator)
+	if !ok {
+		return ErrNotRotatable
+	}
 
-		logger, _ := config.Build(zap.AddCallerSkip(1))
+	if err := s.zapInstance.Sync(); err != nil {
+		return err
+	}
+	return r.Rotate()
+}
 
-		sukiLogger = &SukiLogger{zapInstance: logger}
+// Close stops the heartbeats started by StartHeartbeat, syncs the output and
+// stops flushing it in the background when Async is enabled.
+func (s *SukiLogger) Close() error {
+	s.heartbeats.stopAll()
+	if err := s.zapInstance.Sync(); err != nil {
+		return err
+	}
+	if s.async != nil {
+		return s.async.Stop()
+	}
+	return nil
+}
+
+func L() *SukiLogger {
+	if sukiLogger == nil {
+		config := newZapConfig(zapcore.FatalLevel, Config{})
+		stderr := zapcore.Lock(os.Stderr)
+		subs := &subscribers{}
+		caps := &captures{}
+		started := time.Now()
+
+		logger, alertLogger, debugLogger := newZapLogger(config, Config{}, stderr, stderr, subs, caps, started)
+
+		sukiLogger = &SukiLogger{
+			config:        withBuildInfo(Config{}),
+			zapInstance:   logger,
+			alertInstance: alertLogger,
+			debugInstance: debugLogger,
+			errorOutput:   stderr,
+			subscribers:   subs,
+			captures:      caps,
+			heartbeats:    &heartbeats{},
+			deprecations:  &deprecations{},
+			durationStats: &durationStats{},
+			started:       started,
+		}
+		sukiLogger.buildEnvelopes()
 	}
 	return sukiLogger
 }
 
 func NewProductionConfig() Config {
 
-	config := Config{
+	config := withBuildInfo(Config{
 		LogLevel:    LevelInfo,
-		AppName:     "application",
-		Version:     "1.0.0",
 		MaxBodySize: 1048576,
+		// A day, anything longer is most likely a duration in the wrong unit.
+		MaxPlausibleDurationMs: 86400000,
+		BatchErrorRatio:        0.5,
+		AnomalyDeviation:       0.5,
+	})
+	if config.AppName == "" {
+		config.AppName = "application"
+	}
+	if config.Version == "" {
+		config.Version = "1.0.0"
 	}
 
 	return config
@@ -1,12 +1,47 @@
 package slog
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
+// newTestLogger returns a logger configured with c writing to the returned buffer.
+func newTestLogger(c Config) (*SukiLogger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	c.Output = buf
+
+	logger := &SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		panic(err)
+	}
+	return logger, buf
+}
+
+// decodeLines parses every JSON log line written to buf.
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
 func TestError(t *testing.T) {
 	type args struct {
 		err error
@@ -225,3 +260,791 @@ func TestWithTracing(t *testing.T) {
 		})
 	}
 }
+
+func TestSlowThresholdPerLogType(t *testing.T) {
+	config := NewProductionConfig()
+	config.SlowThresholdMs = map[string]float64{
+		"handler.http":  500,
+		"handler.kafka": 50,
+	}
+
+	tests := []struct {
+		name      string
+		log       func(s *SukiLogger)
+		wantLevel string
+		wantSlow  bool
+	}{
+		{
+			name: "HTTP below its threshold",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, 100, ""))
+			},
+			wantLevel: "info",
+		},
+		{
+			name: "HTTP above its threshold",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, 501, ""))
+			},
+			wantLevel: "warn",
+			wantSlow:  true,
+		},
+		{
+			name: "Kafka uses its own threshold",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("kafka", KafkaMessage{}, WithKafkaResult(100))
+			},
+			wantLevel: "warn",
+			wantSlow:  true,
+		},
+		{
+			name: "Kafka below its threshold",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("kafka", KafkaMessage{}, WithKafkaResult(10))
+			},
+			wantLevel: "info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(config)
+			tt.log(logger)
+
+			line := decodeLines(t, buf)[0]
+			if line["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %v", line["level"], tt.wantLevel)
+			}
+			_, slow := line["data"].(map[string]interface{})["slow"]
+			if slow != tt.wantSlow {
+				t.Errorf("slow = %v, want %v", slow, tt.wantSlow)
+			}
+		})
+	}
+}
+
+func TestSlowThresholdUnconfiguredType(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, 100000, ""))
+
+	if line := decodeLines(t, buf)[0]; line["level"] != "info" {
+		t.Errorf("level = %v, want info", line["level"])
+	}
+}
+
+// rotatingFile is a minimal file sink that moves the current file aside on Rotate.
+type rotatingFile struct {
+	path    string
+	file    *os.File
+	rotated int
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	return r.file.Write(p)
+}
+
+func (r *rotatingFile) Rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.rotated++
+	if err := os.Rename(r.path, fmt.Sprintf("%s.%d", r.path, r.rotated)); err != nil {
+		return err
+	}
+
+	file, err := os.Create(r.path)
+	r.file = file
+	return err
+}
+
+func TestRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &rotatingFile{path: path, file: file}
+	defer sink.file.Close()
+
+	config := NewProductionConfig()
+	config.Output = sink
+	logger := &SukiLogger{}
+	if err := logger.Configure(config); err != nil {
+		t.Fatal(err)
+	}
+
+	logger.Info("before rotate")
+	if err := logger.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	logger.Info("after rotate")
+
+	rotated, _ := os.ReadFile(path + ".1")
+	current, _ := os.ReadFile(path)
+	if !strings.Contains(string(rotated), "before rotate") || strings.Contains(string(rotated), "after rotate") {
+		t.Errorf("rotated file = %q", rotated)
+	}
+	if !strings.Contains(string(current), "after rotate") || strings.Contains(string(current), "before rotate") {
+		t.Errorf("current file = %q", current)
+	}
+}
+
+func TestRotateWithoutRotatableOutput(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+
+	if err := logger.Rotate(); err != ErrNotRotatable {
+		t.Errorf("Rotate() error = %v, want %v", err, ErrNotRotatable)
+	}
+}
+
+func TestRequestKafkaDeserializationError(t *testing.T) {
+	type order struct {
+		ID int `json:"id"`
+	}
+
+	payload := `{"id": "not a number"`
+	var target order
+	err := json.Unmarshal([]byte(payload), &target)
+	if err == nil {
+		t.Fatal("payload should be malformed")
+	}
+
+	config := NewProductionConfig()
+	config.MaxBodySize = 8
+	logger, buf := newTestLogger(config)
+
+	logger.RequestKafka(
+		"consume order",
+		WithKafkaMessage("orders", 0, 1, nil, "", payload, time.Time{}),
+		WithKafkaResult(1),
+		WithDeserializationError(err, payload, &target),
+	)
+
+	line := decodeLines(t, buf)[0]
+	if line["level"] != "error" {
+		t.Errorf("level = %v, want error", line["level"])
+	}
+
+	data := line["data"].(map[string]interface{})
+	want := map[string]interface{}{
+		"message":           err.Error(),
+		"target_type":       "*slog.order",
+		"payload":           `{"id": "`,
+		"payload_truncated": true,
+	}
+	if !reflect.DeepEqual(data["deserialization_error"], want) {
+		t.Errorf("deserialization_error = %v, want %v", data["deserialization_error"], want)
+	}
+	if name := data["kafka_result"].(map[string]interface{})["error"].(map[string]interface{})["name"]; name != "deserialization_error" {
+		t.Errorf("kafka_result.error.name = %v, want deserialization_error", name)
+	}
+}
+
+func TestRequestKafkaDeserializationErrorLimit(t *testing.T) {
+	config := NewProductionConfig()
+	config.MaxBodySize = 0
+	config.MaxRequestBodySize = 3
+	logger, buf := newTestLogger(config)
+
+	payload := `"สวัสดี"`
+	logger.RequestKafka("consume", KafkaMessage{}, WithKafkaResult(1), WithDeserializationError(errors.New("invalid"), payload, struct{}{}))
+
+	got := decodeLines(t, buf)[0]["data"].(map[string]interface{})["deserialization_error"].(map[string]interface{})
+	if got["payload"] != `"` || got["payload_truncated"] != true {
+		t.Errorf("payload, payload_truncated = %q, %v, want %q, true", got["payload"], got["payload_truncated"], `"`)
+	}
+}
+
+// marshalCounter counts how many times it is encoded.
+type marshalCounter struct {
+	calls *int
+}
+
+func (m marshalCounter) MarshalJSON() ([]byte, error) {
+	*m.calls++
+	return []byte(`{"secret":"s"}`), nil
+}
+
+func TestAppLogEnvelopeBuiltOnlyWhenWritten(t *testing.T) {
+	config := NewProductionConfig()
+	config.AppName = "shop"
+	config.LogLevel = LevelInfo
+	config.RedactPaths = []string{"shop.order.secret"}
+	logger, buf := newTestLogger(config)
+
+	calls := 0
+	logger.Debug("filtered out", Any("order", marshalCounter{calls: &calls}))
+	if calls != 0 || buf.Len() != 0 {
+		t.Errorf("disabled debug log encoded its data %d times, wrote %q", calls, buf.String())
+	}
+
+	logger.Info("written", Any("order", marshalCounter{calls: &calls}))
+	if calls == 0 {
+		t.Error("written log did not encode its data")
+	}
+}
+
+func TestAlertLogsBypassSampling(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	for i := 0; i < 1000; i++ {
+		logger.Error("payment gateway down", WithOption(LogOption{Alert: LevelAlert}))
+		logger.Error("noisy error")
+	}
+
+	alerts, others := 0, 0
+	for _, line := range decodeLines(t, buf) {
+		if line["alert"] == float64(LevelAlert) {
+			alerts++
+		} else {
+			others++
+		}
+	}
+	if alerts != 1000 {
+		t.Errorf("wrote %d alert logs, want 1000", alerts)
+	}
+	if others >= 1000 {
+		t.Errorf("wrote %d non-alert logs, want them sampled", others)
+	}
+}
+
+func TestDurationAnomaly(t *testing.T) {
+	tests := []struct {
+		name         string
+		log          func(s *SukiLogger)
+		key          string
+		wantDuration float64
+		wantAnomaly  interface{}
+	}{
+		{
+			name: "Negative HTTP duration is clamped",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, -3, ""))
+			},
+			key:          "http_response",
+			wantDuration: 0,
+			wantAnomaly:  DurationNegative,
+		},
+		{
+			name: "Implausible HTTP duration is kept",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(200, 9.2e12, ""))
+			},
+			key:          "http_response",
+			wantDuration: 9.2e12,
+			wantAnomaly:  DurationImplausible,
+		},
+		{
+			name: "Negative Kafka duration is clamped",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("kafka", KafkaMessage{}, WithKafkaResult(-1))
+			},
+			key:          "kafka_result",
+			wantDuration: 0,
+			wantAnomaly:  DurationNegative,
+		},
+		{
+			name: "Implausible Kafka duration is kept",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("kafka", KafkaMessage{}, WithKafkaResult(9.2e12))
+			},
+			key:          "kafka_result",
+			wantDuration: 9.2e12,
+			wantAnomaly:  DurationImplausible,
+		},
+		{
+			name: "Clean duration adds no field",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponseDuration(200, 1500*time.Microsecond, ""))
+			},
+			key:          "http_response",
+			wantDuration: 1.5,
+			wantAnomaly:  nil,
+		},
+		{
+			name: "Duration typed Kafka result",
+			log: func(s *SukiLogger) {
+				s.RequestKafka("kafka", KafkaMessage{}, WithKafkaResultDuration(2*time.Second))
+			},
+			key:          "kafka_result",
+			wantDuration: 2000,
+			wantAnomaly:  nil,
+		},
+		{
+			name: "Struct literal HTTP duration is checked when written",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, HTTPResponseInfo{Status: 200, Duration: -2})
+			},
+			key:          "http_response",
+			wantDuration: 0,
+			wantAnomaly:  DurationNegative,
+		},
+		{
+			name: "Negative DB duration is clamped",
+			log: func(s *SukiLogger) {
+				s.RequestDB("db", WithDBQuery("postgresql", "SELECT 1"), DBResult{Duration: -1})
+			},
+			key:          "db_result",
+			wantDuration: 0,
+			wantAnomaly:  DurationNegative,
+		},
+		{
+			name: "Implausible consumer job duration is kept",
+			log: func(s *SukiLogger) {
+				s.ConsumeJob("job", ConsumerJobInfo{}, WithConsumerJobResult(JobProcessed, 9.2e12))
+			},
+			key:          "consumer_job_result",
+			wantDuration: 9.2e12,
+			wantAnomaly:  DurationImplausible,
+		},
+		{
+			name: "Negative webhook duration is clamped",
+			log: func(s *SukiLogger) {
+				s.Webhook(WebhookInfo{EventType: "order.created", Status: 200, Duration: -4})
+			},
+			key:          "webhook",
+			wantDuration: 0,
+			wantAnomaly:  DurationNegative,
+		},
+		{
+			name: "Implausible migration duration is kept",
+			log: func(s *SukiLogger) {
+				s.Migration(WithMigration("42", MigrationUp, 9.2e12, MigrationSucceeded))
+			},
+			key:          "migration",
+			wantDuration: 9.2e12,
+			wantAnomaly:  DurationImplausible,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			tt.log(logger)
+
+			result := decodeLines(t, buf)[0]["data"].(map[string]interface{})[tt.key].(map[string]interface{})
+			if result["duration"] != tt.wantDuration {
+				t.Errorf("duration = %v, want %v", result["duration"], tt.wantDuration)
+			}
+			if result["duration_anomaly"] != tt.wantAnomaly {
+				t.Errorf("duration_anomaly = %v, want %v", result["duration_anomaly"], tt.wantAnomaly)
+			}
+		})
+	}
+}
+
+func TestWithExperiment(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(s *SukiLogger)
+		want interface{}
+	}{
+		{
+			name: "Single experiment",
+			log: func(s *SukiLogger) {
+				s.Info("checkout", WithExperiment("new-checkout", "b"))
+			},
+			want: []interface{}{
+				map[string]interface{}{"id": "new-checkout", "variant": "b"},
+			},
+		},
+		{
+			name: "Multiple experiments on a request log",
+			log: func(s *SukiLogger) {
+				s.RequestHTTP("http", HTTPRequestInfo{}, HTTPResponseInfo{}, WithExperiment("new-checkout", "b"), WithExperiment("price-test", "control"))
+			},
+			want: []interface{}{
+				map[string]interface{}{"id": "new-checkout", "variant": "b"},
+				map[string]interface{}{"id": "price-test", "variant": "control"},
+			},
+		},
+		{
+			name: "No experiment",
+			log: func(s *SukiLogger) {
+				s.Info("checkout")
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+			tt.log(logger)
+
+			got, ok := decodeLines(t, buf)[0]["data"].(map[string]interface{})["experiment"]
+			if tt.want == nil && ok {
+				t.Errorf("experiment should be omitted, got %v", got)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("experiment = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldPrefix(t *testing.T) {
+	c := NewProductionConfig()
+	c.FieldPrefix = "suki_"
+	logger, buf := newTestLogger(c)
+
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	logger.Info("info", Any("a", 1))
+	logger.Error("error")
+	logger.RequestHTTP("http", WithHTTPRequest("GET", "/", "", nil, nil, nil, ""), WithHTTPResponse(200, 1, ""))
+
+	for _, line := range decodeLines(t, buf) {
+		for key := range line {
+			if !strings.HasPrefix(key, "suki_") {
+				t.Errorf("field %v of %v is not prefixed", key, line["suki_message"])
+			}
+		}
+		for _, key := range []string{"suki_level", "suki_timestamp", "suki_caller", "suki_message", "suki_app_name", "suki_version", "suki_log_type", "suki_alert", "suki_data"} {
+			if _, ok := line[key]; !ok {
+				t.Errorf("field %v is missing from %v", key, line)
+			}
+		}
+	}
+
+	entry := <-entries
+	if entry.AppName != c.AppName || entry.LogType != "application" || entry.Data == nil {
+		t.Errorf("subscriber entry = %+v, want the unprefixed fields decoded", entry)
+	}
+}
+
+func TestEncoderKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      func(c *Config)
+		wantKeys    []string
+		notWantKeys []string
+	}{
+		{
+			name:        "Defaults",
+			config:      func(c *Config) {},
+			wantKeys:    []string{"caller", "stacktrace"},
+			notWantKeys: []string{"function"},
+		},
+		{
+			name: "Custom keys",
+			config: func(c *Config) {
+				c.CallerKey = "source"
+				c.FunctionKey = "func"
+				c.StacktraceKey = "stack"
+			},
+			wantKeys:    []string{"source", "func", "stack"},
+			notWantKeys: []string{"caller", "function", "stacktrace"},
+		},
+		{
+			name: "Custom keys with field prefix",
+			config: func(c *Config) {
+				c.FunctionKey = "function"
+				c.FieldPrefix = "suki_"
+			},
+			wantKeys: []string{"suki_caller", "suki_function", "suki_stacktrace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			tt.config(&c)
+			logger, buf := newTestLogger(c)
+
+			logger.Error("failed")
+
+			line := decodeLines(t, buf)[0]
+			for _, key := range tt.wantKeys {
+				if _, ok := line[key]; !ok {
+					t.Errorf("%v is missing from %v", key, line)
+				}
+			}
+			for _, key := range tt.notWantKeys {
+				if _, ok := line[key]; ok {
+					t.Errorf("%v should not be written", key)
+				}
+			}
+			if function, ok := line[c.FieldPrefix+c.FunctionKey].(string); ok && !strings.Contains(function, "TestEncoderKeys") {
+				t.Errorf("function = %v, want the test function", function)
+			}
+		})
+	}
+}
+
+func TestLevelNames(t *testing.T) {
+	names := map[LogLevel]string{
+		LevelWarn:  "WARNING",
+		LevelPanic: "CRITICAL",
+		LevelFatal: "CRITICAL",
+	}
+
+	tests := []struct {
+		name  string
+		names map[LogLevel]string
+		log   func(s *SukiLogger)
+		want  string
+	}{
+		{
+			name:  "Default lowercase names",
+			names: nil,
+			log:   func(s *SukiLogger) { s.Warn("message") },
+			want:  "warn",
+		},
+		{
+			name:  "Mapped level",
+			names: names,
+			log:   func(s *SukiLogger) { s.Warn("message") },
+			want:  "WARNING",
+		},
+		{
+			name:  "Unmapped level keeps its name",
+			names: names,
+			log:   func(s *SukiLogger) { s.Info("message") },
+			want:  "info",
+		},
+		{
+			name:  "Panic mapped",
+			names: names,
+			log: func(s *SukiLogger) {
+				defer func() { _ = recover() }()
+				s.Panic("message")
+			},
+			want: "CRITICAL",
+		},
+		{
+			name:  "Typed log",
+			names: names,
+			log:   func(s *SukiLogger) { s.Drift([]DriftInfo{WithDrift("service/orders", "timeout", "1s", "2s")}) },
+			want:  "WARNING",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.LevelNames = tt.names
+			logger, buf := newTestLogger(c)
+
+			tt.log(logger)
+
+			if level := decodeLines(t, buf)[0]["level"]; level != tt.want {
+				t.Errorf("level = %v, want %v", level, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxBodySizePerDirection(t *testing.T) {
+	request := WithHTTPRequest("POST", "/orders", "127.0.0.1", nil, nil, nil, "1234567890")
+	response := WithHTTPResponse(200, 1, "12345678901234567890")
+
+	tests := []struct {
+		name                string
+		maxBodySize         int
+		maxRequestBodySize  int
+		maxResponseBodySize int
+		wantRequest         string
+		wantResponse        string
+	}{
+		{
+			name:         "Fallback to MaxBodySize",
+			maxBodySize:  15,
+			wantRequest:  "1234567890",
+			wantResponse: "body is too large",
+		},
+		{
+			name:                "Larger response limit",
+			maxBodySize:         5,
+			maxResponseBodySize: 20,
+			wantRequest:         "body is too large",
+			wantResponse:        "12345678901234567890",
+		},
+		{
+			name:                "Both limits set",
+			maxBodySize:         100,
+			maxRequestBodySize:  5,
+			maxResponseBodySize: 10,
+			wantRequest:         "body is too large",
+			wantResponse:        "body is too large",
+		},
+		{
+			name:               "Request limit only",
+			maxRequestBodySize: 5,
+			wantRequest:        "body is too large",
+			wantResponse:       "12345678901234567890",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.MaxBodySize = tt.maxBodySize
+			c.MaxRequestBodySize = tt.maxRequestBodySize
+			c.MaxResponseBodySize = tt.maxResponseBodySize
+			logger, buf := newTestLogger(c)
+
+			logger.RequestHTTP("http", request, response)
+			logger.RequestHTTP("http", request.WithBodyReader(strings.NewReader("1234567890")), response.WithBodyReader(strings.NewReader("12345678901234567890")))
+
+			lines := decodeLines(t, buf)
+			data := lines[0]["data"].(map[string]interface{})
+			if body := data["http_request"].(map[string]interface{})["body"]; body != tt.wantRequest {
+				t.Errorf("request body = %v, want %v", body, tt.wantRequest)
+			}
+			if body := data["http_response"].(map[string]interface{})["body"]; body != tt.wantResponse {
+				t.Errorf("response body = %v, want %v", body, tt.wantResponse)
+			}
+
+			// Body readers are cut at the limit of their direction.
+			data = lines[1]["data"].(map[string]interface{})
+			requestTruncated := data["http_request"].(map[string]interface{})["body_truncated"] == true
+			responseTruncated := data["http_response"].(map[string]interface{})["body_truncated"] == true
+			if requestTruncated != (tt.wantRequest == "body is too large") || responseTruncated != (tt.wantResponse == "body is too large") {
+				t.Errorf("body_truncated = %v, %v for the body readers", requestTruncated, responseTruncated)
+			}
+		})
+	}
+}
+
+func TestLineEnding(t *testing.T) {
+	tests := []struct {
+		name       string
+		lineEnding string
+		wantEnding string
+		wantErr    error
+	}{
+		{
+			name:       "Default",
+			wantEnding: "}\n",
+		},
+		{
+			name:       "LF",
+			lineEnding: "\n",
+			wantEnding: "}\n",
+		},
+		{
+			name:       "CRLF",
+			lineEnding: "\r\n",
+			wantEnding: "}\r\n",
+		},
+		{
+			name:       "CR",
+			lineEnding: "\r",
+			wantErr:    ErrInvalidLineEnding,
+		},
+		{
+			name:       "Not a line ending",
+			lineEnding: ";",
+			wantErr:    ErrInvalidLineEnding,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewProductionConfig()
+			c.LineEnding = tt.lineEnding
+			buf := &bytes.Buffer{}
+			c.Output = buf
+
+			logger := &SukiLogger{}
+			if err := logger.Configure(c); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Configure() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			logger.Info("first")
+			logger.Info("second")
+
+			lines := strings.SplitAfter(buf.String(), tt.wantEnding)
+			if len(lines) != 3 || lines[2] != "" {
+				t.Fatalf("output = %q, want 2 lines ending with %q", buf, tt.wantEnding)
+			}
+			if tt.wantEnding == "}\n" && strings.Contains(buf.String(), "\r") {
+				t.Errorf("output = %q, want no \\r", buf)
+			}
+		})
+	}
+}
+
+func TestWithDefaultOption(t *testing.T) {
+	tests := []struct {
+		name      string
+		defaults  LogOption
+		emit      func(s *SukiLogger)
+		wantAlert float64
+	}{
+		{
+			name:      "Default applies to application logs",
+			defaults:  LogOption{Alert: LevelAlert},
+			emit:      func(s *SukiLogger) { s.Info("application") },
+			wantAlert: 1,
+		},
+		{
+			name:     "Default applies to request logs",
+			defaults: LogOption{Alert: LevelAlert},
+			emit: func(s *SukiLogger) {
+				s.RequestDB("db query", WithDBQuery("postgresql", "SELECT 1"), WithDBResult(1, 1))
+			},
+			wantAlert: 1,
+		},
+		{
+			name:      "Per-call option overrides the default",
+			defaults:  LogOption{Alert: LevelAlert},
+			emit:      func(s *SukiLogger) { s.Info("application", WithOption(LogOption{Alert: LevelNone})) },
+			wantAlert: 0,
+		},
+		{
+			name:     "Per-call option overrides the default of request logs",
+			defaults: LogOption{Alert: LevelAlert},
+			emit: func(s *SukiLogger) {
+				s.Event("event", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"), WithOption(LogOption{Alert: LevelNone}))
+			},
+			wantAlert: 0,
+		},
+		{
+			name:      "No default",
+			emit:      func(s *SukiLogger) { s.Info("application") },
+			wantAlert: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			tt.emit(logger.WithDefaultOption(tt.defaults))
+			logger.Info("parent")
+
+			lines := decodeLines(t, buf)
+			if lines[0]["alert"] != tt.wantAlert {
+				t.Errorf("alert = %v, want %v", lines[0]["alert"], tt.wantAlert)
+			}
+			if lines[1]["alert"] != float64(0) {
+				t.Errorf("parent alert = %v, want 0", lines[1]["alert"])
+			}
+		})
+	}
+}
+
+func TestParentRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		tracing TraceInfo
+		want    interface{}
+	}{
+		{name: "Parent set", tracing: WithTracing("trace", "span", "child").WithParentRequestID("parent"), want: "parent"},
+		{name: "Parent empty", tracing: WithTracing("trace", "span", "child"), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			logger.Info("app", tt.tracing)
+			logger.ClientHTTP("client", WithHTTPRequest("GET", "/", "", nil, nil, nil, ""), WithHTTPResponse(200, 1, ""), tt.tracing)
+
+			for _, line := range decodeLines(t, buf) {
+				tracing := line["data"].(map[string]interface{})["tracing"].(map[string]interface{})
+				parent, ok := tracing["parent_request_id"]
+				if parent != tt.want || ok != (tt.want != nil) {
+					t.Errorf("%v: parent_request_id = %v, want %v", line["message"], parent, tt.want)
+				}
+			}
+		})
+	}
+}
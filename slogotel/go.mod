@@ -0,0 +1,14 @@
+module github.com/Sellsuki/sellsuki-go-logger/slogotel
+
+go 1.22
+
+require (
+	github.com/Sellsuki/sellsuki-go-logger v1.2.0
+	go.opentelemetry.io/otel v1.28.0
+)
+
+require (
+	go.uber.org/atomic v1.7.0 // indirect
+	go.uber.org/multierr v1.6.0 // indirect
+	go.uber.org/zap v1.24.0 // indirect
+)
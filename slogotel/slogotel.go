@@ -0,0 +1,153 @@
+// Package slogotel converts the slog request structs to and from OpenTelemetry
+// attributes following the semantic conventions v1.26.0, so a single source of
+// truth can describe both spans and logs.
+//
+// It lives in its own module to keep the OpenTelemetry dependency out of the core logger.
+package slogotel
+
+import (
+	slog "github.com/Sellsuki/sellsuki-go-logger"
+	"go.opentelemetry.io/otel/attribute"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	HTTPRequestMethodKey       = attribute.Key("http.request.method")
+	URLPathKey                 = attribute.Key("url.path")
+	URLQueryKey                = attribute.Key("url.query")
+	ClientAddressKey           = attribute.Key("client.address")
+	HTTPResponseStatusCodeKey  = attribute.Key("http.response.status_code")
+	ErrorTypeKey               = attribute.Key("error.type")
+	MessagingSystemKey         = attribute.Key("messaging.system")
+	MessagingDestinationKey    = attribute.Key("messaging.destination.name")
+	MessagingPartitionKey      = attribute.Key("messaging.destination.partition.id")
+	MessagingKafkaOffsetKey    = attribute.Key("messaging.kafka.message.offset")
+	MessagingKafkaMessageKey   = attribute.Key("messaging.kafka.message.key")
+	httpRequestHeaderKeyPrefix = "http.request.header."
+)
+
+// AllowedRequestHeaders are the request headers exported as attributes by HTTPRequestAttributes,
+// the other headers may hold credentials such as Authorization or Cookie and are left out.
+// It can be extended at startup with the headers a service needs on its spans.
+var AllowedRequestHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Content-Length",
+	"Content-Type",
+	"User-Agent",
+	"X-Forwarded-For",
+	"X-Request-Id",
+}
+
+func allowedRequestHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, allowed := range AllowedRequestHeaders {
+		if http.CanonicalHeaderKey(allowed) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPRequestAttributes returns the attributes describing info, only the headers of
+// AllowedRequestHeaders are exported.
+func HTTPRequestAttributes(info slog.HTTPRequestInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		HTTPRequestMethodKey.String(info.Method),
+		URLPathKey.String(info.Path),
+	}
+
+	if info.RemoteIP != "" {
+		attrs = append(attrs, ClientAddressKey.String(info.RemoteIP))
+	}
+
+	if len(info.Query) > 0 {
+		query := url.Values{}
+		for k, v := range info.Query {
+			query.Set(k, v)
+		}
+		attrs = append(attrs, URLQueryKey.String(query.Encode()))
+	}
+
+	keys := make([]string, 0, len(info.Headers))
+	for k := range info.Headers {
+		if !allowedRequestHeader(k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := attribute.Key(httpRequestHeaderKeyPrefix + strings.ToLower(k))
+		attrs = append(attrs, key.StringSlice([]string{info.Headers[k]}))
+	}
+
+	return attrs
+}
+
+// HTTPResponseAttributes returns the attributes describing info.
+func HTTPResponseAttributes(info slog.HTTPResponseInfo) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		HTTPResponseStatusCodeKey.Int64(info.Status),
+	}
+
+	if info.Error.Name != "" {
+		attrs = append(attrs, ErrorTypeKey.String(info.Error.Name))
+	}
+
+	return attrs
+}
+
+// KafkaMessageAttributes returns the attributes describing message.
+func KafkaMessageAttributes(message slog.KafkaMessage) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		MessagingSystemKey.String("kafka"),
+		MessagingDestinationKey.String(message.Topic),
+		MessagingPartitionKey.String(strconv.FormatInt(message.Partition, 10)),
+		MessagingKafkaOffsetKey.Int64(message.Offset),
+	}
+
+	if message.Key != "" {
+		attrs = append(attrs, MessagingKafkaMessageKey.String(message.Key))
+	}
+
+	return attrs
+}
+
+// HTTPRequestInfoFromOTel builds an HTTPRequestInfo from attributes recorded by existing instrumentation,
+// unknown attributes are ignored.
+func HTTPRequestInfoFromOTel(attrs []attribute.KeyValue) slog.HTTPRequestInfo {
+	var method, path, remoteIP string
+	headers := map[string]string{}
+	query := map[string]string{}
+
+	for _, attr := range attrs {
+		switch {
+		case attr.Key == HTTPRequestMethodKey:
+			method = attr.Value.AsString()
+		case attr.Key == URLPathKey:
+			path = attr.Value.AsString()
+		case attr.Key == ClientAddressKey:
+			remoteIP = attr.Value.AsString()
+		case attr.Key == URLQueryKey:
+			values, _ := url.ParseQuery(attr.Value.AsString())
+			for k, v := range values {
+				query[k] = v[0]
+			}
+		case strings.HasPrefix(string(attr.Key), httpRequestHeaderKeyPrefix):
+			name := strings.TrimPrefix(string(attr.Key), httpRequestHeaderKeyPrefix)
+			if values := attr.Value.AsStringSlice(); len(values) > 0 {
+				headers[name] = values[0]
+			} else if value := attr.Value.AsString(); value != "" {
+				headers[name] = value
+			}
+		}
+	}
+
+	return slog.WithHTTPRequest(method, path, remoteIP, headers, nil, query, "")
+}
@@ -0,0 +1,159 @@
+package slogotel
+
+import (
+	slog "github.com/Sellsuki/sellsuki-go-logger"
+	"go.opentelemetry.io/otel/attribute"
+	"reflect"
+	"testing"
+)
+
+func TestHTTPRequestAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		info slog.HTTPRequestInfo
+		want []attribute.KeyValue
+	}{
+		{
+			name: "Full request",
+			info: slog.WithHTTPRequest(
+				"POST",
+				"/orders",
+				"127.0.0.1",
+				map[string]string{"User-Agent": "curl", "Content-Type": "application/json"},
+				nil,
+				map[string]string{"page": "2"},
+				"{}",
+			),
+			want: []attribute.KeyValue{
+				attribute.String("http.request.method", "POST"),
+				attribute.String("url.path", "/orders"),
+				attribute.String("client.address", "127.0.0.1"),
+				attribute.String("url.query", "page=2"),
+				attribute.StringSlice("http.request.header.content-type", []string{"application/json"}),
+				attribute.StringSlice("http.request.header.user-agent", []string{"curl"}),
+			},
+		},
+		{
+			name: "Credential headers left out",
+			info: slog.WithHTTPRequest(
+				"GET",
+				"/",
+				"",
+				map[string]string{
+					"Authorization":       "Bearer secret",
+					"Cookie":              "session=secret",
+					"Proxy-Authorization": "Basic secret",
+					"X-Api-Key":           "secret",
+					"x-request-id":        "req-1",
+				},
+				nil,
+				nil,
+				"",
+			),
+			want: []attribute.KeyValue{
+				attribute.String("http.request.method", "GET"),
+				attribute.String("url.path", "/"),
+				attribute.StringSlice("http.request.header.x-request-id", []string{"req-1"}),
+			},
+		},
+		{
+			name: "Minimal request",
+			info: slog.WithHTTPRequest("GET", "/", "", nil, nil, nil, ""),
+			want: []attribute.KeyValue{
+				attribute.String("http.request.method", "GET"),
+				attribute.String("url.path", "/"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPRequestAttributes(tt.info); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTTPRequestAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPResponseAttributes(t *testing.T) {
+	tests := []struct {
+		name string
+		info slog.HTTPResponseInfo
+		want []attribute.KeyValue
+	}{
+		{
+			name: "Success",
+			info: slog.WithHTTPResponse(200, 1, ""),
+			want: []attribute.KeyValue{
+				attribute.Int64("http.response.status_code", 200),
+			},
+		},
+		{
+			name: "Error",
+			info: slog.WithHTTPResponse(404, 1, "", slog.WithError("item_not_found")),
+			want: []attribute.KeyValue{
+				attribute.Int64("http.response.status_code", 404),
+				attribute.String("error.type", "item_not_found"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTTPResponseAttributes(tt.info); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HTTPResponseAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKafkaMessageAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		message slog.KafkaMessage
+		want    []attribute.KeyValue
+	}{
+		{
+			name:    "With key",
+			message: slog.KafkaMessage{Topic: "orders", Partition: 3, Offset: 500, Key: "order-1"},
+			want: []attribute.KeyValue{
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", "orders"),
+				attribute.String("messaging.destination.partition.id", "3"),
+				attribute.Int64("messaging.kafka.message.offset", 500),
+				attribute.String("messaging.kafka.message.key", "order-1"),
+			},
+		},
+		{
+			name:    "Without key",
+			message: slog.KafkaMessage{Topic: "orders"},
+			want: []attribute.KeyValue{
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", "orders"),
+				attribute.String("messaging.destination.partition.id", "0"),
+				attribute.Int64("messaging.kafka.message.offset", 0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KafkaMessageAttributes(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KafkaMessageAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRequestInfoFromOTel(t *testing.T) {
+	info := slog.WithHTTPRequest(
+		"POST",
+		"/orders",
+		"127.0.0.1",
+		map[string]string{"content-type": "application/json"},
+		nil,
+		map[string]string{"page": "2"},
+		"",
+	)
+
+	if got := HTTPRequestInfoFromOTel(HTTPRequestAttributes(info)); !reflect.DeepEqual(got, info) {
+		t.Errorf("HTTPRequestInfoFromOTel() = %v, want %v", got, info)
+	}
+}
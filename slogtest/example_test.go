@@ -0,0 +1,50 @@
+package slogtest_test
+
+import (
+	"fmt"
+	"github.com/Sellsuki/sellsuki-go-logger"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"time"
+)
+
+func ExampleFaultySink() {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sink := slogtest.NewFaultySink(clock, slogtest.Faults{FailAfter: 1})
+
+	c := slog.NewProductionConfig()
+	c.Clock = clock
+	c.Output = sink
+	c.OnWriteError = func(err error) {
+		fmt.Println("write error:", err)
+	}
+
+	logger := &slog.SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		panic(err)
+	}
+	logger.Info("written")
+	logger.Info("falls back to stderr")
+
+	fmt.Println(len(sink.Lines()), sink.Writes(), sink.Failures())
+	// Output:
+	// write error: slogtest: injected write failure
+	// 1 2 1
+}
+
+func ExampleFaults_recovery() {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	sink := slogtest.NewFaultySink(clock, slogtest.Faults{
+		FailureRate:  1,
+		RecoverAfter: time.Minute,
+	})
+
+	_, err := sink.Write([]byte("lost\n"))
+	fmt.Println(err)
+
+	clock.Advance(time.Minute)
+	_, err = sink.Write([]byte("written\n"))
+	fmt.Println(err)
+	// Output:
+	// slogtest: injected write failure
+	// <nil>
+}
@@ -0,0 +1,168 @@
+// Package slogtest provides helpers to test code using the logger.
+package slogtest
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock.Clock whose time only moves with Advance, for tests
+// that should not sleep. Tickers and timers fire synchronously within Advance.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending timer, or a ticker when period is set.
+type waiter struct {
+	clock  *FakeClock
+	at     time.Time
+	period time.Duration
+	f      func()
+	c      chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("slogtest: non-positive interval for NewTicker")
+	}
+	w := &waiter{clock: c, period: d, c: make(chan time.Time, 1)}
+	c.schedule(w, d)
+	return fakeTicker{w}
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	w := &waiter{clock: c, f: f}
+	c.schedule(w, d)
+	return fakeTimer{w}
+}
+
+func (c *FakeClock) schedule(w *waiter, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.at = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+}
+
+// Advance moves the time forward by d, firing in order every ticker and timer due meanwhile.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+
+	for {
+		w := c.next(target)
+		if w == nil {
+			break
+		}
+		c.now = w.at
+
+		if w.period > 0 {
+			select {
+			case w.c <- w.at:
+			default:
+			}
+			w.at = w.at.Add(w.period)
+			continue
+		}
+
+		c.remove(w)
+		c.mu.Unlock()
+		w.f()
+		c.mu.Lock()
+	}
+
+	c.now = target
+	c.mu.Unlock()
+}
+
+// next returns the earliest waiter due by target.
+func (c *FakeClock) next(target time.Time) *waiter {
+	var next *waiter
+	for _, w := range c.waiters {
+		if !w.at.After(target) && (next == nil || w.at.Before(next.at)) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (c *FakeClock) remove(w *waiter) bool {
+	for i := range c.waiters {
+		if c.waiters[i] == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// BlockUntil blocks until at least n tickers and timers are pending, for a test
+// to wait for the code under test to schedule them before calling Advance.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Pending returns the time each pending ticker and timer fires next, earliest first.
+func (c *FakeClock) Pending() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := make([]time.Time, 0, len(c.waiters))
+	for _, w := range c.waiters {
+		pending = append(pending, w.at)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Before(pending[j])
+	})
+	return pending
+}
+
+// stop cancels the waiter, it reports whether it was still pending.
+func (w *waiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.remove(w)
+}
+
+type fakeTicker struct {
+	w *waiter
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.w.c
+}
+
+func (t fakeTicker) Stop() {
+	t.w.stop()
+}
+
+type fakeTimer struct {
+	w *waiter
+}
+
+func (t fakeTimer) Stop() bool {
+	return t.w.stop()
+}
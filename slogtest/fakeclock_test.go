@@ -0,0 +1,84 @@
+package slogtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFakeClockAfterFunc(t *testing.T) {
+	clock := NewFakeClock(epoch)
+
+	var fired []time.Time
+	clock.AfterFunc(2*time.Second, func() { fired = append(fired, clock.Now()) })
+	clock.AfterFunc(time.Second, func() { fired = append(fired, clock.Now()) })
+	stopped := clock.AfterFunc(3*time.Second, func() { t.Error("stopped timer fired") })
+
+	if !stopped.Stop() {
+		t.Error("Stop() = false for a pending timer")
+	}
+	if stopped.Stop() {
+		t.Error("Stop() = true for a stopped timer")
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if want := []time.Time{epoch.Add(time.Second)}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("fired = %v, want %v", fired, want)
+	}
+
+	clock.Advance(5 * time.Second)
+	if want := []time.Time{epoch.Add(time.Second), epoch.Add(2 * time.Second)}; !reflect.DeepEqual(fired, want) {
+		t.Errorf("fired = %v, want %v", fired, want)
+	}
+	if now := clock.Now(); !now.Equal(epoch.Add(6500 * time.Millisecond)) {
+		t.Errorf("Now() = %v, want %v", now, epoch.Add(6500*time.Millisecond))
+	}
+	if pending := clock.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want none", pending)
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	ticker := clock.NewTicker(time.Minute)
+
+	clock.Advance(time.Minute)
+	if tick := <-ticker.C(); !tick.Equal(epoch.Add(time.Minute)) {
+		t.Errorf("tick = %v, want %v", tick, epoch.Add(time.Minute))
+	}
+
+	if want := []time.Time{epoch.Add(2 * time.Minute)}; !reflect.DeepEqual(clock.Pending(), want) {
+		t.Errorf("Pending() = %v, want %v", clock.Pending(), want)
+	}
+
+	// Like time.Ticker, ticks are dropped for a slow receiver.
+	clock.Advance(3 * time.Minute)
+	if tick := <-ticker.C(); !tick.Equal(epoch.Add(2 * time.Minute)) {
+		t.Errorf("tick = %v, want %v", tick, epoch.Add(2*time.Minute))
+	}
+	select {
+	case tick := <-ticker.C():
+		t.Errorf("unexpected tick %v", tick)
+	default:
+	}
+
+	ticker.Stop()
+	if pending := clock.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want none after Stop", pending)
+	}
+}
+
+func TestFakeClockBlockUntil(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	done := make(chan struct{})
+
+	go func() {
+		clock.AfterFunc(time.Second, func() { close(done) })
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	<-done
+}
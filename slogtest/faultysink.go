@@ -0,0 +1,166 @@
+package slogtest
+
+import (
+	"errors"
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the error of the writes failed by a FaultySink when Faults.Err is nil.
+var ErrInjected = errors.New("slogtest: injected write failure")
+
+// Faults is the script of a FaultySink, the zero value writes every line.
+type Faults struct {
+	// Latency is how long every write takes, it advances a FakeClock rather than sleeping.
+	Latency time.Duration
+	// FailureRate is the probability for a write to fail, from 0 to 1.
+	FailureRate float64
+	// Rand returns the number in [0, 1) compared to FailureRate, math/rand when nil.
+	Rand func() float64
+	// FailAfter fails every write once FailAfter writes succeeded (0 = Disabled).
+	FailAfter int
+	// PartialWrite writes only the first PartialWrite bytes of a longer line and
+	// fails it with io.ErrShortWrite (0 = Disabled).
+	PartialWrite int
+	// RecoverAfter stops the failures once RecoverAfter elapsed since the first one (0 = Never).
+	RecoverAfter time.Duration
+	// Err is returned by the failed writes, ErrInjected when nil.
+	Err error
+	// SyncErr is returned by Sync.
+	SyncErr error
+}
+
+// FaultySink is a zapcore.WriteSyncer, usable as Config.Output, misbehaving as
+// scripted by its Faults to test how the logger handles a failing output.
+type FaultySink struct {
+	mu       sync.Mutex
+	clock    clock.Clock
+	faults   Faults
+	lines    []string
+	writes   int
+	failures int
+	syncs    int
+	// failedAt is when the first failure happened, zero before.
+	failedAt time.Time
+}
+
+// NewFaultySink returns a FaultySink timed by c, the real clock when nil.
+func NewFaultySink(c clock.Clock, faults Faults) *FaultySink {
+	if c == nil {
+		c = clock.Real{}
+	}
+	return &FaultySink{clock: c, faults: faults}
+}
+
+// SetFaults replaces the script, the counters and the recorded lines are kept.
+func (f *FaultySink) SetFaults(faults Faults) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.faults = faults
+	f.failedAt = time.Time{}
+}
+
+func (f *FaultySink) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	latency := f.faults.Latency
+	f.mu.Unlock()
+
+	// The clock is advanced unlocked, a timer it fires may write to the sink.
+	if latency > 0 {
+		if fake, ok := f.clock.(*FakeClock); ok {
+			fake.Advance(latency)
+		} else {
+			time.Sleep(latency)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.writes++
+	if f.failing() {
+		f.failures++
+		if f.failedAt.IsZero() {
+			f.failedAt = f.clock.Now()
+		}
+		return 0, f.err()
+	}
+
+	if f.faults.PartialWrite > 0 && len(p) > f.faults.PartialWrite {
+		f.failures++
+		f.lines = append(f.lines, string(p[:f.faults.PartialWrite]))
+		return f.faults.PartialWrite, io.ErrShortWrite
+	}
+
+	f.lines = append(f.lines, string(p))
+	return len(p), nil
+}
+
+// failing reports whether the current write fails.
+func (f *FaultySink) failing() bool {
+	if f.faults.RecoverAfter > 0 && !f.failedAt.IsZero() && f.clock.Now().Sub(f.failedAt) >= f.faults.RecoverAfter {
+		return false
+	}
+	if f.faults.FailAfter > 0 && len(f.lines) >= f.faults.FailAfter {
+		return true
+	}
+	if f.faults.FailureRate > 0 {
+		random := f.faults.Rand
+		if random == nil {
+			random = rand.Float64
+		}
+		return random() < f.faults.FailureRate
+	}
+	return false
+}
+
+func (f *FaultySink) err() error {
+	if f.faults.Err != nil {
+		return f.faults.Err
+	}
+	return ErrInjected
+}
+
+func (f *FaultySink) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.syncs++
+	return f.faults.SyncErr
+}
+
+// Lines returns the lines written, a partial write records the bytes written.
+func (f *FaultySink) Lines() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.lines...)
+}
+
+// Writes returns the number of writes received, failed ones included.
+func (f *FaultySink) Writes() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.writes
+}
+
+// Failures returns the number of failed writes, partial ones included.
+func (f *FaultySink) Failures() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.failures
+}
+
+// Syncs returns the number of calls to Sync.
+func (f *FaultySink) Syncs() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.syncs
+}
@@ -0,0 +1,144 @@
+package slogtest
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFaultySink(t *testing.T) {
+	errDisk := errors.New("disk full")
+
+	tests := []struct {
+		name         string
+		faults       Faults
+		advance      time.Duration
+		writes       []string
+		wantErrs     []error
+		wantLines    []string
+		wantFailures int
+		wantElapsed  time.Duration
+	}{
+		{
+			name:      "No faults",
+			writes:    []string{"a\n", "b\n"},
+			wantErrs:  []error{nil, nil},
+			wantLines: []string{"a\n", "b\n"},
+		},
+		{
+			name:         "Fail after",
+			faults:       Faults{FailAfter: 1},
+			writes:       []string{"a\n", "b\n", "c\n"},
+			wantErrs:     []error{nil, ErrInjected, ErrInjected},
+			wantLines:    []string{"a\n"},
+			wantFailures: 2,
+		},
+		{
+			name:         "Custom error",
+			faults:       Faults{FailAfter: 1, Err: errDisk},
+			writes:       []string{"a\n", "b\n"},
+			wantErrs:     []error{nil, errDisk},
+			wantLines:    []string{"a\n"},
+			wantFailures: 1,
+		},
+		{
+			name:         "Failure rate",
+			faults:       Faults{FailureRate: 0.5, Rand: sequence(0.1, 0.9, 0.4)},
+			writes:       []string{"a\n", "b\n", "c\n"},
+			wantErrs:     []error{ErrInjected, nil, ErrInjected},
+			wantLines:    []string{"b\n"},
+			wantFailures: 2,
+		},
+		{
+			name:         "Partial write",
+			faults:       Faults{PartialWrite: 3},
+			writes:       []string{"ab\n", "abcdef\n"},
+			wantErrs:     []error{nil, io.ErrShortWrite},
+			wantLines:    []string{"ab\n", "abc"},
+			wantFailures: 1,
+		},
+		{
+			name:         "Recover after",
+			faults:       Faults{FailAfter: 1, RecoverAfter: time.Second, Latency: 400 * time.Millisecond},
+			writes:       []string{"a\n", "b\n", "c\n", "d\n", "e\n"},
+			wantErrs:     []error{nil, ErrInjected, ErrInjected, ErrInjected, nil},
+			wantLines:    []string{"a\n", "e\n"},
+			wantFailures: 3,
+			wantElapsed:  2 * time.Second,
+		},
+		{
+			name:        "Latency",
+			faults:      Faults{Latency: 250 * time.Millisecond},
+			writes:      []string{"a\n", "b\n"},
+			wantErrs:    []error{nil, nil},
+			wantLines:   []string{"a\n", "b\n"},
+			wantElapsed: 500 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(epoch)
+			sink := NewFaultySink(clock, tt.faults)
+
+			var errs []error
+			for _, line := range tt.writes {
+				_, err := sink.Write([]byte(line))
+				errs = append(errs, err)
+			}
+
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("errors = %v, want %v", errs, tt.wantErrs)
+			}
+			if lines := sink.Lines(); !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("Lines() = %q, want %q", lines, tt.wantLines)
+			}
+			if sink.Writes() != len(tt.writes) || sink.Failures() != tt.wantFailures {
+				t.Errorf("Writes(), Failures() = %d, %d, want %d, %d", sink.Writes(), sink.Failures(), len(tt.writes), tt.wantFailures)
+			}
+			if elapsed := clock.Now().Sub(epoch); elapsed != tt.wantElapsed {
+				t.Errorf("elapsed = %v, want %v", elapsed, tt.wantElapsed)
+			}
+		})
+	}
+}
+
+func TestFaultySinkSetFaults(t *testing.T) {
+	sink := NewFaultySink(NewFakeClock(epoch), Faults{FailAfter: 1})
+	sink.Write([]byte("a\n"))
+	sink.Write([]byte("b\n"))
+
+	sink.SetFaults(Faults{})
+	if _, err := sink.Write([]byte("c\n")); err != nil {
+		t.Errorf("Write() error = %v after clearing the faults", err)
+	}
+
+	if want := []string{"a\n", "c\n"}; !reflect.DeepEqual(sink.Lines(), want) {
+		t.Errorf("Lines() = %q, want %q", sink.Lines(), want)
+	}
+	if sink.Writes() != 3 || sink.Failures() != 1 {
+		t.Errorf("Writes(), Failures() = %d, %d, want 3, 1", sink.Writes(), sink.Failures())
+	}
+}
+
+func TestFaultySinkSync(t *testing.T) {
+	errSync := errors.New("sync failed")
+	sink := NewFaultySink(nil, Faults{SyncErr: errSync})
+
+	if err := sink.Sync(); err != errSync {
+		t.Errorf("Sync() error = %v, want %v", err, errSync)
+	}
+	if sink.Syncs() != 1 {
+		t.Errorf("Syncs() = %d, want 1", sink.Syncs())
+	}
+}
+
+// sequence returns a Faults.Rand returning values in turn.
+func sequence(values ...float64) func() float64 {
+	return func() float64 {
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+}
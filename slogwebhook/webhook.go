@@ -0,0 +1,303 @@
+// Package slogwebhook posts the alerting entries of a logger to a webhook,
+// e.g. a Slack or Opsgenie integration, off the logging path.
+package slogwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	slog "github.com/Sellsuki/sellsuki-go-logger"
+	"github.com/Sellsuki/sellsuki-go-logger/clock"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultQueueSize is the number of alerts waiting for delivery when Options.QueueSize is 0.
+	DefaultQueueSize = 100
+	// DefaultMaxRetries is the number of retries of a failed delivery when Options.MaxRetries is 0.
+	DefaultMaxRetries = 3
+	// DefaultBackoff is the delay before the first retry when Options.Backoff is 0.
+	DefaultBackoff = 500 * time.Millisecond
+	// DefaultFailureThreshold is the number of failed deliveries in a row opening
+	// the circuit when Options.FailureThreshold is 0.
+	DefaultFailureThreshold = 5
+	// DefaultOpenDuration is how long the circuit stays open when Options.OpenDuration is 0.
+	DefaultOpenDuration = 30 * time.Second
+	// DefaultTimeout bounds a delivery attempt when Options.Client is nil.
+	DefaultTimeout = 10 * time.Second
+)
+
+type Options struct {
+	// URL receives a POST with the JSON of every alerting entry.
+	URL string
+	// Client sends the requests, a client timing out after DefaultTimeout when nil.
+	Client *http.Client
+	// QueueSize bounds the alerts waiting for delivery, the alerts written while
+	// it is full are dropped.
+	QueueSize int
+	// MaxRetries is the number of retries of a delivery failing with a network
+	// error, a 429 or a 5xx status (-1 = No retry).
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for every other retry.
+	Backoff time.Duration
+	// FailureThreshold is the number of failed deliveries in a row opening the
+	// circuit, the alerts are rejected without being sent while it is open.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open, the next alert is then
+	// sent once to close it again or reopen it.
+	OpenDuration time.Duration
+	// Clock times the backoff and the circuit, the real clock when nil.
+	Clock clock.Clock
+}
+
+func (o Options) withDefaults() Options {
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = DefaultQueueSize
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultMaxRetries
+	} else if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	if o.FailureThreshold <= 0 {
+		o.FailureThreshold = DefaultFailureThreshold
+	}
+	if o.OpenDuration <= 0 {
+		o.OpenDuration = DefaultOpenDuration
+	}
+	if o.Clock == nil {
+		o.Clock = clock.Real{}
+	}
+	return o
+}
+
+// Stats are the counters of a Webhook.
+type Stats struct {
+	// Sent are the alerts delivered.
+	Sent int64
+	// Failed are the alerts whose delivery still failed after the retries.
+	Failed int64
+	// Dropped are the alerts written while the subscription buffer or the queue was full.
+	Dropped int64
+	// Rejected are the alerts not sent as the circuit was open.
+	Rejected int64
+}
+
+// Payload is the JSON body posted for an alerting entry.
+type Payload struct {
+	Level     string                 `json:"level"`
+	Timestamp time.Time              `json:"timestamp"`
+	Message   string                 `json:"message"`
+	Caller    string                 `json:"caller,omitempty"`
+	AppName   string                 `json:"app_name"`
+	Version   string                 `json:"version"`
+	LogType   string                 `json:"log_type"`
+	Alert     slog.AlertLevel        `json:"alert"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Webhook posts the entries written with alert set to a URL.
+type Webhook struct {
+	opts    Options
+	sub     *slog.Subscription
+	entries <-chan slog.Entry
+	queue   chan item
+	flush   chan chan struct{}
+	done    chan struct{}
+
+	sent, failed, dropped, rejected int64
+
+	// failures counts the failed deliveries in a row, openUntil is when the
+	// circuit closes again, both only used by the delivery goroutine.
+	failures  int
+	openUntil time.Time
+}
+
+// item is a queued alert, or a flush marker when flushed is set.
+type item struct {
+	entry   slog.Entry
+	flushed chan struct{}
+}
+
+// New subscribes to the alerting entries of logger and posts them to
+// opts.URL until Close is called, e.g.
+//
+//	hook := slogwebhook.New(slog.L(), slogwebhook.Options{URL: os.Getenv("ALERT_WEBHOOK_URL")})
+//	defer hook.Close()
+func New(logger *slog.SukiLogger, opts Options) *Webhook {
+	opts = opts.withDefaults()
+	sub := logger.SubscribeAlerts()
+
+	w := &Webhook{
+		opts:    opts,
+		sub:     sub,
+		entries: sub.Entries(),
+		queue:   make(chan item, opts.QueueSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.forward()
+	go w.deliver()
+	return w
+}
+
+// Flush returns once the alerts written before it was called are delivered, failed or rejected.
+func (w *Webhook) Flush() {
+	flushed := make(chan struct{})
+	select {
+	case w.flush <- flushed:
+		<-flushed
+	case <-w.done:
+	}
+}
+
+// Close stops receiving entries and returns once the queued alerts are delivered, failed or rejected.
+func (w *Webhook) Close() {
+	w.sub.Unsubscribe()
+	<-w.done
+}
+
+func (w *Webhook) Stats() Stats {
+	return Stats{
+		Sent:     atomic.LoadInt64(&w.sent),
+		Failed:   atomic.LoadInt64(&w.failed),
+		Dropped:  atomic.LoadInt64(&w.dropped) + w.sub.Dropped(),
+		Rejected: atomic.LoadInt64(&w.rejected),
+	}
+}
+
+// forward moves the alerting entries to the queue, dropping them when it is full.
+func (w *Webhook) forward() {
+	defer close(w.queue)
+
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				return
+			}
+			w.enqueue(entry)
+		case flushed := <-w.flush:
+			// The entries written before Flush are already buffered by the subscription.
+			w.drain()
+			w.queue <- item{flushed: flushed}
+		}
+	}
+}
+
+func (w *Webhook) drain() {
+	for {
+		select {
+		case entry, ok := <-w.entries:
+			if !ok {
+				return
+			}
+			w.enqueue(entry)
+		default:
+			return
+		}
+	}
+}
+
+func (w *Webhook) enqueue(entry slog.Entry) {
+	if entry.Alert < slog.LevelAlert {
+		return
+	}
+	select {
+	case w.queue <- item{entry: entry}:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+}
+
+func (w *Webhook) deliver() {
+	defer close(w.done)
+
+	for it := range w.queue {
+		if it.flushed != nil {
+			close(it.flushed)
+			continue
+		}
+
+		if w.failures >= w.opts.FailureThreshold && w.opts.Clock.Now().Before(w.openUntil) {
+			atomic.AddInt64(&w.rejected, 1)
+			continue
+		}
+
+		// A half-open circuit gets a single attempt.
+		retries := w.opts.MaxRetries
+		if w.failures >= w.opts.FailureThreshold {
+			retries = 0
+		}
+
+		if err := w.send(it.entry, retries); err != nil {
+			atomic.AddInt64(&w.failed, 1)
+			w.failures++
+			if w.failures >= w.opts.FailureThreshold {
+				w.openUntil = w.opts.Clock.Now().Add(w.opts.OpenDuration)
+			}
+			continue
+		}
+		atomic.AddInt64(&w.sent, 1)
+		w.failures = 0
+	}
+}
+
+// send posts entry, retrying the retryable failures up to retries times.
+func (w *Webhook) send(entry slog.Entry, retries int) error {
+	body, err := json.Marshal(Payload{
+		Level:     entry.Level,
+		Timestamp: entry.Timestamp,
+		Message:   entry.Message,
+		Caller:    entry.Caller,
+		AppName:   entry.AppName,
+		Version:   entry.Version,
+		LogType:   entry.LogType,
+		Alert:     entry.Alert,
+		Data:      entry.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	backoff := w.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.post(body)
+		if err == nil || !retryable || attempt >= retries {
+			return err
+		}
+		w.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post posts body once, reporting whether a failure is worth a retry.
+func (w *Webhook) post(body []byte) (bool, error) {
+	resp, err := w.opts.Client.Post(w.opts.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("slogwebhook: %s responded %s", w.opts.URL, resp.Status)
+}
+
+func (w *Webhook) sleep(d time.Duration) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	w.opts.Clock.AfterFunc(d, wg.Done)
+	wg.Wait()
+}
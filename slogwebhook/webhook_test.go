@@ -0,0 +1,232 @@
+package slogwebhook
+
+import (
+	"encoding/json"
+	slog "github.com/Sellsuki/sellsuki-go-logger"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// stubServer responds with the statuses in order, the last one once they are exhausted.
+type stubServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	payloads []Payload
+}
+
+func newStubServer(t *testing.T, statuses ...int) *stubServer {
+	s := &stubServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("invalid request %v: %v", r.Header, err)
+		}
+
+		s.mu.Lock()
+		s.payloads = append(s.payloads, p)
+		status := s.statuses[0]
+		if len(s.statuses) > 1 {
+			s.statuses = s.statuses[1:]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *stubServer) setStatuses(statuses ...int) {
+	s.mu.Lock()
+	s.statuses = statuses
+	s.mu.Unlock()
+}
+
+func (s *stubServer) hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.payloads)
+}
+
+func newLogger(t *testing.T, clock *slogtest.FakeClock) *slog.SukiLogger {
+	c := slog.NewProductionConfig()
+	c.AppName = "shop"
+	c.Output = io.Discard
+	c.Clock = clock
+	logger := &slog.SukiLogger{}
+	if err := logger.Configure(c); err != nil {
+		t.Fatal(err)
+	}
+	return logger
+}
+
+func alert(logger *slog.SukiLogger, message string) {
+	logger.Warn(message, slog.Any("order_id", 1), slog.LogOption{Alert: slog.LevelAlert})
+}
+
+func TestWebhookSuccess(t *testing.T) {
+	server := newStubServer(t, http.StatusOK)
+	logger := newLogger(t, slogtest.NewFakeClock(start))
+	hook := New(logger, Options{URL: server.URL})
+	defer hook.Close()
+
+	logger.Info("not an alert")
+	alert(logger, "payment gateway down")
+	hook.Flush()
+
+	if got, want := hook.Stats(), (Stats{Sent: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if len(server.payloads) != 1 {
+		t.Fatalf("%d requests, want 1", len(server.payloads))
+	}
+	p := server.payloads[0]
+	if p.Level != "warn" || p.Message != "payment gateway down" || p.AppName != "shop" || p.LogType != "application" || p.Alert != slog.LevelAlert || !p.Timestamp.Equal(start) {
+		t.Errorf("payload = %+v", p)
+	}
+	if want := map[string]interface{}{"shop": map[string]interface{}{"order_id": float64(1)}}; !reflect.DeepEqual(p.Data, want) {
+		t.Errorf("payload data = %v, want %v", p.Data, want)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantHits  int
+		wantStats Stats
+	}{
+		{
+			name:      "Recovers",
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			wantHits:  3,
+			wantStats: Stats{Sent: 1},
+		},
+		{
+			name:      "Retries exhausted",
+			statuses:  []int{http.StatusInternalServerError},
+			wantHits:  4,
+			wantStats: Stats{Failed: 1},
+		},
+		{
+			name:      "Not retryable",
+			statuses:  []int{http.StatusBadRequest},
+			wantHits:  1,
+			wantStats: Stats{Failed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubServer(t, tt.statuses...)
+			clock := slogtest.NewFakeClock(start)
+			logger := newLogger(t, clock)
+			hook := New(logger, Options{URL: server.URL, MaxRetries: 3, Backoff: 100 * time.Millisecond, Clock: clock})
+			defer hook.Close()
+
+			alert(logger, "payment gateway down")
+
+			// Every retry waits twice as long as the previous one.
+			for i, backoff := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+				if i+1 >= tt.wantHits {
+					break
+				}
+				clock.BlockUntil(1)
+				if pending := clock.Pending(); len(pending) != 1 || !pending[0].Equal(clock.Now().Add(backoff)) {
+					t.Fatalf("retry %d scheduled at %v, want %v later", i+1, pending, backoff)
+				}
+				clock.Advance(backoff)
+			}
+			hook.Flush()
+
+			if server.hits() != tt.wantHits {
+				t.Errorf("%d requests, want %d", server.hits(), tt.wantHits)
+			}
+			if got := hook.Stats(); got != tt.wantStats {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestWebhookOpenCircuit(t *testing.T) {
+	server := newStubServer(t, http.StatusInternalServerError)
+	clock := slogtest.NewFakeClock(start)
+	logger := newLogger(t, clock)
+	hook := New(logger, Options{URL: server.URL, MaxRetries: -1, FailureThreshold: 2, OpenDuration: time.Minute, Clock: clock})
+	defer hook.Close()
+
+	for _, message := range []string{"first", "second", "third", "fourth"} {
+		alert(logger, message)
+	}
+	hook.Flush()
+
+	if server.hits() != 2 {
+		t.Errorf("%d requests, want 2 before the circuit opened", server.hits())
+	}
+	if got, want := hook.Stats(), (Stats{Failed: 2, Rejected: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// Half-open: a single attempt, its failure reopens the circuit at once.
+	clock.Advance(time.Minute)
+	alert(logger, "half-open failure")
+	alert(logger, "rejected again")
+	hook.Flush()
+	if got, want := hook.Stats(), (Stats{Failed: 3, Rejected: 3}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// Its success closes the circuit.
+	clock.Advance(time.Minute)
+	server.setStatuses(http.StatusOK)
+	alert(logger, "half-open success")
+	alert(logger, "closed")
+	hook.Flush()
+	if got, want := hook.Stats(), (Stats{Sent: 2, Failed: 3, Rejected: 3}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWebhookQueueFull(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer server.Close()
+
+	logger := newLogger(t, slogtest.NewFakeClock(start))
+	hook := New(logger, Options{URL: server.URL, QueueSize: 1})
+	defer hook.Close()
+
+	alert(logger, "delivering")
+	<-received
+	alert(logger, "queued")
+	alert(logger, "dropped")
+
+	go func() {
+		<-received
+		close(release)
+	}()
+	release <- struct{}{}
+	hook.Flush()
+
+	if got, want := hook.Stats(), (Stats{Sent: 2, Dropped: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDefaultClientTimeout(t *testing.T) {
+	if got := (Options{}).withDefaults().Client.Timeout; got != DefaultTimeout {
+		t.Errorf("default client timeout = %v, want %v", got, DefaultTimeout)
+	}
+}
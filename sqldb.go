@@ -0,0 +1,103 @@
+package slog
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type SQLOption struct {
+	// System is the DBQuery.System of the logs, e.g. postgresql.
+	System string
+	// PoolStats adds the pool statistics to the logs of slow queries as data.db_pool.
+	PoolStats bool
+}
+
+// SQLDB is a *sql.DB writing a client.db log for every query run through its
+// Exec, Query and QueryRow methods.
+type SQLDB struct {
+	*sql.DB
+	logger *SukiLogger
+	opts   SQLOption
+}
+
+// SQLDB wraps db to log its queries, e.g.
+//
+//	db := slog.L().SQLDB(sqlDB, slog.SQLOption{System: "postgresql", PoolStats: true})
+//	rows, err := db.QueryContext(ctx, "SELECT id FROM orders WHERE shop_id = $1", shopID)
+func (s *SukiLogger) SQLDB(db *sql.DB, opts SQLOption) *SQLDB {
+	return &SQLDB{DB: db, logger: s, opts: opts}
+}
+
+func (d *SQLDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := d.logger.clock().Now()
+	result, err := d.DB.ExecContext(ctx, query, args...)
+
+	var rows int64
+	if err == nil {
+		rows, _ = result.RowsAffected()
+	}
+	d.log(ctx, query, start, rows, err)
+	return result, err
+}
+
+func (d *SQLDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return d.ExecContext(context.Background(), query, args...)
+}
+
+// QueryContext logs the time until the query returned its rows, Rows is not counted.
+func (d *SQLDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := d.logger.clock().Now()
+	rows, err := d.DB.QueryContext(ctx, query, args...)
+	d.log(ctx, query, start, 0, err)
+	return rows, err
+}
+
+func (d *SQLDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return d.QueryContext(context.Background(), query, args...)
+}
+
+func (d *SQLDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := d.logger.clock().Now()
+	row := d.DB.QueryRowContext(ctx, query, args...)
+	d.log(ctx, query, start, 0, row.Err())
+	return row
+}
+
+func (d *SQLDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return d.QueryRowContext(context.Background(), query, args...)
+}
+
+func (d *SQLDB) log(ctx context.Context, query string, start time.Time, rows int64, err error) {
+	var e []ErrorInfo
+	if err != nil {
+		e = append(e, WithError(err.Error()))
+	}
+	result := WithDBResult(toMillis(d.logger.clock().Now().Sub(start)), rows, e...)
+
+	args := []interface{}{ctx}
+	if caller, ok := sqlCaller(); ok {
+		args = append(args, caller)
+	}
+	if d.opts.PoolStats {
+		args = append(args, DBPoolStats(d.DB.Stats))
+	}
+	d.logger.RequestDB("db query", WithDBQuery(d.opts.System, query), result, args...)
+}
+
+// sqlCaller returns the caller of the SQLDB method being logged.
+func sqlCaller() (CallerInfo, bool) {
+	pc := make([]uintptr, 8)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, ".(*SQLDB).") {
+			return Caller(frame.File, frame.Line), true
+		}
+		if !more {
+			return CallerInfo{}, false
+		}
+	}
+}
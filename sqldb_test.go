@@ -0,0 +1,239 @@
+package slog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errSyntax = errors.New("syntax error")
+
+// fakeConnector opens connections running every statement through run.
+type fakeConnector struct {
+	run func(query string) error
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{run: c.run}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	run func(query string) error
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if err := c.run(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(2), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if err := c.run(query); err != nil {
+		return nil, err
+	}
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+func newSQLTestDB(run func(query string) error) *sql.DB {
+	db := sql.OpenDB(fakeConnector{run: run})
+	db.SetMaxOpenConns(1)
+	return db
+}
+
+func TestSQLDB(t *testing.T) {
+	tests := []struct {
+		name      string
+		poolStats bool
+		query     func(db *SQLDB) error
+		wantLevel string
+		wantRows  float64
+		wantError string
+		wantPool  bool
+	}{
+		{
+			name:      "Fast query",
+			poolStats: true,
+			query: func(db *SQLDB) error {
+				rows, err := db.QueryContext(context.Background(), "SELECT id FROM orders")
+				if err == nil {
+					rows.Close()
+				}
+				return err
+			},
+			wantLevel: "info",
+		},
+		{
+			name:      "Slow exec with pool stats",
+			poolStats: true,
+			query: func(db *SQLDB) error {
+				_, err := db.Exec("UPDATE orders SET paid = true -- slow")
+				return err
+			},
+			wantLevel: "warn",
+			wantRows:  2,
+			wantPool:  true,
+		},
+		{
+			name: "Slow query without pool stats",
+			query: func(db *SQLDB) error {
+				return db.QueryRow("SELECT id FROM orders -- slow").Err()
+			},
+			wantLevel: "warn",
+		},
+		{
+			name: "Failed query",
+			query: func(db *SQLDB) error {
+				_, err := db.Query("SELEC id")
+				if !errors.Is(err, errSyntax) {
+					return errors.New("missing error")
+				}
+				return nil
+			},
+			wantLevel: "info",
+			wantError: errSyntax.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+			c := NewProductionConfig()
+			c.Clock = clock
+			c.SlowThresholdMs = map[string]float64{"client.db": 100}
+			logger, buf := newTestLogger(c)
+
+			db := logger.SQLDB(newSQLTestDB(func(query string) error {
+				if strings.HasPrefix(query, "SELEC ") {
+					return errSyntax
+				}
+				if strings.HasSuffix(query, "-- slow") {
+					clock.Advance(200 * time.Millisecond)
+				}
+				return nil
+			}), SQLOption{System: "postgresql", PoolStats: tt.poolStats})
+
+			if err := tt.query(db); err != nil {
+				t.Fatal(err)
+			}
+
+			entry := decodeLines(t, buf)[0]
+			if entry["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %v", entry["level"], tt.wantLevel)
+			}
+			if !strings.Contains(entry["caller"].(string), "sqldb_test.go:") {
+				t.Errorf("caller = %v, want the caller of SQLDB", entry["caller"])
+			}
+
+			data := entry["data"].(map[string]interface{})
+			result := data["db_result"].(map[string]interface{})
+			if result["rows"] != tt.wantRows || result["error"].(map[string]interface{})["name"] != tt.wantError {
+				t.Errorf("db_result = %v", result)
+			}
+			if _, ok := data["db_pool"]; ok != tt.wantPool {
+				t.Errorf("db_pool = %v, want it present: %v", data["db_pool"], tt.wantPool)
+			}
+		})
+	}
+}
+
+func TestSQLDBConstrainedPool(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.Clock = clock
+	c.SlowThresholdMs = map[string]float64{"client.db": 100}
+	logger, buf := newTestLogger(c)
+
+	release := make(chan struct{})
+	first := true
+	var mu sync.Mutex
+	sqlDB := newSQLTestDB(func(query string) error {
+		mu.Lock()
+		blocking := first
+		first = false
+		mu.Unlock()
+
+		if blocking {
+			<-release
+		}
+		clock.Advance(200 * time.Millisecond)
+		return nil
+	})
+	db := logger.SQLDB(sqlDB, SQLOption{System: "postgresql", PoolStats: true})
+
+	var wg sync.WaitGroup
+	query := func() {
+		defer wg.Done()
+		rows, err := db.Query("SELECT id FROM orders")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		rows.Close()
+	}
+
+	wg.Add(2)
+	go query()
+	for sqlDB.Stats().InUse == 0 {
+		runtime.Gosched()
+	}
+	go query()
+	for sqlDB.Stats().WaitCount == 0 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	waited := lines[1]["data"].(map[string]interface{})
+	if waited["slow"] != true {
+		t.Fatalf("data = %v, want the waiting query slow", waited)
+	}
+	pool := waited["db_pool"].(map[string]interface{})
+	if pool["wait_count"] != float64(1) || pool["max_open_connections"] != float64(1) {
+		t.Errorf("db_pool = %v, want wait_count 1 of a single connection pool", pool)
+	}
+	if pool["wait_duration"].(float64) <= 0 {
+		t.Errorf("wait_duration = %v, want the time waited for the connection", pool["wait_duration"])
+	}
+}
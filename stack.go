@@ -0,0 +1,132 @@
+package slog
+
+import (
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StackTraceMode controls how ErrorInfo.StackTrace is written.
+type StackTraceMode int
+
+const (
+	// StackTraceString writes the stack trace as the raw stack_trace string.
+	StackTraceString StackTraceMode = iota
+	// StackTraceFrames replaces stack_trace with the parsed error.frames array.
+	StackTraceFrames
+	// StackTraceBoth writes both stack_trace and error.frames.
+	StackTraceBoth
+)
+
+type StackFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ParseStackFrames parses a stack captured by runtime/debug.Stack or zap into frames,
+// lines that are not part of a frame are ignored.
+func ParseStackFrames(stack string) []StackFrame {
+	var frames []StackFrame
+	lines := strings.Split(stack, "\n")
+
+	for i := 0; i+1 < len(lines); i++ {
+		fn := strings.TrimSpace(lines[i])
+		location := lines[i+1]
+		if fn == "" || strings.HasPrefix(fn, "goroutine ") || !strings.HasPrefix(location, "\t") {
+			continue
+		}
+
+		file, line, ok := parseStackLocation(strings.TrimSpace(location))
+		if !ok {
+			continue
+		}
+
+		frames = append(frames, StackFrame{
+			Func: parseStackFunc(fn),
+			File: file,
+			Line: line,
+		})
+		i++
+	}
+
+	return frames
+}
+
+func parseStackFunc(fn string) string {
+	if strings.HasPrefix(fn, "created by ") {
+		fn = strings.TrimPrefix(fn, "created by ")
+		if i := strings.Index(fn, " in goroutine "); i >= 0 {
+			fn = fn[:i]
+		}
+		return fn
+	}
+
+	if strings.HasSuffix(fn, ")") {
+		if i := strings.LastIndex(fn, "("); i > 0 {
+			fn = fn[:i]
+		}
+	}
+	return fn
+}
+
+func parseStackLocation(location string) (string, int, bool) {
+	if i := strings.LastIndex(location, " +0x"); i >= 0 {
+		location = location[:i]
+	}
+
+	i := strings.LastIndex(location, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+
+	line, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return location[:i], line, true
+}
+
+// formatError applies the configured StackTraceMode and StackTraceSeparator to e.
+func (s SukiLogger) formatError(e ErrorInfo) ErrorInfo {
+	if s.config.StackTraceMode == StackTraceString || e.StackTrace == "" {
+		e.StackTrace = s.config.singleLineStack(e.StackTrace)
+		return e
+	}
+
+	e.Frames = ParseStackFrames(e.StackTrace)
+	if s.config.StackTraceMode == StackTraceFrames {
+		e.StackTrace, e.framesOnly = "", true
+		return e
+	}
+	e.StackTrace = s.config.singleLineStack(e.StackTrace)
+	return e
+}
+
+// stackLineBreak is a line break of a stack trace with the indentation of the next line.
+var stackLineBreak = regexp.MustCompile(`\r?\n[\t ]*`)
+
+// singleLineStack joins the lines of stack with StackTraceSeparator, if set.
+func (c Config) singleLineStack(stack string) string {
+	if c.StackTraceSeparator == "" || stack == "" {
+		return stack
+	}
+	return stackLineBreak.ReplaceAllLiteralString(strings.TrimRight(stack, "\r\n"), c.StackTraceSeparator)
+}
+
+// singleLineStackEncoder joins the lines of the stacktrace zap adds to error logs.
+type singleLineStackEncoder struct {
+	zapcore.Encoder
+	config Config
+}
+
+func (e singleLineStackEncoder) Clone() zapcore.Encoder {
+	return singleLineStackEncoder{Encoder: e.Encoder.Clone(), config: e.config}
+}
+
+func (e singleLineStackEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Stack = e.config.singleLineStack(ent.Stack)
+	return e.Encoder.EncodeEntry(ent, fields)
+}
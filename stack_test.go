@@ -0,0 +1,164 @@
+package slog
+
+import (
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestParseStackFrames(t *testing.T) {
+	_, file, line, _ := runtime.Caller(0)
+	stack := string(debug.Stack())
+
+	frames := ParseStackFrames(stack)
+	if len(frames) < 2 {
+		t.Fatalf("got %d frames from %q", len(frames), stack)
+	}
+
+	if frames[0].Func != "runtime/debug.Stack" {
+		t.Errorf("frames[0].Func = %v, want runtime/debug.Stack", frames[0].Func)
+	}
+
+	want := StackFrame{
+		Func: "github.com/Sellsuki/sellsuki-go-logger.TestParseStackFrames",
+		File: file,
+		Line: line + 1,
+	}
+	if !reflect.DeepEqual(frames[1], want) {
+		t.Errorf("frames[1] = %v, want %v", frames[1], want)
+	}
+}
+
+func TestParseStackFramesFormats(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack string
+		want  []StackFrame
+	}{
+		{
+			name:  "Method with arguments and created by",
+			stack: "goroutine 7 [running]:\nmain.(*Server).handle(0xc000010000, {0x1, 0x2})\n\t/app/server.go:42 +0x1d\ncreated by main.main in goroutine 1\n\t/app/main.go:10 +0x5e\n",
+			want: []StackFrame{
+				{Func: "main.(*Server).handle", File: "/app/server.go", Line: 42},
+				{Func: "main.main", File: "/app/main.go", Line: 10},
+			},
+		},
+		{
+			name:  "Zap stacktrace",
+			stack: "main.run\n\t/app/main.go:20\nmain.main\n\t/app/main.go:12",
+			want: []StackFrame{
+				{Func: "main.run", File: "/app/main.go", Line: 20},
+				{Func: "main.main", File: "/app/main.go", Line: 12},
+			},
+		},
+		{
+			name:  "Not a stack",
+			stack: "/dodge/wow.go:35",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseStackFrames(tt.stack); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseStackFrames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackTraceMode(t *testing.T) {
+	stack := "main.run\n\t/app/main.go:20"
+	tests := []struct {
+		name       string
+		mode       StackTraceMode
+		wantString bool
+		wantFrames bool
+	}{
+		{name: "String", mode: StackTraceString, wantString: true},
+		{name: "Frames", mode: StackTraceFrames, wantFrames: true},
+		{name: "Both", mode: StackTraceBoth, wantString: true, wantFrames: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewProductionConfig()
+			config.StackTraceMode = tt.mode
+			logger, buf := newTestLogger(config)
+
+			logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(500, 1, "", WithError("boom", stack)))
+
+			line := decodeLines(t, buf)[0]
+			e := line["data"].(map[string]interface{})["http_response"].(map[string]interface{})["error"].(map[string]interface{})
+			trace, ok := e["stack_trace"].(string)
+			if tt.wantString && !strings.Contains(trace, "main.run") {
+				t.Errorf("stack_trace = %v, want the string", e["stack_trace"])
+			}
+			if !tt.wantString && ok {
+				t.Errorf("stack_trace = %q, want it omitted", trace)
+			}
+			if _, got := e["frames"]; got != tt.wantFrames {
+				t.Errorf("frames = %v, want frames %v", e["frames"], tt.wantFrames)
+			}
+		})
+	}
+}
+
+func TestStackTraceSeparator(t *testing.T) {
+	stack := "main.run()\n\t/app/main.go:20 +0x1d\nmain.main()\n\t/app/main.go:10 +0x25\n"
+	tests := []struct {
+		name      string
+		separator string
+		mode      StackTraceMode
+		want      string
+		wantLines bool
+	}{
+		{
+			name:      "Multi-line",
+			want:      stack,
+			wantLines: true,
+		},
+		{
+			name:      "Single-line",
+			separator: " | ",
+			want:      "main.run() | /app/main.go:20 +0x1d | main.main() | /app/main.go:10 +0x25",
+		},
+		{
+			name:      "Single-line with frames",
+			separator: " | ",
+			mode:      StackTraceBoth,
+			want:      "main.run() | /app/main.go:20 +0x1d | main.main() | /app/main.go:10 +0x25",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewProductionConfig()
+			config.StackTraceSeparator = tt.separator
+			config.StackTraceMode = tt.mode
+			logger, buf := newTestLogger(config)
+
+			logger.RequestHTTP("http", HTTPRequestInfo{}, WithHTTPResponse(500, 1, "", WithError("boom", stack)))
+			logger.Error("failed")
+
+			lines := decodeLines(t, buf)
+			e := lines[0]["data"].(map[string]interface{})["http_response"].(map[string]interface{})["error"].(map[string]interface{})
+			if e["stack_trace"] != tt.want {
+				t.Errorf("stack_trace = %q, want %q", e["stack_trace"], tt.want)
+			}
+			if frames, _ := e["frames"].([]interface{}); tt.mode == StackTraceBoth && len(frames) != 2 {
+				t.Errorf("frames = %v, want 2", e["frames"])
+			}
+
+			stacktrace, _ := lines[1]["stacktrace"].(string)
+			if stacktrace == "" {
+				t.Fatal("no stacktrace on the error log")
+			}
+			if got := strings.Contains(stacktrace, "\n"); got != tt.wantLines {
+				t.Errorf("stacktrace = %q, want line breaks %v", stacktrace, tt.wantLines)
+			}
+			if !tt.wantLines && !strings.Contains(stacktrace, tt.separator) {
+				t.Errorf("stacktrace = %q, want joined with %q", stacktrace, tt.separator)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"reflect"
+	"strings"
+)
+
+// StructFields returns a LogField for every field of the struct v tagged with `log`.
+//
+// The key is the name given in the log tag, or the json tag name (then the Go
+// field name) when the tag is `log:"true"`. Adding ",omitempty" skips the field
+// when it holds its zero value:
+//
+//	type Order struct {
+//		ID     int    `json:"id" log:"true"`
+//		Status string `log:"order_status,omitempty"`
+//		Secret string
+//	}
+func StructFields(v interface{}) []LogField {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []LogField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag, ok := sf.Tag.Lookup("log")
+		if !ok || sf.PkgPath != "" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" || name == "false" {
+			continue
+		}
+
+		value := rv.Field(i)
+		if opts == "omitempty" && value.IsZero() {
+			continue
+		}
+
+		fields = append(fields, Any(structFieldKey(sf, name), value.Interface()))
+	}
+
+	return fields
+}
+
+func structFieldKey(sf reflect.StructField, name string) string {
+	if name != "" && name != "true" {
+		return name
+	}
+
+	if jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+		return jsonName
+	}
+	return sf.Name
+}
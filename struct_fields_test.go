@@ -0,0 +1,71 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+type loggableOrder struct {
+	ID       int    `json:"id" log:"true"`
+	Status   string `log:"order_status,omitempty"`
+	Customer string `log:"true"`
+	Note     string `json:"note" log:"true,omitempty"`
+	Secret   string `json:"secret"`
+	Skipped  string `log:"-"`
+	internal string `log:"true"`
+}
+
+func TestStructFields(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want []LogField
+	}{
+		{
+			name: "Tagged struct",
+			v:    loggableOrder{ID: 1, Status: "paid", Customer: "doge", Note: "fragile", Secret: "s3cr3t", internal: "x"},
+			want: []LogField{
+				{Key: "id", Value: 1},
+				{Key: "order_status", Value: "paid"},
+				{Key: "Customer", Value: "doge"},
+				{Key: "note", Value: "fragile"},
+			},
+		},
+		{
+			name: "Zero values are skipped with omitempty",
+			v:    &loggableOrder{ID: 2},
+			want: []LogField{
+				{Key: "id", Value: 2},
+				{Key: "Customer", Value: ""},
+			},
+		},
+		{
+			name: "Nil pointer",
+			v:    (*loggableOrder)(nil),
+			want: nil,
+		},
+		{
+			name: "Not a struct",
+			v:    "hello",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StructFields(tt.v); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StructFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructFieldsInLog(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	logger.Info("order created", StructFields(loggableOrder{ID: 1, Secret: "s3cr3t"}))
+
+	payload := decodeLines(t, buf)[0]["data"].(map[string]interface{})["application"].(map[string]interface{})
+	want := map[string]interface{}{"id": float64(1), "Customer": ""}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("payload = %v, want %v", payload, want)
+	}
+}
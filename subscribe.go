@@ -0,0 +1,270 @@
+package slog
+
+import (
+	"encoding/json"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SubscriberBufferSize is the number of entries buffered for each subscriber,
+// entries emitted while a subscriber's buffer is full are dropped for that subscriber.
+const SubscriberBufferSize = 256
+
+// Entry is a log entry as it was written by the logger.
+type Entry struct {
+	Level     string                 `json:"-"`
+	Timestamp time.Time              `json:"-"`
+	Message   string                 `json:"-"`
+	Caller    string                 `json:"-"`
+	AppName   string                 `json:"app_name"`
+	Version   string                 `json:"version"`
+	LogType   string                 `json:"log_type"`
+	Alert     AlertLevel             `json:"alert"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// Subscribe returns a channel receiving every entry written from now on and a
+// func to unsubscribe which closes the channel. The channel is bounded by
+// SubscriberBufferSize, a slow consumer misses the entries emitted while its
+// buffer is full rather than blocking the logger.
+func (s *SukiLogger) Subscribe() (<-chan Entry, func()) {
+	return s.subscribers.subscribe()
+}
+
+// Subscription is a subscription of SubscribeAlerts.
+type Subscription struct {
+	ch          chan Entry
+	dropped     int64
+	alertsOnly  bool
+	unsubscribe func()
+}
+
+// Entries receives the entries, it is closed by Unsubscribe.
+func (s *Subscription) Entries() <-chan Entry {
+	return s.ch
+}
+
+// Dropped is the number of entries missed while the buffer was full.
+func (s *Subscription) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+func (s *Subscription) Unsubscribe() {
+	s.unsubscribe()
+}
+
+// SubscribeAlerts subscribes to the entries written with alert set, e.g. to
+// forward them to a pager. The other entries are skipped before being decoded,
+// so the subscription costs nothing to the logs which do not alert. Like
+// Subscribe, its buffer holds SubscriberBufferSize entries, the entries written
+// while it is full are dropped and counted.
+func (s *SukiLogger) SubscribeAlerts() *Subscription {
+	return s.subscribers.add(true)
+}
+
+// Capture runs fn and returns the entries written while it ran, in addition
+// to the normal output. Unlike Subscribe no entry is dropped, the entries written
+// by other goroutines in the meantime are captured too.
+func (s *SukiLogger) Capture(fn func()) []Entry {
+	c := s.subscribers.collect()
+	defer s.subscribers.stopCollecting(c)
+
+	fn()
+	return c.take()
+}
+
+type subscribers struct {
+	mu       sync.RWMutex
+	channels map[*Subscription]struct{}
+	// collectors are the entries being captured by Capture.
+	collectors map[*collector]struct{}
+}
+
+type collector struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (c *collector) add(entry Entry) {
+	c.mu.Lock()
+	c.entries = append(c.entries, entry)
+	c.mu.Unlock()
+}
+
+func (c *collector) take() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.entries
+	c.entries = nil
+	return entries
+}
+
+func (h *subscribers) collect() *collector {
+	c := &collector{}
+
+	h.mu.Lock()
+	if h.collectors == nil {
+		h.collectors = make(map[*collector]struct{})
+	}
+	h.collectors[c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+func (h *subscribers) stopCollecting(c *collector) {
+	h.mu.Lock()
+	delete(h.collectors, c)
+	h.mu.Unlock()
+}
+
+func (h *subscribers) subscribe() (<-chan Entry, func()) {
+	sub := h.add(false)
+	return sub.ch, sub.unsubscribe
+}
+
+func (h *subscribers) add(alertsOnly bool) *Subscription {
+	sub := &Subscription{ch: make(chan Entry, SubscriberBufferSize), alertsOnly: alertsOnly}
+
+	h.mu.Lock()
+	if h.channels == nil {
+		h.channels = make(map[*Subscription]struct{})
+	}
+	h.channels[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	sub.unsubscribe = func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.channels, sub)
+			close(sub.ch)
+			h.mu.Unlock()
+		})
+	}
+	return sub
+}
+
+// wants reports whether an entry, alerting or not, has a subscriber.
+func (h *subscribers) wants(alerting bool) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.collectors) > 0 {
+		return true
+	}
+	for sub := range h.channels {
+		if alerting || !sub.alertsOnly {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *subscribers) publish(entry Entry) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.channels {
+		if sub.alertsOnly && entry.Alert < LevelAlert {
+			continue
+		}
+		select {
+		case sub.ch <- entry:
+		default:
+			atomic.AddInt64(&sub.dropped, 1)
+		}
+	}
+	for c := range h.collectors {
+		c.add(entry)
+	}
+}
+
+func (h *subscribers) active() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.channels) > 0 || len(h.collectors) > 0
+}
+
+// subscriberCore is the zapcore.Core fanning written entries out to the subscribers.
+type subscriberCore struct {
+	zapcore.LevelEnabler
+	hub    *subscribers
+	fields []zapcore.Field
+	// prefix is the Config.FieldPrefix stripped from the field names.
+	prefix string
+}
+
+func newSubscriberCore(level zapcore.LevelEnabler, hub *subscribers, prefix string) zapcore.Core {
+	return &subscriberCore{LevelEnabler: level, hub: hub, prefix: prefix}
+}
+
+func (c *subscriberCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &clone
+}
+
+func (c *subscriberCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) && c.hub.active() {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *subscriberCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.hub.wants(c.alerting(fields)) {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range append(append([]zapcore.Field{}, c.fields...), fields...) {
+		f.AddTo(enc)
+	}
+
+	encoded := enc.Fields
+	if c.prefix != "" {
+		encoded = make(map[string]interface{}, len(enc.Fields))
+		for k, v := range enc.Fields {
+			encoded[strings.TrimPrefix(k, c.prefix)] = v
+		}
+	}
+
+	b, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return err
+	}
+	entry.Level = ent.Level.String()
+	entry.Timestamp = ent.Time
+	entry.Message = ent.Message
+	if ent.Caller.Defined {
+		entry.Caller = ent.Caller.TrimmedPath()
+	}
+
+	c.hub.publish(entry)
+	return nil
+}
+
+// alerting reports whether the alert field of an entry is set, without encoding it.
+func (c *subscriberCore) alerting(fields []zapcore.Field) bool {
+	for _, group := range [][]zapcore.Field{fields, c.fields} {
+		for _, f := range group {
+			if f.Key == c.prefix+"alert" {
+				return f.Integer >= int64(LevelAlert)
+			}
+		}
+	}
+	return false
+}
+
+func (c *subscriberCore) Sync() error {
+	return nil
+}
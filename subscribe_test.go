@@ -0,0 +1,143 @@
+package slog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	entries, unsubscribe := logger.Subscribe()
+
+	logger.Info("hello", Any("order_id", 1), WithTracing("trace", "span"))
+
+	select {
+	case entry := <-entries:
+		if entry.Level != "info" || entry.Message != "hello" || entry.LogType != "application" || entry.AppName != "application" {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+		if entry.Timestamp.IsZero() || entry.Caller == "" {
+			t.Errorf("timestamp or caller missing from %+v", entry)
+		}
+		if entry.Data["application"].(map[string]interface{})["order_id"] != float64(1) {
+			t.Errorf("data = %v", entry.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no entry received")
+	}
+
+	unsubscribe()
+	unsubscribe()
+	logger.Info("after unsubscribe")
+	if _, ok := <-entries; ok {
+		t.Error("channel is still open after unsubscribe")
+	}
+}
+
+func TestSubscribeDropsForSlowConsumer(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+	entries, unsubscribe := logger.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < SubscriberBufferSize+10; i++ {
+		logger.Info(fmt.Sprintf("hello %d", i))
+	}
+
+	if got := len(entries); got != SubscriberBufferSize {
+		t.Errorf("buffered %d entries, want %d", got, SubscriberBufferSize)
+	}
+	if got := len(decodeLines(t, buf)); got != SubscriberBufferSize+10 {
+		t.Errorf("wrote %d lines, want %d", got, SubscriberBufferSize+10)
+	}
+}
+
+type countingMarshaler struct {
+	calls int
+}
+
+func (c *countingMarshaler) MarshalJSON() ([]byte, error) {
+	c.calls++
+	return []byte(`"counted"`), nil
+}
+
+func TestSubscribeAlerts(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	sub := logger.SubscribeAlerts()
+	defer sub.Unsubscribe()
+
+	// The field counts its encodings, the entry not alerting is only encoded for the output.
+	encodings := &countingMarshaler{}
+	logger.Info("not alerting", Any("counted", encodings))
+	logger.Error("alerting", WithOption(LogOption{Alert: LevelAlert}))
+
+	select {
+	case entry := <-sub.Entries():
+		if entry.Message != "alerting" || entry.Alert != LevelAlert {
+			t.Errorf("unexpected entry %+v", entry)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no entry received")
+	}
+	if len(sub.Entries()) != 0 {
+		t.Errorf("%d more entries received, want the alerting one only", len(sub.Entries()))
+	}
+	if encodings.calls != 1 {
+		t.Errorf("the entry not alerting was encoded %d times, want once for the output", encodings.calls)
+	}
+}
+
+func TestSubscribeAlertsCountsDrops(t *testing.T) {
+	logger, _ := newTestLogger(NewProductionConfig())
+	sub := logger.SubscribeAlerts()
+	defer sub.Unsubscribe()
+
+	for i := 0; i < SubscriberBufferSize+10; i++ {
+		logger.Error(fmt.Sprintf("alert %d", i), WithOption(LogOption{Alert: LevelAlert}))
+	}
+
+	if got := len(sub.Entries()); got != SubscriberBufferSize {
+		t.Errorf("buffered %d entries, want %d", got, SubscriberBufferSize)
+	}
+	if got := sub.Dropped(); got != 10 {
+		t.Errorf("Dropped() = %d, want 10", got)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	logger.Info("before")
+	var inner []Entry
+	entries := logger.Capture(func() {
+		logger.Info("inside", Any("order_id", 1))
+		inner = logger.Capture(func() {
+			logger.Warn("nested")
+		})
+		for i := 0; i < 2*SubscriberBufferSize; i++ {
+			logger.Info(fmt.Sprintf("inside %d", i))
+		}
+	})
+	logger.Info("after")
+
+	if len(entries) != 2+2*SubscriberBufferSize {
+		t.Fatalf("captured %d entries, want %d", len(entries), 2+2*SubscriberBufferSize)
+	}
+	if entries[0].Message != "inside" || entries[0].Level != "info" || entries[0].Data["application"].(map[string]interface{})["order_id"] != float64(1) {
+		t.Errorf("first entry = %+v, want info inside with order_id 1", entries[0])
+	}
+	if entries[1].Message != "nested" || entries[len(entries)-1].Message != fmt.Sprintf("inside %d", 2*SubscriberBufferSize-1) {
+		t.Errorf("entries = %v ... %v, want nested then the loop in order", entries[1], entries[len(entries)-1])
+	}
+	if len(inner) != 1 || inner[0].Message != "nested" {
+		t.Errorf("nested capture = %+v, want the nested entry only", inner)
+	}
+
+	lines := decodeLines(t, buf)
+	if len(lines) != 4+2*SubscriberBufferSize || lines[0]["message"] != "before" || lines[len(lines)-1]["message"] != "after" {
+		t.Errorf("output has %d lines, want every entry written normally", len(lines))
+	}
+	if n := len(logger.subscribers.collectors); n != 0 {
+		t.Errorf("%d collectors left after Capture, want 0", n)
+	}
+}
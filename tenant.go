@@ -0,0 +1,28 @@
+package slog
+
+import (
+	"context"
+)
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying the tenant id, written as
+// data.tenant_id by the logs given ctx as an argument, e.g.
+//
+//	slog.L().Info("order created", ctx)
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFromContext returns the tenant id carried by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok
+}
+
+// contextArgs adds the fields carried by ctx to data.
+func contextArgs(data map[string]interface{}, ctx context.Context) {
+	if id, ok := TenantFromContext(ctx); ok {
+		data["tenant_id"] = id
+	}
+}
@@ -0,0 +1,66 @@
+package slog
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextWithTenant(t *testing.T) {
+	tenantCtx := ContextWithTenant(context.Background(), "tenant-1")
+
+	tests := []struct {
+		name       string
+		emit       func(s *SukiLogger)
+		wantTenant interface{}
+	}{
+		{
+			name: "Application log with tenant",
+			emit: func(s *SukiLogger) {
+				s.Info("order created", tenantCtx)
+			},
+			wantTenant: "tenant-1",
+		},
+		{
+			name: "Application log without tenant",
+			emit: func(s *SukiLogger) {
+				s.Info("order created", context.Background())
+			},
+			wantTenant: nil,
+		},
+		{
+			name: "Application log without context",
+			emit: func(s *SukiLogger) {
+				s.Info("order created")
+			},
+			wantTenant: nil,
+		},
+		{
+			name: "Event log with tenant",
+			emit: func(s *SukiLogger) {
+				s.Event("order created", WithEvent("order", ActionCreate, ResultSuccess, nil, "order-1"), tenantCtx)
+			},
+			wantTenant: "tenant-1",
+		},
+		{
+			name: "Database log with tenant",
+			emit: func(s *SukiLogger) {
+				db := s.SQLDB(newSQLTestDB(func(string) error { return nil }), SQLOption{System: "postgresql"})
+				defer db.Close()
+				db.ExecContext(tenantCtx, "DELETE FROM orders")
+			},
+			wantTenant: "tenant-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger(NewProductionConfig())
+
+			tt.emit(logger)
+
+			data := decodeLines(t, buf)[0]["data"].(map[string]interface{})
+			if data["tenant_id"] != tt.wantTenant {
+				t.Errorf("tenant_id = %v, want %v", data["tenant_id"], tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"time"
+)
+
+// Timer measures a step to be written as a timer log by Stop.
+type Timer struct {
+	logger *SukiLogger
+	name   string
+	start  time.Time
+}
+
+// TimerInfo is the data.timer of a timer log, Duration is in milliseconds.
+type TimerInfo struct {
+	Name            string  `json:"name"`
+	Duration        float64 `json:"duration"`
+	DurationAnomaly string  `json:"duration_anomaly,omitempty"`
+}
+
+// StartTimer starts a Timer for the step name, e.g.
+//
+//	t := slog.L().StartTimer("db-call")
+//	defer t.Stop(slog.WithTracing(traceID, spanID))
+func (s *SukiLogger) StartTimer(name string) *Timer {
+	return &Timer{logger: s, name: name, start: s.clock().Now()}
+}
+
+// Stop writes a timer log with the time elapsed since StartTimer, it is written
+// at warn level with slow: true above the SlowThresholdMs of "timer".
+func (t *Timer) Stop(args ...interface{}) {
+	duration := toMillis(t.logger.clock().Now().Sub(t.start))
+
+	data := make(map[string]interface{})
+	alertLevel := t.logger.requestArgs(data, args)
+	anomaly := t.logger.checkDuration(&duration)
+	data["timer"] = TimerInfo{Name: t.name, Duration: duration, DurationAnomaly: anomaly}
+	level := t.logger.durationLevel("timer", duration, data)
+
+	if ce := t.logger.sampledLogger("timer", alertLevel, level, t.name, data).Check(level, t.name); ce != nil {
+		withCaller(ce, args)
+		ce.Write(t.logger.envelope(alertLevel, data)...)
+	}
+}
@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"github.com/Sellsuki/sellsuki-go-logger/slogtest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.SlowThresholdMs = map[string]float64{"timer": 1000}
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	timer := logger.StartTimer("db-call")
+	clock.Advance(10 * time.Millisecond)
+	timer.Stop(WithTracing("trace-1", "span-1"))
+
+	line := decodeLines(t, buf)[0]
+	if line["log_type"] != "timer" || line["message"] != "db-call" || line["level"] != "info" {
+		t.Errorf("log_type, message, level = %v, %v, %v, want timer, db-call, info", line["log_type"], line["message"], line["level"])
+	}
+
+	if !strings.Contains(line["caller"].(string), "timer_test.go") {
+		t.Errorf("caller = %v, want the caller of Stop", line["caller"])
+	}
+
+	data := line["data"].(map[string]interface{})
+	timerInfo := data["timer"].(map[string]interface{})
+	if timerInfo["name"] != "db-call" {
+		t.Errorf("name = %v, want db-call", timerInfo["name"])
+	}
+	if timerInfo["duration"] != float64(10) {
+		t.Errorf("duration = %v, want 10", timerInfo["duration"])
+	}
+	if data["tracing"].(map[string]interface{})["trace_id"] != "trace-1" {
+		t.Errorf("tracing = %v, want trace_id trace-1", data["tracing"])
+	}
+}
+
+func TestTimerSlow(t *testing.T) {
+	clock := slogtest.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := NewProductionConfig()
+	c.SlowThresholdMs = map[string]float64{"timer": 1}
+	c.Clock = clock
+	logger, buf := newTestLogger(c)
+
+	timer := logger.StartTimer("slow-call")
+	clock.Advance(5 * time.Millisecond)
+	timer.Stop()
+
+	line := decodeLines(t, buf)[0]
+	if line["level"] != "warn" || line["data"].(map[string]interface{})["slow"] != true {
+		t.Errorf("level = %v, data = %v, want warn and slow", line["level"], line["data"])
+	}
+}
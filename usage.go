@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"errors"
+	"go.uber.org/zap/zapcore"
+)
+
+var ErrNegativeQuantity = errors.New("slog: usage quantity must not be negative")
+
+type UsageInfo struct {
+	Account  string  `json:"account"`
+	Metric   string  `json:"metric"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+func WithUsage(account string, metric string, quantity float64, unit string) UsageInfo {
+	return UsageInfo{
+		Account:  account,
+		Metric:   metric,
+		Quantity: quantity,
+		Unit:     unit,
+	}
+}
+
+// Usage writes a usage log for billing, it returns ErrNegativeQuantity
+// without writing anything when the quantity is negative.
+func (s SukiLogger) Usage(info UsageInfo, args ...interface{}) error {
+	if info.Quantity < 0 {
+		return ErrNegativeQuantity
+	}
+
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+	data["usage"] = info
+
+	if ce := s.sampledLogger("usage", alertLevel, zapcore.InfoLevel, "usage", data).Check(zapcore.InfoLevel, "usage"); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+	return nil
+}
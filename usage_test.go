@@ -0,0 +1,39 @@
+package slog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUsage(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	if err := logger.Usage(WithUsage("shop-1", "orders.created", 3, "order"), WithTracing("trace", "span")); err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+
+	line := decodeLines(t, buf)[0]
+	if line["log_type"] != "usage" || line["message"] != "usage" {
+		t.Errorf("log_type = %v, message = %v", line["log_type"], line["message"])
+	}
+	want := map[string]interface{}{
+		"account":  "shop-1",
+		"metric":   "orders.created",
+		"quantity": float64(3),
+		"unit":     "order",
+	}
+	if got := line["data"].(map[string]interface{})["usage"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("usage = %v, want %v", got, want)
+	}
+}
+
+func TestUsageNegativeQuantity(t *testing.T) {
+	logger, buf := newTestLogger(NewProductionConfig())
+
+	if err := logger.Usage(WithUsage("shop-1", "storage", -1, "GB")); err != ErrNegativeQuantity {
+		t.Errorf("Usage() error = %v, want %v", err, ErrNegativeQuantity)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("negative usage was written: %s", buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+package slog
+
+import (
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+// WebhookInfo is the data.webhook of a webhook log, a delivery attempt of an
+// outbound webhook. Duration is in milliseconds.
+type WebhookInfo struct {
+	Endpoint  string `json:"endpoint"`
+	EventType string `json:"event_type"`
+	// Attempt is the number of the delivery attempt, starting at 1.
+	Attempt         int     `json:"attempt"`
+	Status          int     `json:"status"`
+	Duration        float64 `json:"duration"`
+	DurationAnomaly string  `json:"duration_anomaly,omitempty"`
+	// Headers are the request headers, the signing secrets and signatures are redacted.
+	Headers map[string]string `json:"headers,omitempty"`
+	Error   ErrorInfo         `json:"error"`
+}
+
+func WithWebhook(endpoint string, eventType string, attempt int, status int, duration float64, error ...ErrorInfo) WebhookInfo {
+	var e ErrorInfo
+	if len(error) > 0 {
+		e = error[0]
+	}
+	return WebhookInfo{
+		Endpoint:  endpoint,
+		EventType: eventType,
+		Attempt:   attempt,
+		Status:    status,
+		Duration:  duration,
+		Error:     e,
+	}
+}
+
+// Webhook writes a webhook log of a delivery attempt, at warn level when it
+// failed with an error or a non-2xx status.
+func (s SukiLogger) Webhook(info WebhookInfo, args ...interface{}) {
+	data := make(map[string]interface{})
+	alertLevel := s.requestArgs(data, args)
+
+	info.Endpoint = redactUserinfo(info.Endpoint)
+	info.Headers = s.config.RedactionRules.active("").redactHeaders(redactSigningHeaders(info.Headers))
+	info.Error = s.formatError(info.Error)
+	info.DurationAnomaly = s.checkDuration(&info.Duration)
+	data["webhook"] = info
+
+	level := zapcore.InfoLevel
+	if info.Error.Name != "" || info.Status < 200 || info.Status >= 300 {
+		level = zapcore.WarnLevel
+	}
+
+	if ce := s.sampledLogger("webhook", alertLevel, level, "webhook "+info.EventType, data).Check(level, "webhook "+info.EventType); ce != nil {
+		withCaller(ce, args)
+		ce.Write(s.envelope(alertLevel, data)...)
+	}
+}
+
+// signingHeaderWords are found in the names of the headers carrying webhook
+// signing secrets or signatures, e.g. X-Hub-Signature-256 or X-Shopify-Hmac-Sha256.
+var signingHeaderWords = []string{"signature", "secret", "hmac", "authorization", "token"}
+
+// redactSigningHeaders returns a copy of headers with the signing headers masked.
+func redactSigningHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+
+	result := make(map[string]string, len(headers))
+	for k, v := range headers {
+		result[k] = v
+		name := strings.ToLower(k)
+		for _, word := range signingHeaderWords {
+			if strings.Contains(name, word) {
+				result[k] = redactedValue
+				break
+			}
+		}
+	}
+	return result
+}